@@ -0,0 +1,56 @@
+package main
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/lib/apikey"
+	"Events-Service/internal/lib/logger/sl"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+const issueKeyUsage = "usage: events-service [-config path] issue-key <user_id>"
+
+// runIssueKey выполняет подкоманду issue-key: выдает ключ API существующему пользователю,
+// например созданному до появления ключей, и печатает его в stdout.
+func runIssueKey(ctx context.Context, log *slog.Logger, cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New(issueKeyUsage)
+	}
+
+	userID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || userID <= 0 {
+		return errors.New(issueKeyUsage)
+	}
+
+	if cfg.Storage.Type != config.StorageDatabase {
+		return fmt.Errorf("issue-key requires storage type %q, got %q", config.StorageDatabase, cfg.Storage.Type)
+	}
+
+	storage, err := setupDatabase(ctx, log, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to init storage: %w", err)
+	}
+	defer func() {
+		if err := storage.Close(); err != nil {
+			log.Error("failed to close database", sl.Err(err))
+		}
+	}()
+
+	key, record, err := apikey.Generate()
+	if err != nil {
+		return err
+	}
+
+	keyID, err := storage.CreateAPIKey(ctx, userID, record)
+	if err != nil {
+		return err
+	}
+
+	log.Info("api key issued", slog.Int64("user_id", userID), slog.Int64("key_id", keyID), slog.String("prefix", record.Prefix))
+	fmt.Println(key)
+
+	return nil
+}
@@ -2,15 +2,40 @@ package main
 
 import (
 	"Events-Service/internal/config"
+	"Events-Service/internal/http-server/handlers/apiKey"
+	"Events-Service/internal/http-server/handlers/calendar"
+	"Events-Service/internal/http-server/handlers/event/batch"
 	"Events-Service/internal/http-server/handlers/event/createEvent"
 	"Events-Service/internal/http-server/handlers/event/deleteEvent"
+	"Events-Service/internal/http-server/handlers/event/eventHistory"
 	"Events-Service/internal/http-server/handlers/event/getEvents"
+	"Events-Service/internal/http-server/handlers/event/listTags"
+	"Events-Service/internal/http-server/handlers/event/listTrash"
+	"Events-Service/internal/http-server/handlers/event/respondEvent"
+	"Events-Service/internal/http-server/handlers/event/restoreEvent"
+	"Events-Service/internal/http-server/handlers/event/searchEvents"
 	"Events-Service/internal/http-server/handlers/event/updateEvent"
+	"Events-Service/internal/http-server/handlers/share"
+	"Events-Service/internal/http-server/handlers/token"
 	"Events-Service/internal/http-server/handlers/user"
+	"Events-Service/internal/http-server/handlers/webhook"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/http-server/middleware/deadline"
 	"Events-Service/internal/http-server/middleware/mwlogger"
+	"Events-Service/internal/lib/jwtauth"
 	"Events-Service/internal/lib/logger/handlers/slogpretty"
 	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/lib/netguard"
+	"Events-Service/internal/outbox"
+	"Events-Service/internal/reminder"
+	"Events-Service/internal/storage/memory"
 	"Events-Service/internal/storage/postgres"
+	"Events-Service/internal/storage/sqlite"
+	"Events-Service/internal/storage/trash"
+	webhooksender "Events-Service/internal/webhook"
+	"context"
+	"flag"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"log/slog"
@@ -26,19 +51,109 @@ const (
 	envProd  = "prod"
 )
 
+type eventStorage interface {
+	user.UserCreator
+	user.UserGetter
+	user.UserUpdater
+	user.UserDeleter
+	apiKey.KeyCreator
+	apiKey.KeyLister
+	apiKey.KeyRevoker
+	calendar.CalendarCreator
+	calendar.CalendarLister
+	calendar.CalendarUpdater
+	calendar.CalendarDeleter
+	share.CalendarSharer
+	share.ShareLister
+	share.ShareRevoker
+	webhook.WebhookCreator
+	webhook.WebhookLister
+	webhook.WebhookUpdater
+	webhook.WebhookDeleter
+	webhook.DeliveryLister
+	auth.Authenticator
+	token.Sessions
+	createEvent.CreateEvent
+	updateEvent.UpdateEvent
+	deleteEvent.DeleteEvent
+	respondEvent.RespondEvent
+	getEvents.GetEvents
+	getEvents.ListEvents
+	listTags.ListTags
+	searchEvents.SearchEvents
+	listTrash.ListTrash
+	restoreEvent.RestoreEvent
+	eventHistory.EventHistory
+	batch.Batch
+	trash.Purger
+	reminder.Firer
+	webhooksender.Store
+	outbox.Relayer
+	Close() error
+}
+
 func main() {
 	cfg := config.MustLoad()
 
 	log := setupLogger(cfg.Env)
 
-	log.Info("Starting events service", slog.String("env", cfg.Env))
+	// Ожидание базы при старте прерывается сигналом остановки.
+	startCtx, stopStart := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stopStart()
+
+	if args := flag.Args(); len(args) > 0 {
+		switch args[0] {
+		case "migrate":
+			if err := runMigrate(startCtx, log, cfg, args[1:]); err != nil {
+				log.Error("migration failed", sl.Err(err))
+				os.Exit(1)
+			}
+		case "issue-key":
+			if err := runIssueKey(startCtx, log, cfg, args[1:]); err != nil {
+				log.Error("failed to issue api key", sl.Err(err))
+				os.Exit(1)
+			}
+		default:
+			log.Error("unknown command", slog.String("command", args[0]))
+			os.Exit(2)
+		}
+
+		return
+	}
+
+	log.Info("Starting events service", slog.String("env", cfg.Env), slog.String("storage", cfg.Storage.Type))
 	log.Debug("debug messages are enabled")
 
-	storage, err := postgres.InitDB(cfg)
+	storage, err := setupStorage(startCtx, log, cfg)
 	if err != nil {
 		log.Error("failed to init storage", sl.Err(err))
 		os.Exit(1)
 	}
+	stopStart()
+
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	go trash.Run(purgeCtx, log, storage, cfg.Trash)
+
+	notifier, err := reminder.NewNotifier(log, cfg.Reminders)
+	if err != nil {
+		log.Error("failed to init reminders notifier", sl.Err(err))
+		os.Exit(1)
+	}
+	remindCtx, stopReminders := context.WithCancel(context.Background())
+	go reminder.Run(remindCtx, log, storage, notifier, cfg.Reminders)
+
+	sendCtx, stopWebhooks := context.WithCancel(context.Background())
+	go webhooksender.Run(sendCtx, log, storage, cfg.Webhooks)
+
+	publisher, err := outbox.NewPublisher(cfg.Outbox)
+	if err != nil {
+		log.Error("failed to init outbox publisher", sl.Err(err))
+		os.Exit(1)
+	}
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	go outbox.Run(relayCtx, log, storage, publisher, cfg.Outbox)
+
+	webhookGuard := netguard.New(cfg.Webhooks.AllowPrivateNetworks)
 
 	router := chi.NewRouter()
 
@@ -47,13 +162,64 @@ func main() {
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.URLFormat)
 
-	router.Post("/create_user", user.New(log, storage))
-	router.Post("/create_event", createEvent.New(log, storage))
-	router.Post("/update_event", updateEvent.New(log, storage))
-	router.Post("/delete_event", deleteEvent.New(log, storage))
-	router.Get("/events_for_day", getEvents.ByDay(log, storage))
-	router.Get("/events_for_week", getEvents.ByWeek(log, storage))
-	router.Get("/events_for_month", getEvents.ByMonth(log, storage))
+	timeouts := cfg.Storage.Timeouts
+
+	var verifier auth.TokenVerifier
+	if cfg.JWT.Enabled() {
+		tokens, err := jwtauth.New(cfg.JWT)
+		if err != nil {
+			log.Error("invalid jwt config", sl.Err(err))
+			os.Exit(1)
+		}
+		verifier = tokens
+
+		router.With(deadline.New(timeouts.Write)).Post("/token", token.New(log, storage, tokens))
+	}
+	authenticate := auth.New(log, storage, verifier)
+
+	// Регистрация и обмен ключа на токены — единственные запросы без авторизации.
+	router.With(deadline.New(timeouts.Write)).Post("/create_user", user.New(log, storage))
+
+	router.Group(func(r chi.Router) {
+		r.Use(deadline.New(timeouts.Write), authenticate)
+
+		r.Post("/update_user", user.Update(log, storage))
+		r.Post("/delete_user", user.Delete(log, storage))
+		r.Post("/create_calendar", calendar.New(log, storage))
+		r.Post("/update_calendar", calendar.Update(log, storage))
+		r.Post("/delete_calendar", calendar.Delete(log, storage))
+		r.Post("/share_calendar", share.New(log, storage))
+		r.Post("/revoke_share", share.Revoke(log, storage))
+		r.Post("/create_webhook", webhook.New(log, storage, webhookGuard))
+		r.Post("/update_webhook", webhook.Update(log, storage, webhookGuard))
+		r.Post("/delete_webhook", webhook.Delete(log, storage))
+		r.Post("/create_event", createEvent.New(log, storage))
+		r.Post("/update_event", updateEvent.New(log, storage))
+		r.Post("/delete_event", deleteEvent.New(log, storage))
+		r.Post("/rsvp", respondEvent.New(log, storage))
+		r.Post("/restore_event", restoreEvent.New(log, storage))
+		r.Post("/create_api_key", apiKey.New(log, storage))
+		r.Post("/revoke_api_key", apiKey.Revoke(log, storage))
+	})
+	router.Group(func(r chi.Router) {
+		r.Use(deadline.New(timeouts.Read), authenticate)
+
+		r.Get("/get_user", user.Get(log, storage))
+		r.Get("/api_keys", apiKey.List(log, storage))
+		r.Get("/calendars", calendar.List(log, storage))
+		r.Get("/shares", share.List(log, storage))
+		r.Get("/webhooks", webhook.List(log, storage))
+		r.Get("/webhook_deliveries", webhook.Deliveries(log, storage))
+		r.Get("/events_for_day", getEvents.ByDay(log, storage))
+		r.Get("/events_for_week", getEvents.ByWeek(log, storage))
+		r.Get("/events_for_month", getEvents.ByMonth(log, storage))
+		r.Get("/events", getEvents.List(log, storage))
+		r.Get("/tags", listTags.New(log, storage))
+		r.Get("/search_events", searchEvents.New(log, storage))
+		r.Get("/trash", listTrash.New(log, storage))
+		r.Get("/event_history", eventHistory.New(log, storage))
+	})
+	router.With(deadline.New(timeouts.Batch), authenticate).Post("/batch", batch.New(log, storage))
 
 	log.Info("starting server", slog.String("address", cfg.HTTPServer.Address))
 
@@ -76,11 +242,27 @@ func main() {
 
 	log.Info("server stopped", slog.String("signal", sign.String()))
 
+	stopPurge()
+	stopReminders()
+	stopWebhooks()
+	stopRelay()
+
 	if err = storage.Close(); err != nil {
 		log.Error("failed to close database", slog.String("error", err.Error()))
 	}
 
-	log.Info("postgres connection closed")
+	log.Info("storage closed", slog.String("type", cfg.Storage.Type))
+}
+
+func setupStorage(ctx context.Context, log *slog.Logger, cfg *config.Config) (eventStorage, error) {
+	switch cfg.Storage.Type {
+	case config.StorageDatabase:
+		return setupDatabase(ctx, log, cfg)
+	case config.StorageMemory:
+		return memory.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage type: %q", cfg.Storage.Type)
+	}
 }
 
 func setupLogger(env string) *slog.Logger {
@@ -109,3 +291,22 @@ func setupPrettySlog() *slog.Logger {
 
 	return slog.New(handler)
 }
+
+func setupDatabase(ctx context.Context, log *slog.Logger, cfg *config.Config) (eventStorage, error) {
+	switch cfg.Database.Driver {
+	case config.DriverPostgres:
+		storage, err := postgres.InitDB(ctx, cfg, log)
+		if err != nil {
+			return nil, err
+		}
+		return storage, nil
+	case config.DriverSQLite:
+		storage, err := sqlite.InitDB(ctx, cfg, log)
+		if err != nil {
+			return nil, err
+		}
+		return storage, nil
+	default:
+		return nil, fmt.Errorf("unknown database driver: %q", cfg.Database.Driver)
+	}
+}
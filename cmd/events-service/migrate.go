@@ -0,0 +1,103 @@
+package main
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/storage/migrations"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = "usage: events-service [-config path] migrate up|down|status"
+
+type migratable interface {
+	Migrator() (*migrations.Migrator, error)
+}
+
+// runMigrate выполняет подкоманду migrate: up применяет все новые миграции,
+// down откатывает последнюю, status печатает состояние каждой версии.
+func runMigrate(ctx context.Context, log *slog.Logger, cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+
+	if cfg.Storage.Type != config.StorageDatabase {
+		return fmt.Errorf("migrations require storage type %q, got %q", config.StorageDatabase, cfg.Storage.Type)
+	}
+
+	// Подкоманда сама управляет схемой, поэтому автоматическая миграция при подключении отключается.
+	dbCfg := *cfg
+	dbCfg.Database.AutoMigrate = false
+
+	storage, err := setupDatabase(ctx, log, &dbCfg)
+	if err != nil {
+		return fmt.Errorf("failed to init storage: %w", err)
+	}
+	defer func() {
+		if err := storage.Close(); err != nil {
+			log.Error("failed to close database", sl.Err(err))
+		}
+	}()
+
+	m, ok := storage.(migratable)
+	if !ok {
+		return fmt.Errorf("driver %q does not support migrations", cfg.Database.Driver)
+	}
+
+	migrator, err := m.Migrator()
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			log.Info("migration applied", slog.Int64("version", migration.Version), slog.String("name", migration.Name))
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Info("no pending migrations")
+		}
+	case "down":
+		migration, err := migrator.Down()
+		if err != nil {
+			return err
+		}
+		if migration == nil {
+			log.Info("no migrations to roll back")
+			return nil
+		}
+		log.Info("migration rolled back", slog.Int64("version", migration.Version), slog.String("name", migration.Name))
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		printMigrationStatus(statuses)
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	return nil
+}
+
+func printMigrationStatus(statuses []migrations.Status) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, st := range statuses {
+		appliedAt := "pending"
+		if st.Applied {
+			appliedAt = st.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", st.Version, st.Name, appliedAt)
+	}
+	w.Flush()
+}
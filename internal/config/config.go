@@ -7,19 +7,86 @@ import (
 	"time"
 )
 
+const (
+	StorageDatabase = "database"
+	StorageMemory   = "memory"
+
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+
+	NotifierLog  = "log"
+	NotifierFile = "file"
+
+	PublisherMemory = "memory"
+	PublisherFile   = "file"
+)
+
 type Config struct {
 	Env        string     `yaml:"env" env-default:"local"`
+	Storage    Storage    `yaml:"storage"`
 	Database   Database   `yaml:"database"`
 	HTTPServer HTTPServer `yaml:"http_server"`
+	Trash      Trash      `yaml:"trash"`
+	Reminders  Reminders  `yaml:"reminders"`
+	Webhooks   Webhooks   `yaml:"webhooks"`
+	Outbox     Outbox     `yaml:"outbox"`
+	JWT        JWT        `yaml:"jwt"`
+}
+
+// Storage определяет, где сервис хранит данные.
+// "database" — в базе данных из секции database, "memory" — в памяти процесса (данные теряются при перезапуске).
+type Storage struct {
+	Type     string          `yaml:"type" env-default:"database"`
+	Timeouts StorageTimeouts `yaml:"timeouts"`
 }
 
+// StorageTimeouts ограничивают время, которое запрос проводит в хранилище: Read — выборки, поиск
+// и история, Write — создание, изменение, удаление и восстановление, Batch — пакеты /batch.
+// Сроки стоит держать меньше http_server.timeout, нулевой срок снимает ограничение.
+type StorageTimeouts struct {
+	Read  time.Duration `yaml:"read" env-default:"2s"`
+	Write time.Duration `yaml:"write" env-default:"3s"`
+	Batch time.Duration `yaml:"batch" env-default:"3s"`
+}
+
+// Database описывает подключение к базе данных.
+// Для драйвера "sqlite" из параметров подключения используется только Path, остальные
+// относятся к "postgres". Pool и ConnectRetry действуют для обоих драйверов.
 type Database struct {
+	Driver   string `yaml:"driver" env-default:"postgres"`
+	Path     string `yaml:"path" env-default:"events.db"`
 	Host     string `yaml:"host" env-default:"localhost"`
 	Port     int    `yaml:"port" env-default:"5432"`
 	User     string `yaml:"user" env-default:"postgres"`
 	Password string `yaml:"password" env-required:"true"`
 	DBName   string `yaml:"dbname" env-required:"true"`
 	SSLMode  string `yaml:"sslmode" env-default:"disable"`
+
+	// AutoMigrate применяет недостающие миграции схемы при старте сервиса.
+	AutoMigrate bool `yaml:"auto_migrate" env-default:"true"`
+
+	Pool         Pool  `yaml:"pool"`
+	ConnectRetry Retry `yaml:"connect_retry"`
+}
+
+// Pool настраивает пул соединений database/sql. Нулевые MaxOpenConns, ConnMaxLifetime
+// и ConnMaxIdleTime снимают ограничение, нулевой MaxIdleConns оставляет значение по умолчанию (2).
+type Pool struct {
+	MaxOpenConns    int           `yaml:"max_open_conns" env-default:"20"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env-default:"10"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env-default:"30m"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env-default:"5m"`
+}
+
+// Retry описывает повтор с экспоненциальной задержкой: после n-й неудачной попытки
+// сервис ждет InitialInterval*Multiplier^(n-1), но не больше MaxInterval, и сдвигает задержку
+// на случайную долю до Jitter в обе стороны. MaxAttempts — число попыток вместе с первой.
+type Retry struct {
+	MaxAttempts     int           `yaml:"max_attempts" env-default:"10"`
+	InitialInterval time.Duration `yaml:"initial_interval" env-default:"500ms"`
+	MaxInterval     time.Duration `yaml:"max_interval" env-default:"10s"`
+	Multiplier      float64       `yaml:"multiplier" env-default:"2"`
+	Jitter          float64       `yaml:"jitter" env-default:"0.2"`
 }
 
 type HTTPServer struct {
@@ -28,6 +95,83 @@ type HTTPServer struct {
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
 }
 
+// Trash описывает хранение удаленных событий.
+// События в корзине старше Retention окончательно удаляются раз в PurgeInterval,
+// нулевой Retention отключает очистку.
+type Trash struct {
+	Retention     time.Duration `yaml:"retention" env-default:"720h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+}
+
+// Reminders настраивает напоминания о событиях. Раз в PollInterval планировщик забирает до BatchSize
+// сработавших напоминаний и передает их Notifier: "log" пишет их в журнал сервиса, "file" дописывает
+// строками JSON в File. Напоминание, опоздавшее больше чем на ExpireAfter (например, пока сервис
+// не работал), не доставляется, а только отмечается в журнале; нулевой ExpireAfter доставляет все
+// пропущенные напоминания. Нулевой PollInterval отключает планировщик.
+type Reminders struct {
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"30s"`
+	BatchSize    int           `yaml:"batch_size" env-default:"100"`
+	ExpireAfter  time.Duration `yaml:"expire_after" env-default:"1h"`
+	Notifier     string        `yaml:"notifier" env-default:"log"`
+	File         string        `yaml:"file" env-default:"reminders.log"`
+}
+
+// Webhooks настраивает доставку изменений событий на вебхуки пользователей. Раз в PollInterval
+// отправитель забирает до BatchSize доставок и отправляет каждую POST-запросом, который ждет
+// ответа не дольше Timeout (не положительный Timeout заменяется на 10s). Неудачная доставка
+// повторяется через RetryInterval, с каждой попыткой вдвое дольше, но не дольше MaxRetryInterval;
+// после MaxAttempts попыток доставка считается проваленной. Вебхук выключается после DisableAfter
+// неудачных попыток подряд, нулевой DisableAfter не выключает вебхуки. Нулевой PollInterval
+// отключает отправку. Адреса во внутренних сетях (loopback, частные, link-local) вебхукам
+// запрещены, AllowPrivateNetworks разрешает их для локальной разработки.
+type Webhooks struct {
+	PollInterval         time.Duration `yaml:"poll_interval" env-default:"5s"`
+	BatchSize            int           `yaml:"batch_size" env-default:"50"`
+	Timeout              time.Duration `yaml:"timeout" env-default:"10s"`
+	MaxAttempts          int           `yaml:"max_attempts" env-default:"8"`
+	RetryInterval        time.Duration `yaml:"retry_interval" env-default:"30s"`
+	MaxRetryInterval     time.Duration `yaml:"max_retry_interval" env-default:"1h"`
+	DisableAfter         int           `yaml:"disable_after" env-default:"20"`
+	AllowPrivateNetworks bool          `yaml:"allow_private_networks" env-default:"false"`
+}
+
+// Outbox настраивает публикацию уведомлений об изменениях, записанных в outbox вместе с самими
+// изменениями. Раз в PollInterval релей забирает до BatchSize записей по порядку и передает их
+// Publisher: "memory" хранит их в памяти процесса, "file" дописывает строками JSON в File.
+// Нулевой PollInterval отключает релей, записи при этом копятся в outbox.
+type Outbox struct {
+	PollInterval time.Duration `yaml:"poll_interval" env-default:"1s"`
+	BatchSize    int           `yaml:"batch_size" env-default:"100"`
+	Publisher    string        `yaml:"publisher" env-default:"file"`
+	File         string        `yaml:"file" env-default:"outbox.log"`
+}
+
+// JWT настраивает короткоживущие токены доступа, которые /token выдает в обмен на ключ API
+// или токен обновления. Токены подписываются HMAC-SHA256 ключом ActiveKey (по умолчанию первым
+// из SigningKeys) и проверяются ключом из заголовка kid. Чтобы сменить ключ, добавьте новый,
+// сделайте его активным и удалите старый, когда истекут подписанные им токены (AccessTTL).
+// Пустой SigningKeys отключает /token и вход по токенам.
+type JWT struct {
+	Issuer      string        `yaml:"issuer" env-default:"events-service"`
+	Audience    string        `yaml:"audience" env-default:"events-service"`
+	AccessTTL   time.Duration `yaml:"access_ttl" env-default:"15m"`
+	RefreshTTL  time.Duration `yaml:"refresh_ttl" env-default:"720h"`
+	Leeway      time.Duration `yaml:"leeway" env-default:"30s"`
+	ActiveKey   string        `yaml:"active_key"`
+	SigningKeys []SigningKey  `yaml:"signing_keys"`
+}
+
+// SigningKey — секрет HMAC не короче 32 байт и его ID для заголовка kid.
+type SigningKey struct {
+	ID     string `yaml:"id"`
+	Secret string `yaml:"secret"`
+}
+
+// Enabled сообщает, настроены ли ключи подписи.
+func (j JWT) Enabled() bool {
+	return len(j.SigningKeys) > 0
+}
+
 func MustLoad() *Config {
 	path := fetchConfigPath()
 	if path == "" {
@@ -0,0 +1,65 @@
+package apiKey
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/apikey"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+// Response возвращает новый ключ в открытом виде — повторно получить его нельзя.
+type Response struct {
+	response.Response
+	KeyId  int64  `json:"key_id"`
+	ApiKey string `json:"api_key"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=KeyCreator
+type KeyCreator interface {
+	CreateAPIKey(ctx context.Context, userID int64, key models.APIKey) (int64, error)
+}
+
+// New выдает текущему пользователю дополнительный ключ, например для ротации.
+func New(log *slog.Logger, keyCreator KeyCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.apiKey.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		key, record, err := apikey.Generate()
+		if err != nil {
+			log.Error("failed to generate api key", sl.Err(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to create api key"))
+
+			return
+		}
+
+		keyId, err := keyCreator.CreateAPIKey(r.Context(), userID, record)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to create api key")
+
+			return
+		}
+
+		log.Info("api key created", slog.Int64("id", keyId), slog.String("prefix", record.Prefix))
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			KeyId:    keyId,
+			ApiKey:   key,
+		})
+	}
+}
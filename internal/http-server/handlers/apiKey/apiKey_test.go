@@ -0,0 +1,109 @@
+package apiKey_test
+
+import (
+	"Events-Service/internal/http-server/handlers/apiKey"
+	"Events-Service/internal/http-server/handlers/apiKey/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/apikey"
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNew_Success(t *testing.T) {
+	mockService := new(mocks.KeyCreator)
+
+	var issued models.APIKey
+	mockService.On("CreateAPIKey", mock.Anything, int64(1), mock.AnythingOfType("models.APIKey")).
+		Run(func(args mock.Arguments) {
+			issued = args.Get(2).(models.APIKey)
+		}).Return(int64(5), nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/create_api_key", nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	apiKey.New(testLogger, mockService).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp apiKey.Response
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, int64(5), resp.KeyId)
+	assert.Equal(t, apikey.Hash(resp.ApiKey), issued.Hash)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestList_Success(t *testing.T) {
+	created := time.Date(2025, 8, 5, 10, 0, 0, 0, time.UTC)
+	revoked := created.Add(time.Hour)
+
+	mockService := new(mocks.KeyLister)
+	mockService.On("ListAPIKeys", mock.Anything, int64(1)).Return([]models.APIKey{
+		{ID: 1, UserID: 1, Prefix: "evs_aaaaaaaa", CreatedAt: created, RevokedAt: &revoked},
+		{ID: 2, UserID: 1, Prefix: "evs_bbbbbbbb", CreatedAt: created},
+	}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/api_keys", nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	apiKey.List(testLogger, mockService).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp apiKey.ListResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, []apiKey.KeyResponse{
+		{KeyId: 1, Prefix: "evs_aaaaaaaa", CreatedAt: "2025-08-05T10:00:00Z", RevokedAt: "2025-08-05T11:00:00Z"},
+		{KeyId: 2, Prefix: "evs_bbbbbbbb", CreatedAt: "2025-08-05T10:00:00Z"},
+	}, resp.Keys)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRevoke(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		callsStore bool
+		wantStatus int
+	}{
+		{name: "revoked", body: `{"key_id": 2}`, callsStore: true, wantStatus: http.StatusOK},
+		{name: "key not found", body: `{"key_id": 2}`, serviceErr: storage.ErrAPIKeyNotFound, callsStore: true, wantStatus: http.StatusNotFound},
+		{name: "missing key id", body: `{}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.KeyRevoker)
+			if tt.callsStore {
+				mockService.On("RevokeAPIKey", mock.Anything, int64(1), int64(2)).Return(tt.serviceErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/revoke_api_key", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			apiKey.Revoke(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package apiKey
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/models"
+	"context"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// KeyResponse — ключ без секрета: по Prefix пользователь узнает свой ключ.
+// RevokedAt пуст у действующих ключей.
+type KeyResponse struct {
+	KeyId     int64  `json:"key_id"`
+	Prefix    string `json:"prefix"`
+	CreatedAt string `json:"created_at"`
+	RevokedAt string `json:"revoked_at,omitempty"`
+}
+
+type ListResponse struct {
+	response.Response
+	Keys []KeyResponse `json:"keys"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=KeyLister
+type KeyLister interface {
+	ListAPIKeys(ctx context.Context, userID int64) ([]models.APIKey, error)
+}
+
+func List(log *slog.Logger, keyLister KeyLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.apiKey.List"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		keys, err := keyLister.ListAPIKeys(r.Context(), userID)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to list api keys")
+
+			return
+		}
+
+		log.Info("got api keys", slog.Int("count", len(keys)))
+
+		responseKeys(w, r, keys)
+	}
+}
+
+func responseKeys(w http.ResponseWriter, r *http.Request, keys []models.APIKey) {
+	responseKeys := make([]KeyResponse, 0, len(keys))
+	for _, key := range keys {
+		resp := KeyResponse{
+			KeyId:     key.ID,
+			Prefix:    key.Prefix,
+			CreatedAt: key.CreatedAt.UTC().Format(time.RFC3339),
+		}
+		if key.RevokedAt != nil {
+			resp.RevokedAt = key.RevokedAt.UTC().Format(time.RFC3339)
+		}
+		responseKeys = append(responseKeys, resp)
+	}
+
+	render.JSON(w, r, ListResponse{
+		Response: response.OK(),
+		Keys:     responseKeys,
+	})
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "Events-Service/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// KeyCreator is an autogenerated mock type for the KeyCreator type
+type KeyCreator struct {
+	mock.Mock
+}
+
+// CreateAPIKey provides a mock function with given fields: ctx, userID, key
+func (_m *KeyCreator) CreateAPIKey(ctx context.Context, userID int64, key models.APIKey) (int64, error) {
+	ret := _m.Called(ctx, userID, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAPIKey")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.APIKey) (int64, error)); ok {
+		return rf(ctx, userID, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.APIKey) int64); ok {
+		r0 = rf(ctx, userID, key)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.APIKey) error); ok {
+		r1 = rf(ctx, userID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewKeyCreator creates a new instance of KeyCreator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewKeyCreator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *KeyCreator {
+	mock := &KeyCreator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,72 @@
+package apiKey
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+type RevokeRequest struct {
+	KeyId int64 `json:"key_id" validate:"required"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=KeyRevoker
+type KeyRevoker interface {
+	RevokeAPIKey(ctx context.Context, userID, keyID int64) error
+}
+
+// Revoke отзывает ключ текущего пользователя. Отозвать можно и ключ, которым подписан запрос.
+func Revoke(log *slog.Logger, keyRevoker KeyRevoker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.apiKey.Revoke"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req RevokeRequest
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		if err = keyRevoker.RevokeAPIKey(r.Context(), userID, req.KeyId); err != nil {
+			response.StorageError(w, r, log, err, "failed to revoke api key")
+
+			return
+		}
+
+		log.Info("api key revoked", slog.Int64("id", req.KeyId))
+
+		render.JSON(w, r, response.OK())
+	}
+}
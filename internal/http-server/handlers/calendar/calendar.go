@@ -0,0 +1,117 @@
+package calendar
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Request — новый календарь. Color — цвет в виде #rrggbb (по умолчанию models.DefaultCalendarColor),
+// TimeZone — часовой пояс IANA (по умолчанию часовой пояс пользователя).
+type Request struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Color    string `json:"color,omitempty" validate:"omitempty,hexcolor"`
+	TimeZone string `json:"time_zone,omitempty"`
+}
+
+type Response struct {
+	response.Response
+	CalendarId int64 `json:"calendar_id"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=CalendarCreator
+type CalendarCreator interface {
+	CreateCalendar(ctx context.Context, calendar models.Calendar) (int64, error)
+}
+
+func New(log *slog.Logger, calendarCreator CalendarCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.calendar.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		calendar, err := newCalendar(req.Name, req.Color, req.TimeZone)
+		if err != nil {
+			log.Error("invalid calendar", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+
+			return
+		}
+		calendar.UserID = userID
+		if calendar.Color == "" {
+			calendar.Color = models.DefaultCalendarColor
+		}
+
+		calendarId, err := calendarCreator.CreateCalendar(r.Context(), calendar)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to create calendar")
+
+			return
+		}
+
+		log.Info("calendar created", slog.Int64("id", calendarId))
+
+		render.JSON(w, r, Response{
+			Response:   response.OK(),
+			CalendarId: calendarId,
+		})
+	}
+}
+
+// newCalendar проверяет часовой пояс календаря и приводит цвет к нижнему регистру.
+// Пустые поля остаются пустыми.
+func newCalendar(name, color, timeZone string) (models.Calendar, error) {
+	if timeZone != "" {
+		if _, err := time.LoadLocation(timeZone); err != nil {
+			return models.Calendar{}, fmt.Errorf("unknown time zone %q", timeZone)
+		}
+	}
+
+	return models.Calendar{
+		Name:     strings.TrimSpace(name),
+		Color:    strings.ToLower(color),
+		TimeZone: timeZone,
+	}, nil
+}
@@ -0,0 +1,185 @@
+package calendar_test
+
+import (
+	"Events-Service/internal/http-server/handlers/calendar"
+	"Events-Service/internal/http-server/handlers/calendar/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantCalendar models.Calendar
+		serviceErr   error
+		callsStore   bool
+		wantStatus   int
+	}{
+		{
+			name:         "created",
+			body:         `{"name": "Work", "color": "#FF8800", "time_zone": "Europe/Moscow"}`,
+			wantCalendar: models.Calendar{UserID: 1, Name: "Work", Color: "#ff8800", TimeZone: "Europe/Moscow"},
+			callsStore:   true,
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "default color",
+			body:         `{"name": "Work"}`,
+			wantCalendar: models.Calendar{UserID: 1, Name: "Work", Color: models.DefaultCalendarColor},
+			callsStore:   true,
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "name taken",
+			body:         `{"name": "Work"}`,
+			wantCalendar: models.Calendar{UserID: 1, Name: "Work", Color: models.DefaultCalendarColor},
+			serviceErr:   storage.ErrCalendarExists,
+			callsStore:   true,
+			wantStatus:   http.StatusConflict,
+		},
+		{name: "missing name", body: `{"color": "#ff8800"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid color", body: `{"name": "Work", "color": "orange"}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown time zone", body: `{"name": "Work", "time_zone": "Mars/Olympus"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.CalendarCreator)
+			if tt.callsStore {
+				mockService.On("CreateCalendar", mock.Anything, tt.wantCalendar).Return(int64(3), tt.serviceErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/create_calendar", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			calendar.New(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus == http.StatusOK {
+				var resp calendar.Response
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, int64(3), resp.CalendarId)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestList_Success(t *testing.T) {
+	created := time.Date(2025, 8, 5, 10, 0, 0, 0, time.UTC)
+
+	mockService := new(mocks.CalendarLister)
+	mockService.On("ListCalendars", mock.Anything, int64(1)).Return([]models.Calendar{
+		{ID: 1, UserID: 1, Name: "Default", Color: "#4a90e2", TimeZone: "UTC", Default: true, CreatedAt: created},
+		{ID: 2, UserID: 1, Name: "Work", Color: "#ff8800", TimeZone: "Europe/Moscow", CreatedAt: created},
+	}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/calendars", nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	calendar.List(testLogger, mockService).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp calendar.ListResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, []calendar.CalendarResponse{
+		{CalendarId: 1, Name: "Default", Color: "#4a90e2", TimeZone: "UTC", Default: true, CreatedAt: "2025-08-05T10:00:00Z"},
+		{CalendarId: 2, Name: "Work", Color: "#ff8800", TimeZone: "Europe/Moscow", CreatedAt: "2025-08-05T10:00:00Z"},
+	}, resp.Calendars)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		callsStore bool
+		wantStatus int
+	}{
+		{name: "updated", body: `{"calendar_id": 2, "color": "#00AA00"}`, callsStore: true, wantStatus: http.StatusOK},
+		{name: "calendar not found", body: `{"calendar_id": 2, "color": "#00AA00"}`, serviceErr: storage.ErrCalendarNotFound, callsStore: true, wantStatus: http.StatusNotFound},
+		{name: "missing calendar id", body: `{"color": "#00aa00"}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown time zone", body: `{"calendar_id": 2, "time_zone": "Mars/Olympus"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.CalendarUpdater)
+			if tt.callsStore {
+				mockService.On("UpdateCalendar", mock.Anything, models.Calendar{ID: 2, UserID: 1, Color: "#00aa00"}).
+					Return(models.Calendar{ID: 2, UserID: 1, Name: "Work", Color: "#00aa00", TimeZone: "UTC"}, tt.serviceErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/update_calendar", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			calendar.Update(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus == http.StatusOK {
+				var resp calendar.UpdateResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, "#00aa00", resp.Calendar.Color)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		callsStore bool
+		wantStatus int
+	}{
+		{name: "deleted", body: `{"calendar_id": 2}`, callsStore: true, wantStatus: http.StatusOK},
+		{name: "calendar not found", body: `{"calendar_id": 2}`, serviceErr: storage.ErrCalendarNotFound, callsStore: true, wantStatus: http.StatusNotFound},
+		{name: "default calendar", body: `{"calendar_id": 2}`, serviceErr: storage.ErrDefaultCalendar, callsStore: true, wantStatus: http.StatusConflict},
+		{name: "missing calendar id", body: `{}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.CalendarDeleter)
+			if tt.callsStore {
+				mockService.On("DeleteCalendar", mock.Anything, int64(1), int64(2)).Return(tt.serviceErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/delete_calendar", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			calendar.Delete(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
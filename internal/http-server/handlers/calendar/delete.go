@@ -0,0 +1,73 @@
+package calendar
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+type DeleteRequest struct {
+	CalendarId int64 `json:"calendar_id" validate:"required"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=CalendarDeleter
+type CalendarDeleter interface {
+	DeleteCalendar(ctx context.Context, userID, calendarID int64) error
+}
+
+// Delete удаляет календарь вместе с его событиями: в корзину они не попадают.
+// Календарь по умолчанию удалить нельзя.
+func Delete(log *slog.Logger, calendarDeleter CalendarDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.calendar.Delete"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req DeleteRequest
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		if err = calendarDeleter.DeleteCalendar(r.Context(), userID, req.CalendarId); err != nil {
+			response.StorageError(w, r, log, err, "failed to delete calendar")
+
+			return
+		}
+
+		log.Info("calendar deleted", slog.Int64("id", req.CalendarId))
+
+		render.JSON(w, r, response.OK())
+	}
+}
@@ -0,0 +1,76 @@
+package calendar
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/models"
+	"context"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type CalendarResponse struct {
+	CalendarId int64  `json:"calendar_id"`
+	Name       string `json:"name"`
+	Color      string `json:"color"`
+	TimeZone   string `json:"time_zone"`
+	Default    bool   `json:"default"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type ListResponse struct {
+	response.Response
+	Calendars []CalendarResponse `json:"calendars"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=CalendarLister
+type CalendarLister interface {
+	ListCalendars(ctx context.Context, userID int64) ([]models.Calendar, error)
+}
+
+func List(log *slog.Logger, calendarLister CalendarLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.calendar.List"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		calendars, err := calendarLister.ListCalendars(r.Context(), userID)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to list calendars")
+
+			return
+		}
+
+		log.Info("got calendars", slog.Int("count", len(calendars)))
+
+		responseCalendars := make([]CalendarResponse, 0, len(calendars))
+		for _, calendar := range calendars {
+			responseCalendars = append(responseCalendars, toResponse(calendar))
+		}
+
+		render.JSON(w, r, ListResponse{
+			Response:  response.OK(),
+			Calendars: responseCalendars,
+		})
+	}
+}
+
+func toResponse(calendar models.Calendar) CalendarResponse {
+	return CalendarResponse{
+		CalendarId: calendar.ID,
+		Name:       calendar.Name,
+		Color:      calendar.Color,
+		TimeZone:   calendar.TimeZone,
+		Default:    calendar.Default,
+		CreatedAt:  calendar.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CalendarDeleter is an autogenerated mock type for the CalendarDeleter type
+type CalendarDeleter struct {
+	mock.Mock
+}
+
+// DeleteCalendar provides a mock function with given fields: ctx, userID, calendarID
+func (_m *CalendarDeleter) DeleteCalendar(ctx context.Context, userID int64, calendarID int64) error {
+	ret := _m.Called(ctx, userID, calendarID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCalendar")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userID, calendarID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCalendarDeleter creates a new instance of CalendarDeleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCalendarDeleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CalendarDeleter {
+	mock := &CalendarDeleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
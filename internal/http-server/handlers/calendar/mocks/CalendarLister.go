@@ -0,0 +1,59 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "Events-Service/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CalendarLister is an autogenerated mock type for the CalendarLister type
+type CalendarLister struct {
+	mock.Mock
+}
+
+// ListCalendars provides a mock function with given fields: ctx, userID
+func (_m *CalendarLister) ListCalendars(ctx context.Context, userID int64) ([]models.Calendar, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCalendars")
+	}
+
+	var r0 []models.Calendar
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.Calendar, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.Calendar); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Calendar)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCalendarLister creates a new instance of CalendarLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCalendarLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CalendarLister {
+	mock := &CalendarLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
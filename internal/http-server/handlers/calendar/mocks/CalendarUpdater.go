@@ -0,0 +1,57 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "Events-Service/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CalendarUpdater is an autogenerated mock type for the CalendarUpdater type
+type CalendarUpdater struct {
+	mock.Mock
+}
+
+// UpdateCalendar provides a mock function with given fields: ctx, _a1
+func (_m *CalendarUpdater) UpdateCalendar(ctx context.Context, _a1 models.Calendar) (models.Calendar, error) {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCalendar")
+	}
+
+	var r0 models.Calendar
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Calendar) (models.Calendar, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Calendar) models.Calendar); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Get(0).(models.Calendar)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Calendar) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCalendarUpdater creates a new instance of CalendarUpdater. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCalendarUpdater(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CalendarUpdater {
+	mock := &CalendarUpdater{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,98 @@
+package calendar
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+// UpdateRequest меняет только переданные поля календаря. Часовой пояс событий,
+// уже созданных в календаре, не меняется.
+type UpdateRequest struct {
+	CalendarId int64  `json:"calendar_id" validate:"required"`
+	Name       string `json:"name,omitempty" validate:"max=100"`
+	Color      string `json:"color,omitempty" validate:"omitempty,hexcolor"`
+	TimeZone   string `json:"time_zone,omitempty"`
+}
+
+// UpdateResponse возвращает календарь после изменения.
+type UpdateResponse struct {
+	response.Response
+	Calendar CalendarResponse `json:"calendar"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=CalendarUpdater
+type CalendarUpdater interface {
+	UpdateCalendar(ctx context.Context, calendar models.Calendar) (models.Calendar, error)
+}
+
+func Update(log *slog.Logger, calendarUpdater CalendarUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.calendar.Update"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req UpdateRequest
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		patch, err := newCalendar(req.Name, req.Color, req.TimeZone)
+		if err != nil {
+			log.Error("invalid calendar", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+
+			return
+		}
+		patch.ID = req.CalendarId
+		patch.UserID = userID
+
+		calendar, err := calendarUpdater.UpdateCalendar(r.Context(), patch)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to update calendar")
+
+			return
+		}
+
+		log.Info("calendar updated", slog.Int64("id", calendar.ID))
+
+		render.JSON(w, r, UpdateResponse{
+			Response: response.OK(),
+			Calendar: toResponse(calendar),
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package batch
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/eventtime"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/lib/rrule"
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+// Request — пакет операций над событиями пользователя, выполняемый в одной транзакции.
+// По умолчанию пакет применяется целиком или не применяется вовсе; с ContinueOnError
+// ошибка операции отменяет только ее.
+type Request struct {
+	ContinueOnError bool        `json:"continue_on_error,omitempty"`
+	Operations      []Operation `json:"operations" validate:"required,min=1,max=1000,dive"`
+}
+
+// Operation повторяет запросы /create_event, /update_event и /delete_event, вид операции задает Op.
+type Operation struct {
+	Op         string   `json:"op" validate:"required,oneof=create update delete"`
+	EventId    int64    `json:"event_id,omitempty" validate:"required_unless=Op create"`
+	Date       string   `json:"date,omitempty"`
+	Text       string   `json:"text,omitempty" validate:"required_unless=Op delete"`
+	StartTime  string   `json:"start_time,omitempty" validate:"required_with=EndTime"`
+	EndTime    string   `json:"end_time,omitempty" validate:"required_with=StartTime"`
+	TimeZone   string   `json:"time_zone,omitempty"`
+	Recurrence string   `json:"rrule,omitempty"`
+	Tags       []string `json:"tags,omitempty" validate:"dive,max=64"`
+	Attendees  []int64  `json:"attendees,omitempty" validate:"dive,min=1"`
+	Reminders  []int    `json:"reminders,omitempty" validate:"dive,min=0,max=40320"`
+	CalendarId int64    `json:"calendar_id,omitempty"`
+	// Scope и OccurrenceDate ограничивают изменение или удаление серии, как в /update_event.
+	Scope           string `json:"scope,omitempty"`
+	OccurrenceDate  string `json:"occurrence_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	ExpectedVersion int64  `json:"expected_version,omitempty" validate:"omitempty,min=1"`
+}
+
+// Result — результат операции с индексом Index. Code — HTTP-код, которым ответил бы
+// отдельный запрос; 424 означает, что операция не применена из-за ошибки другой операции.
+type Result struct {
+	Index   int    `json:"index"`
+	Status  string `json:"status"`
+	Code    int    `json:"code"`
+	EventId int64  `json:"event_id,omitempty"`
+	Version int64  `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Response struct {
+	response.Response
+	Results []Result `json:"results"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=Batch
+type Batch interface {
+	ApplyBatch(ctx context.Context, ops []models.BatchOperation, continueOnError bool) ([]models.BatchResult, error)
+	GetCalendar(ctx context.Context, userID, calendarID int64) (models.Calendar, error)
+}
+
+func New(log *slog.Logger, batch Batch) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.event.batch.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Int64("user", userID), slog.Int("operations", len(req.Operations)))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		// Как и в /create_event, новое событие со временем без часового пояса получает часовой пояс календаря.
+		timeZones := make(map[int64]string)
+		ops := make([]models.BatchOperation, len(req.Operations))
+		for i, o := range req.Operations {
+			if o.Op == string(models.ActionCreate) && o.StartTime != "" && o.TimeZone == "" {
+				if _, ok := timeZones[o.CalendarId]; !ok {
+					calendar, err := batch.GetCalendar(r.Context(), userID, o.CalendarId)
+					if err != nil {
+						response.StorageError(w, r, log, err, "failed to apply batch")
+
+						return
+					}
+					timeZones[o.CalendarId] = calendar.TimeZone
+				}
+				o.TimeZone = timeZones[o.CalendarId]
+			}
+
+			if ops[i], err = o.operation(userID); err != nil {
+				log.Error("invalid operation", slog.Int("index", i), sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, response.Error(fmt.Sprintf("operation %d: %v", i, err)))
+
+				return
+			}
+		}
+
+		results, err := batch.ApplyBatch(r.Context(), ops, req.ContinueOnError)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to apply batch")
+
+			return
+		}
+
+		resp := Response{Response: response.OK(), Results: make([]Result, len(results))}
+		failed := 0
+		for i, res := range results {
+			resp.Results[i] = result(i, res)
+			if res.Err == nil || errors.Is(res.Err, storage.ErrBatchAborted) {
+				continue
+			}
+			failed++
+			log.Info("batch operation failed", slog.Int("index", i), sl.Err(res.Err))
+
+			if !req.ContinueOnError {
+				// Откаченный пакет отвечает кодом операции, из-за которой он откачен.
+				resp.Response = response.Error(fmt.Sprintf("operation %d: %s", i, resp.Results[i].Error))
+				render.Status(r, resp.Results[i].Code)
+			}
+		}
+
+		log.Info("batch applied", slog.Int("operations", len(results)), slog.Int("failed", failed))
+
+		render.JSON(w, r, resp)
+	}
+}
+
+// operation проверяет операцию запроса так же, как отдельные обработчики событий.
+func (o Operation) operation(userID int64) (models.BatchOperation, error) {
+	op := models.BatchOperation{
+		Action:         models.Action(o.Op),
+		Event:          models.Event{ID: o.EventId, UserID: userID, CalendarID: o.CalendarId, Version: o.ExpectedVersion},
+		OccurrenceDate: o.OccurrenceDate,
+	}
+
+	if op.Action != models.ActionCreate {
+		scope, err := models.ParseScope(o.Scope)
+		if err == nil && scope != models.ScopeAll && o.OccurrenceDate == "" {
+			err = errors.New("occurrence_date is required for scope " + string(scope))
+		}
+		if err != nil {
+			return models.BatchOperation{}, err
+		}
+		op.Scope = scope
+	}
+	if op.Action == models.ActionDelete {
+		return op, nil
+	}
+
+	timing, err := eventtime.Parse(o.Date, o.StartTime, o.EndTime, o.TimeZone)
+	if err != nil {
+		return models.BatchOperation{}, err
+	}
+	if o.Recurrence != "" {
+		rule, err := rrule.Parse(o.Recurrence)
+		if err != nil {
+			return models.BatchOperation{}, errors.New("invalid rrule: " + err.Error())
+		}
+		op.Event.Recurrence = rule.String()
+	}
+
+	op.Event.Date = timing.Date
+	op.Event.Text = o.Text
+	op.Event.StartsAt = timing.StartsAt
+	op.Event.EndsAt = timing.EndsAt
+	op.Event.TimeZone = timing.TimeZone
+	op.Event.Tags = models.NormalizeTags(o.Tags)
+	op.Event.Attendees = models.Invite(o.Attendees)
+	op.Event.Reminders = models.RemindBefore(o.Reminders)
+
+	return op, nil
+}
+
+func result(index int, res models.BatchResult) Result {
+	if res.Err == nil {
+		return Result{
+			Index:   index,
+			Status:  response.StatusOK,
+			Code:    http.StatusOK,
+			EventId: res.EventID,
+			Version: res.Version,
+		}
+	}
+
+	code, msg := http.StatusFailedDependency, "not applied: batch aborted"
+	if !errors.Is(res.Err, storage.ErrBatchAborted) {
+		code, msg = response.StorageStatus(res.Err, "failed to apply operation")
+	}
+
+	return Result{Index: index, Status: response.StatusError, Code: code, Error: msg}
+}
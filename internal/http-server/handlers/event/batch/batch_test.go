@@ -0,0 +1,201 @@
+package batch_test
+
+import (
+	"Events-Service/internal/http-server/handlers/event/batch"
+	"Events-Service/internal/http-server/middleware/auth"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Events-Service/internal/http-server/handlers/event/batch/mocks"
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func serve(t *testing.T, mockService *mocks.Batch, req batch.Request) (*httptest.ResponseRecorder, batch.Response) {
+	t.Helper()
+
+	body, _ := json.Marshal(req)
+	r := httptest.NewRequest(http.MethodPost, "/batch", bytes.NewReader(body))
+	r = r.WithContext(auth.WithUserID(r.Context(), 1))
+	r.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	batch.New(testLogger, mockService).ServeHTTP(rr, r)
+
+	var resp batch.Response
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+
+	return rr, resp
+}
+
+func TestNew_Success(t *testing.T) {
+	mockService := new(mocks.Batch)
+	mockService.On("ApplyBatch", mock.Anything, mock.MatchedBy(func(ops []models.BatchOperation) bool {
+		return len(ops) == 3 &&
+			ops[0].Action == models.ActionCreate && ops[0].Event.UserID == 1 && ops[0].Event.Date == "2025-08-05" &&
+			ops[1].Action == models.ActionUpdate && ops[1].Event.ID == 7 && ops[1].Event.Version == 2 &&
+			ops[1].Scope == models.ScopeThis && ops[1].OccurrenceDate == "2025-08-12" &&
+			ops[2].Action == models.ActionDelete && ops[2].Event.ID == 8 && ops[2].Scope == models.ScopeAll
+	}), false).Return([]models.BatchResult{
+		{EventID: 10, Version: 1},
+		{EventID: 7, Version: 3},
+		{EventID: 8},
+	}, nil).Once()
+
+	rr, resp := serve(t, mockService, batch.Request{
+		Operations: []batch.Operation{
+			{Op: "create", Date: "2025-08-05", Text: "New event"},
+			{Op: "update", EventId: 7, Date: "2025-08-13", Text: "Moved", Scope: "this", OccurrenceDate: "2025-08-12", ExpectedVersion: 2},
+			{Op: "delete", EventId: 8},
+		},
+	})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "OK", resp.Status)
+	assert.Equal(t, []batch.Result{
+		{Index: 0, Status: "OK", Code: http.StatusOK, EventId: 10, Version: 1},
+		{Index: 1, Status: "OK", Code: http.StatusOK, EventId: 7, Version: 3},
+		{Index: 2, Status: "OK", Code: http.StatusOK, EventId: 8},
+	}, resp.Results)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_CalendarTimeZone(t *testing.T) {
+	mockService := new(mocks.Batch)
+	mockService.On("GetCalendar", mock.Anything, int64(1), int64(3)).
+		Return(models.Calendar{ID: 3, UserID: 1, TimeZone: "Europe/Moscow"}, nil).Once()
+	mockService.On("ApplyBatch", mock.Anything, mock.MatchedBy(func(ops []models.BatchOperation) bool {
+		return len(ops) == 2 &&
+			ops[0].Event.CalendarID == 3 && ops[0].Event.TimeZone == "Europe/Moscow" &&
+			ops[1].Event.CalendarID == 3 && ops[1].Event.TimeZone == "Europe/Moscow"
+	}), false).Return([]models.BatchResult{
+		{EventID: 10, Version: 1},
+		{EventID: 11, Version: 1},
+	}, nil).Once()
+
+	rr, _ := serve(t, mockService, batch.Request{
+		Operations: []batch.Operation{
+			{Op: "create", StartTime: "2025-08-05T14:00", EndTime: "2025-08-05T15:00", Text: "First", CalendarId: 3},
+			{Op: "create", StartTime: "2025-08-06T14:00", EndTime: "2025-08-06T15:00", Text: "Second", CalendarId: 3},
+		},
+	})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_Failures(t *testing.T) {
+	tests := []struct {
+		name            string
+		continueOnError bool
+		results         []models.BatchResult
+		wantStatus      int
+		wantCodes       []int
+	}{
+		{
+			name: "aborted",
+			results: []models.BatchResult{
+				{Err: storage.ErrBatchAborted},
+				{Err: storage.ErrVersionMismatch},
+			},
+			wantStatus: http.StatusPreconditionFailed,
+			wantCodes:  []int{http.StatusFailedDependency, http.StatusPreconditionFailed},
+		},
+		{
+			name:            "continue on error",
+			continueOnError: true,
+			results: []models.BatchResult{
+				{EventID: 10, Version: 1},
+				{Err: storage.ErrOccurrenceNotFound},
+			},
+			wantStatus: http.StatusOK,
+			wantCodes:  []int{http.StatusOK, http.StatusNotFound},
+		},
+		{
+			name:            "unexpected error",
+			continueOnError: true,
+			results: []models.BatchResult{
+				{Err: errors.New("db error")},
+				{EventID: 7, Version: 2},
+			},
+			wantStatus: http.StatusOK,
+			wantCodes:  []int{http.StatusInternalServerError, http.StatusOK},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.Batch)
+			mockService.On("ApplyBatch", mock.Anything, mock.Anything, tt.continueOnError).Return(tt.results, nil).Once()
+
+			rr, resp := serve(t, mockService, batch.Request{
+				ContinueOnError: tt.continueOnError,
+				Operations: []batch.Operation{
+					{Op: "create", Date: "2025-08-05", Text: "New event"},
+					{Op: "update", EventId: 7, Date: "2025-08-05", Text: "Updated"},
+				},
+			})
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			var codes []int
+			for _, res := range resp.Results {
+				codes = append(codes, res.Code)
+			}
+			assert.Equal(t, tt.wantCodes, codes)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNew_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations []batch.Operation
+	}{
+		{name: "no operations"},
+		{name: "unknown op", operations: []batch.Operation{{Op: "move", EventId: 1}}},
+		{name: "update without event", operations: []batch.Operation{{Op: "update", Date: "2025-08-05", Text: "Text"}}},
+		{name: "create without text", operations: []batch.Operation{{Op: "create", Date: "2025-08-05"}}},
+		{name: "invalid date", operations: []batch.Operation{{Op: "create", Date: "05.08.2025", Text: "Text"}}},
+		{name: "scope without occurrence", operations: []batch.Operation{{Op: "delete", EventId: 1, Scope: "this"}}},
+		{name: "invalid rrule", operations: []batch.Operation{{Op: "create", Date: "2025-08-05", Text: "Text", Recurrence: "FREQ=SOMETIMES"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.Batch)
+
+			rr, resp := serve(t, mockService, batch.Request{Operations: tt.operations})
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.Equal(t, "Error", resp.Status)
+			mockService.AssertNotCalled(t, "ApplyBatch", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestNew_StorageError(t *testing.T) {
+	mockService := new(mocks.Batch)
+	mockService.On("ApplyBatch", mock.Anything, mock.Anything, false).Return(nil, errors.New("connection refused")).Once()
+
+	rr, resp := serve(t, mockService, batch.Request{
+		Operations: []batch.Operation{{Op: "delete", EventId: 1}},
+	})
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, "failed to apply batch", resp.Error)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,87 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "Events-Service/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Batch is an autogenerated mock type for the Batch type
+type Batch struct {
+	mock.Mock
+}
+
+// ApplyBatch provides a mock function with given fields: ctx, ops, continueOnError
+func (_m *Batch) ApplyBatch(ctx context.Context, ops []models.BatchOperation, continueOnError bool) ([]models.BatchResult, error) {
+	ret := _m.Called(ctx, ops, continueOnError)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyBatch")
+	}
+
+	var r0 []models.BatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.BatchOperation, bool) ([]models.BatchResult, error)); ok {
+		return rf(ctx, ops, continueOnError)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.BatchOperation, bool) []models.BatchResult); ok {
+		r0 = rf(ctx, ops, continueOnError)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.BatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.BatchOperation, bool) error); ok {
+		r1 = rf(ctx, ops, continueOnError)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCalendar provides a mock function with given fields: ctx, userID, calendarID
+func (_m *Batch) GetCalendar(ctx context.Context, userID int64, calendarID int64) (models.Calendar, error) {
+	ret := _m.Called(ctx, userID, calendarID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCalendar")
+	}
+
+	var r0 models.Calendar
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (models.Calendar, error)); ok {
+		return rf(ctx, userID, calendarID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) models.Calendar); ok {
+		r0 = rf(ctx, userID, calendarID)
+	} else {
+		r0 = ret.Get(0).(models.Calendar)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userID, calendarID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBatch creates a new instance of Batch. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBatch(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Batch {
+	mock := &Batch{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
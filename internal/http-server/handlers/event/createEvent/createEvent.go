@@ -1,9 +1,14 @@
 package createEvent
 
 import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/etag"
 	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/eventtime"
 	"Events-Service/internal/lib/logger/sl"
-	"Events-Service/internal/storage"
+	"Events-Service/internal/lib/rrule"
+	"Events-Service/internal/models"
+	"context"
 	"errors"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
@@ -11,30 +16,53 @@ import (
 	"net/http"
 )
 
+// Request описывает событие на весь день (только Date) или событие со временем
+// (StartTime и EndTime в RFC 3339 или локальное время YYYY-MM-DDTHH:MM в TimeZone,
+// по умолчанию — в часовом поясе календаря).
 type Request struct {
-	UserId int64  `json:"user_id" validate:"required"`
-	Date   string `json:"date" validate:"required"`
-	Text   string `json:"text" validate:"required"`
+	Date      string `json:"date,omitempty" validate:"required_without=StartTime"`
+	Text      string `json:"text" validate:"required"`
+	StartTime string `json:"start_time,omitempty" validate:"required_with=EndTime"`
+	EndTime   string `json:"end_time,omitempty" validate:"required_with=StartTime"`
+	TimeZone  string `json:"time_zone,omitempty"`
+	// Recurrence — правило повторения RRULE, например "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=10".
+	Recurrence string `json:"rrule,omitempty"`
+	// Tags — теги события, регистр не учитывается.
+	Tags []string `json:"tags,omitempty" validate:"dive,max=64"`
+	// Attendees — ID приглашенных пользователей, они видят событие в своих выборках.
+	Attendees []int64 `json:"attendees,omitempty" validate:"dive,min=1"`
+	// Reminders — за сколько минут до начала события (каждого повторения серии) напомнить о нем, не больше 4 недель.
+	Reminders []int `json:"reminders,omitempty" validate:"dive,min=0,max=40320"`
+	// CalendarId — календарь события, по умолчанию календарь пользователя по умолчанию.
+	CalendarId int64 `json:"calendar_id,omitempty"`
 }
 
+// Response возвращает ID и версию нового события, версия дублируется в заголовке ETag.
 type Response struct {
 	response.Response
 	EventId int64 `json:"event_id"`
+	Version int64 `json:"version"`
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=CreateEvent
 type CreateEvent interface {
-	SaveEvent(userID int64, dateStr, text string) (int64, error)
+	SaveEvent(ctx context.Context, event models.Event) (int64, error)
+	GetCalendar(ctx context.Context, userID, calendarID int64) (models.Calendar, error)
 }
 
 func New(log *slog.Logger, event CreateEvent) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.event.createEvent.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 		)
 
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
 		var req Request
 
 		err := render.DecodeJSON(r.Body, &req)
@@ -59,18 +87,54 @@ func New(log *slog.Logger, event CreateEvent) http.HandlerFunc {
 			return
 		}
 
-		eventId, err := event.SaveEvent(req.UserId, req.Date, req.Text)
-		if errors.Is(err, storage.ErrEventExists) {
-			log.Info("event already exists", slog.Int64("event", eventId))
-			render.Status(r, http.StatusServiceUnavailable)
-			render.JSON(w, r, response.Error("event already exists"))
+		timeZone := req.TimeZone
+		if timeZone == "" && req.StartTime != "" {
+			calendar, err := event.GetCalendar(r.Context(), userID, req.CalendarId)
+			if err != nil {
+				response.StorageError(w, r, log, err, "failed to add event")
+
+				return
+			}
+			timeZone = calendar.TimeZone
+		}
+
+		timing, err := eventtime.Parse(req.Date, req.StartTime, req.EndTime, timeZone)
+		if err != nil {
+			log.Error("invalid event time", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
 
 			return
 		}
+
+		var recurrence string
+		if req.Recurrence != "" {
+			rule, err := rrule.Parse(req.Recurrence)
+			if err != nil {
+				log.Error("invalid recurrence rule", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, response.Error("invalid rrule: "+err.Error()))
+
+				return
+			}
+			recurrence = rule.String()
+		}
+
+		eventId, err := event.SaveEvent(r.Context(), models.Event{
+			UserID:     userID,
+			CalendarID: req.CalendarId,
+			Date:       timing.Date,
+			Text:       req.Text,
+			StartsAt:   timing.StartsAt,
+			EndsAt:     timing.EndsAt,
+			TimeZone:   timing.TimeZone,
+			Recurrence: recurrence,
+			Tags:       models.NormalizeTags(req.Tags),
+			Attendees:  models.Invite(req.Attendees),
+			Reminders:  models.RemindBefore(req.Reminders),
+		})
 		if err != nil {
-			log.Error("failed to add event", sl.Err(err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, response.Error("failed to add event"))
+			response.StorageError(w, r, log, err, "failed to add event")
 
 			return
 		}
@@ -82,8 +146,10 @@ func New(log *slog.Logger, event CreateEvent) http.HandlerFunc {
 }
 
 func responseOK(w http.ResponseWriter, r *http.Request, eventId int64) {
+	w.Header().Set("ETag", etag.Format(models.InitialVersion))
 	render.JSON(w, r, Response{
 		Response: response.OK(),
 		EventId:  eventId,
+		Version:  models.InitialVersion,
 	})
 }
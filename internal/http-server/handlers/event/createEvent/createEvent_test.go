@@ -2,15 +2,19 @@ package createEvent_test
 
 import (
 	"Events-Service/internal/http-server/handlers/event/createEvent"
+	"Events-Service/internal/http-server/middleware/auth"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"Events-Service/internal/http-server/handlers/event/createEvent/mocks"
+	"Events-Service/internal/models"
 	"Events-Service/internal/storage"
 
 	"github.com/stretchr/testify/assert"
@@ -19,16 +23,16 @@ import (
 
 func TestNew_Success(t *testing.T) {
 	mockService := new(mocks.CreateEvent)
-	mockService.On("SaveEvent", mock.AnythingOfType("int64"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+	mockService.On("SaveEvent", mock.Anything, mock.AnythingOfType("models.Event")).
 		Return(int64(42), nil).Once()
 
 	requestBody := createEvent.Request{
-		UserId: 1,
-		Date:   "2025-08-05",
-		Text:   "Test event",
+		Date: "2025-08-05",
+		Text: "Test event",
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
@@ -43,22 +47,24 @@ func TestNew_Success(t *testing.T) {
 	err := json.Unmarshal(rr.Body.Bytes(), &resp)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(42), resp.EventId)
+	assert.Equal(t, models.InitialVersion, resp.Version)
+	assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
 
 	mockService.AssertExpectations(t)
 }
 
-func TestNew_EventExists(t *testing.T) {
+func TestNew_UserNotFound(t *testing.T) {
 	mockService := new(mocks.CreateEvent)
-	mockService.On("SaveEvent", mock.Anything, mock.Anything, mock.Anything).
-		Return(int64(0), storage.ErrEventExists).Once()
+	mockService.On("SaveEvent", mock.Anything, mock.Anything).
+		Return(int64(0), storage.ErrUserNotFound).Once()
 
 	requestBody := createEvent.Request{
-		UserId: 1,
-		Date:   "2025-08-05",
-		Text:   "Test event",
+		Date: "2025-08-05",
+		Text: "Test event",
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
@@ -66,23 +72,24 @@ func TestNew_EventExists(t *testing.T) {
 	handler := createEvent.New(testLogger, mockService)
 	handler.ServeHTTP(rr, req)
 
-	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "user not found")
 
 	mockService.AssertExpectations(t)
 }
 
 func TestNew_InternalServerError(t *testing.T) {
 	mockService := new(mocks.CreateEvent)
-	mockService.On("SaveEvent", mock.Anything, mock.Anything, mock.Anything).
+	mockService.On("SaveEvent", mock.Anything, mock.Anything).
 		Return(int64(0), errors.New("database connection failed")).Once()
 
 	requestBody := createEvent.Request{
-		UserId: 1,
-		Date:   "2025-08-05",
-		Text:   "Test event",
+		Date: "2025-08-05",
+		Text: "Test event",
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
@@ -95,24 +102,235 @@ func TestNew_InternalServerError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestNew_ContextDone(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        func() (context.Context, context.CancelFunc)
+		wantStatus int
+	}{
+		{
+			name:       "canceled by client",
+			ctx:        func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			wantStatus: 499,
+		},
+		{
+			name:       "deadline exceeded",
+			ctx:        func() (context.Context, context.CancelFunc) { return context.WithTimeout(context.Background(), 0) },
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			cancel()
+
+			mockService := new(mocks.CreateEvent)
+			mockService.On("SaveEvent", mock.Anything, mock.Anything).
+				Return(int64(0), errors.New("failed to begin transaction: context done")).Once()
+
+			body, _ := json.Marshal(createEvent.Request{Date: "2025-08-05", Text: "Test event"})
+			req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body)).WithContext(ctx)
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			createEvent.New(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestNew_ValidationError(t *testing.T) {
 	mockService := new(mocks.CreateEvent)
 
 	requestBody := createEvent.Request{
-		UserId: 0,
-		Date:   "",
-		Text:   "",
+		Date: "",
+		Text: "",
+	}
+	body, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := createEvent.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockService.AssertNotCalled(t, "SaveEvent", mock.Anything)
+}
+
+func TestNew_TimedEvent(t *testing.T) {
+	mockService := new(mocks.CreateEvent)
+	mockService.On("SaveEvent", mock.Anything, mock.MatchedBy(func(e models.Event) bool {
+		return e.Date == "2025-08-05" &&
+			e.TimeZone == "Europe/Moscow" &&
+			e.StartsAt.Equal(time.Date(2025, 8, 5, 11, 0, 0, 0, time.UTC)) &&
+			e.EndsAt.Equal(time.Date(2025, 8, 5, 12, 30, 0, 0, time.UTC))
+	})).Return(int64(7), nil).Once()
+
+	requestBody := createEvent.Request{
+		Text:      "Meeting",
+		StartTime: "2025-08-05T14:00",
+		EndTime:   "2025-08-05T15:30",
+		TimeZone:  "Europe/Moscow",
+	}
+	body, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := createEvent.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_CalendarTimeZone(t *testing.T) {
+	mockService := new(mocks.CreateEvent)
+	mockService.On("GetCalendar", mock.Anything, int64(1), int64(3)).
+		Return(models.Calendar{ID: 3, UserID: 1, TimeZone: "Europe/Moscow"}, nil).Once()
+	mockService.On("SaveEvent", mock.Anything, mock.MatchedBy(func(e models.Event) bool {
+		return e.CalendarID == 3 &&
+			e.TimeZone == "Europe/Moscow" &&
+			e.StartsAt.Equal(time.Date(2025, 8, 5, 11, 0, 0, 0, time.UTC))
+	})).Return(int64(7), nil).Once()
+
+	requestBody := createEvent.Request{
+		Text:       "Meeting",
+		StartTime:  "2025-08-05T14:00",
+		EndTime:    "2025-08-05T15:30",
+		CalendarId: 3,
+	}
+	body, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := createEvent.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_CalendarNotFound(t *testing.T) {
+	mockService := new(mocks.CreateEvent)
+	mockService.On("GetCalendar", mock.Anything, int64(1), int64(3)).
+		Return(models.Calendar{}, storage.ErrCalendarNotFound).Once()
+
+	requestBody := createEvent.Request{
+		Text:       "Meeting",
+		StartTime:  "2025-08-05T14:00",
+		EndTime:    "2025-08-05T15:30",
+		CalendarId: 3,
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
+
 	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := createEvent.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
 
+	mockService.AssertNotCalled(t, "SaveEvent", mock.Anything)
+}
+
+func TestNew_InvalidTime(t *testing.T) {
+	mockService := new(mocks.CreateEvent)
+	mockService.On("GetCalendar", mock.Anything, int64(1), int64(0)).
+		Return(models.Calendar{ID: 1, UserID: 1, TimeZone: "UTC"}, nil).Once()
+
+	requestBody := createEvent.Request{
+		Text:      "Meeting",
+		StartTime: "2025-08-05T15:30",
+		EndTime:   "2025-08-05T14:00",
+	}
+	body, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
 	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
 	handler := createEvent.New(testLogger, mockService)
 	handler.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 
-	mockService.AssertNotCalled(t, "SaveEvent")
+	mockService.AssertNotCalled(t, "SaveEvent", mock.Anything)
+}
+
+func TestNew_Attendees(t *testing.T) {
+	mockService := new(mocks.CreateEvent)
+	mockService.On("SaveEvent", mock.Anything, mock.MatchedBy(func(e models.Event) bool {
+		return assert.ObjectsAreEqual([]models.Attendee{
+			{UserID: 3, Status: models.StatusNeedsAction},
+			{UserID: 5, Status: models.StatusNeedsAction},
+		}, e.Attendees)
+	})).Return(int64(42), nil).Once()
+
+	body := `{"date": "2025-08-05", "text": "Planning", "attendees": [5, 3, 5]}`
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewBufferString(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	createEvent.New(testLogger, mockService).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_Reminders(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "valid", body: `{"date": "2025-08-05", "text": "Planning", "reminders": [1440, 10, 10]}`, wantCode: http.StatusOK},
+		{name: "negative", body: `{"date": "2025-08-05", "text": "Planning", "reminders": [-5]}`, wantCode: http.StatusBadRequest},
+		{name: "too early", body: `{"date": "2025-08-05", "text": "Planning", "reminders": [40321]}`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.CreateEvent)
+			if tt.wantCode == http.StatusOK {
+				mockService.On("SaveEvent", mock.Anything, mock.MatchedBy(func(e models.Event) bool {
+					return assert.ObjectsAreEqual([]time.Duration{10 * time.Minute, 24 * time.Hour}, e.Reminders)
+				})).Return(int64(42), nil).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			createEvent.New(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
 }
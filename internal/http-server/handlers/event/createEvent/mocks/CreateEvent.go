@@ -2,16 +2,50 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "Events-Service/internal/models"
+)
 
 // CreateEvent is an autogenerated mock type for the CreateEvent type
 type CreateEvent struct {
 	mock.Mock
 }
 
-// SaveEvent provides a mock function with given fields: userID, dateStr, text
-func (_m *CreateEvent) SaveEvent(userID int64, dateStr string, text string) (int64, error) {
-	ret := _m.Called(userID, dateStr, text)
+// GetCalendar provides a mock function with given fields: ctx, userID, calendarID
+func (_m *CreateEvent) GetCalendar(ctx context.Context, userID int64, calendarID int64) (models.Calendar, error) {
+	ret := _m.Called(ctx, userID, calendarID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCalendar")
+	}
+
+	var r0 models.Calendar
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (models.Calendar, error)); ok {
+		return rf(ctx, userID, calendarID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) models.Calendar); ok {
+		r0 = rf(ctx, userID, calendarID)
+	} else {
+		r0 = ret.Get(0).(models.Calendar)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userID, calendarID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveEvent provides a mock function with given fields: ctx, event
+func (_m *CreateEvent) SaveEvent(ctx context.Context, event models.Event) (int64, error) {
+	ret := _m.Called(ctx, event)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SaveEvent")
@@ -19,17 +53,17 @@ func (_m *CreateEvent) SaveEvent(userID int64, dateStr string, text string) (int
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, string, string) (int64, error)); ok {
-		return rf(userID, dateStr, text)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Event) (int64, error)); ok {
+		return rf(ctx, event)
 	}
-	if rf, ok := ret.Get(0).(func(int64, string, string) int64); ok {
-		r0 = rf(userID, dateStr, text)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Event) int64); ok {
+		r0 = rf(ctx, event)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, string, string) error); ok {
-		r1 = rf(userID, dateStr, text)
+	if rf, ok := ret.Get(1).(func(context.Context, models.Event) error); ok {
+		r1 = rf(ctx, event)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1,9 +1,12 @@
 package deleteEvent
 
 import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/etag"
 	"Events-Service/internal/lib/api/response"
 	"Events-Service/internal/lib/logger/sl"
-	"Events-Service/internal/storage"
+	"Events-Service/internal/models"
+	"context"
 	"errors"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
@@ -12,8 +15,13 @@ import (
 )
 
 type Request struct {
-	UserId  int64 `json:"user_id" validate:"required"`
 	EventId int64 `json:"event_id" validate:"required"`
+	// Scope — что удалять в серии: this (одно повторение), following (это и следующие) или all (по умолчанию).
+	Scope string `json:"scope,omitempty"`
+	// OccurrenceDate — исходная дата повторения, обязательна для scope this и following.
+	OccurrenceDate string `json:"occurrence_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	// ExpectedVersion — версия, которую клиент видел последней. Вместо нее можно передать заголовок If-Match.
+	ExpectedVersion int64 `json:"expected_version,omitempty" validate:"omitempty,min=1"`
 }
 
 type Response struct {
@@ -22,17 +30,22 @@ type Response struct {
 
 //go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=DeleteEvent
 type DeleteEvent interface {
-	DeleteEvent(userID, eventID int64) error
+	DeleteEvent(ctx context.Context, userID, eventID int64, scope models.Scope, occurrenceDate string, expectedVersion int64) error
 }
 
 func New(log *slog.Logger, event DeleteEvent) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.event.deleteEvent.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 		)
 
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
 		var req Request
 
 		err := render.DecodeJSON(r.Body, &req)
@@ -57,19 +70,31 @@ func New(log *slog.Logger, event DeleteEvent) http.HandlerFunc {
 			return
 		}
 
-		eventId := req.EventId
-		err = event.DeleteEvent(req.UserId, req.EventId)
-		if errors.Is(err, storage.ErrEventNotFound) {
-			log.Info("event not found", slog.Int64("event", eventId))
-			render.Status(r, http.StatusServiceUnavailable)
-			render.JSON(w, r, response.Error("event not found"))
+		scope, err := models.ParseScope(req.Scope)
+		if err == nil && scope != models.ScopeAll && req.OccurrenceDate == "" {
+			err = errors.New("occurrence_date is required for scope " + string(scope))
+		}
+		if err != nil {
+			log.Error("invalid scope", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
 
 			return
 		}
+
+		expectedVersion, err := etag.ExpectedVersion(r, req.ExpectedVersion)
+		if err != nil {
+			log.Error("invalid expected version", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+
+			return
+		}
+
+		eventId := req.EventId
+		err = event.DeleteEvent(r.Context(), userID, req.EventId, scope, req.OccurrenceDate, expectedVersion)
 		if err != nil {
-			log.Error("failed to delete event", sl.Err(err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, response.Error("failed to delete event"))
+			response.StorageError(w, r, log, err, "failed to delete event")
 
 			return
 		}
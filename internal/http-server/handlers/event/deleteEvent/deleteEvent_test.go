@@ -2,6 +2,7 @@ package deleteEvent_test
 
 import (
 	"Events-Service/internal/http-server/handlers/event/deleteEvent"
+	"Events-Service/internal/http-server/middleware/auth"
 	"bytes"
 	"encoding/json"
 	"errors"
@@ -11,6 +12,7 @@ import (
 	"testing"
 
 	"Events-Service/internal/http-server/handlers/event/deleteEvent/mocks"
+	"Events-Service/internal/models"
 	"Events-Service/internal/storage"
 
 	"github.com/stretchr/testify/assert"
@@ -20,15 +22,15 @@ import (
 func TestNew_Success(t *testing.T) {
 	mockService := new(mocks.DeleteEvent)
 
-	mockService.On("DeleteEvent", mock.AnythingOfType("int64"), mock.AnythingOfType("int64")).
+	mockService.On("DeleteEvent", mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("int64"), models.ScopeAll, "", int64(0)).
 		Return(nil).Once()
 
 	requestBody := deleteEvent.Request{
-		UserId:  1,
 		EventId: 101,
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodDelete, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
@@ -44,15 +46,15 @@ func TestNew_Success(t *testing.T) {
 func TestNew_EventNotFound(t *testing.T) {
 	mockService := new(mocks.DeleteEvent)
 
-	mockService.On("DeleteEvent", mock.AnythingOfType("int64"), mock.AnythingOfType("int64")).
+	mockService.On("DeleteEvent", mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("int64"), models.ScopeAll, "", int64(0)).
 		Return(storage.ErrEventNotFound).Once()
 
 	requestBody := deleteEvent.Request{
-		UserId:  1,
 		EventId: 999,
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodDelete, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
@@ -60,7 +62,7 @@ func TestNew_EventNotFound(t *testing.T) {
 	handler := deleteEvent.New(testLogger, mockService)
 	handler.ServeHTTP(rr, req)
 
-	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
 
 	mockService.AssertExpectations(t)
 }
@@ -68,15 +70,15 @@ func TestNew_EventNotFound(t *testing.T) {
 func TestNew_InternalServerError(t *testing.T) {
 	mockService := new(mocks.DeleteEvent)
 
-	mockService.On("DeleteEvent", mock.AnythingOfType("int64"), mock.AnythingOfType("int64")).
+	mockService.On("DeleteEvent", mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("int64"), models.ScopeAll, "", int64(0)).
 		Return(errors.New("database connection failed")).Once()
 
 	requestBody := deleteEvent.Request{
-		UserId:  1,
 		EventId: 101,
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodDelete, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
@@ -93,11 +95,11 @@ func TestNew_ValidationError(t *testing.T) {
 	mockService := new(mocks.DeleteEvent)
 
 	requestBody := deleteEvent.Request{
-		UserId:  0,
 		EventId: 0,
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodDelete, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
@@ -107,5 +109,53 @@ func TestNew_ValidationError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 
-	mockService.AssertNotCalled(t, "DeleteEvent")
+	mockService.AssertNotCalled(t, "DeleteEvent", mock.Anything)
+}
+
+func TestNew_OccurrenceNotFound(t *testing.T) {
+	mockService := new(mocks.DeleteEvent)
+
+	mockService.On("DeleteEvent", mock.Anything, int64(1), int64(101), models.ScopeThis, "2025-09-10", int64(0)).
+		Return(storage.ErrOccurrenceNotFound).Once()
+
+	requestBody := deleteEvent.Request{
+		EventId:        101,
+		Scope:          "this",
+		OccurrenceDate: "2025-09-10",
+	}
+	body, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodDelete, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := deleteEvent.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_VersionMismatch(t *testing.T) {
+	mockService := new(mocks.DeleteEvent)
+
+	mockService.On("DeleteEvent", mock.Anything, int64(1), int64(101), models.ScopeAll, "", int64(3)).
+		Return(storage.ErrVersionMismatch).Once()
+
+	body, _ := json.Marshal(deleteEvent.Request{EventId: 101})
+	req := httptest.NewRequest(http.MethodPost, "/delete_event", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"3"`)
+
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := deleteEvent.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+
+	mockService.AssertExpectations(t)
 }
@@ -2,24 +2,30 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "Events-Service/internal/models"
+)
 
 // DeleteEvent is an autogenerated mock type for the DeleteEvent type
 type DeleteEvent struct {
 	mock.Mock
 }
 
-// DeleteEvent provides a mock function with given fields: userID, eventID
-func (_m *DeleteEvent) DeleteEvent(userID int64, eventID int64) error {
-	ret := _m.Called(userID, eventID)
+// DeleteEvent provides a mock function with given fields: ctx, userID, eventID, scope, occurrenceDate, expectedVersion
+func (_m *DeleteEvent) DeleteEvent(ctx context.Context, userID int64, eventID int64, scope models.Scope, occurrenceDate string, expectedVersion int64) error {
+	ret := _m.Called(ctx, userID, eventID, scope, occurrenceDate, expectedVersion)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteEvent")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int64, int64) error); ok {
-		r0 = rf(userID, eventID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, models.Scope, string, int64) error); ok {
+		r0 = rf(ctx, userID, eventID, scope, occurrenceDate, expectedVersion)
 	} else {
 		r0 = ret.Error(0)
 	}
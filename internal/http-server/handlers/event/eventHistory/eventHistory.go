@@ -0,0 +1,140 @@
+package eventHistory
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Request читается из параметров запроса: /event_history?event_id=7.
+type Request struct {
+	EventId int64 `validate:"required"`
+}
+
+type ChangeResponse struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// RevisionResponse — запись истории. UserId — автор изменения, ChangedAt — время в UTC (RFC 3339).
+type RevisionResponse struct {
+	RevisionId     int64            `json:"revision_id"`
+	UserId         int64            `json:"user_id"`
+	Action         string           `json:"action"`
+	OccurrenceDate string           `json:"occurrence_date,omitempty"`
+	ChangedAt      string           `json:"changed_at"`
+	Changes        []ChangeResponse `json:"changes"`
+}
+
+type Response struct {
+	response.Response
+	EventId   int64              `json:"event_id"`
+	Revisions []RevisionResponse `json:"revisions"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=EventHistory
+type EventHistory interface {
+	EventHistory(ctx context.Context, userID, eventID int64) ([]models.Revision, error)
+}
+
+func New(log *slog.Logger, history EventHistory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.event.eventHistory.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		req, err := parseRequest(r)
+		if err != nil {
+			log.Error("failed to parse query", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+
+			return
+		}
+
+		log.Info("request parsed", slog.Any("request", req))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		revisions, err := history.EventHistory(r.Context(), userID, req.EventId)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to get event history")
+
+			return
+		}
+
+		log.Info("got event history", slog.Int("count", len(revisions)))
+
+		responseOK(w, r, req.EventId, revisions)
+	}
+}
+
+func parseRequest(r *http.Request) (Request, error) {
+	var req Request
+
+	if value := r.URL.Query().Get("event_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return Request{}, fmt.Errorf("invalid event_id: %q", value)
+		}
+		req.EventId = id
+	}
+
+	return req, nil
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, eventId int64, revisions []models.Revision) {
+	responseRevisions := make([]RevisionResponse, 0, len(revisions))
+	for _, rev := range revisions {
+		changes := make([]ChangeResponse, 0, 2)
+		for _, change := range rev.Changes() {
+			changes = append(changes, ChangeResponse{
+				Field: change.Field,
+				Old:   change.Old,
+				New:   change.New,
+			})
+		}
+
+		responseRevisions = append(responseRevisions, RevisionResponse{
+			RevisionId:     rev.ID,
+			UserId:         rev.UserID,
+			Action:         string(rev.Action),
+			OccurrenceDate: rev.OccurrenceDate,
+			ChangedAt:      rev.ChangedAt.UTC().Format(time.RFC3339),
+			Changes:        changes,
+		})
+	}
+
+	render.JSON(w, r, Response{
+		Response:  response.OK(),
+		EventId:   eventId,
+		Revisions: responseRevisions,
+	})
+}
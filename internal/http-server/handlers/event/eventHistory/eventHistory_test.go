@@ -0,0 +1,101 @@
+package eventHistory_test
+
+import (
+	"Events-Service/internal/http-server/handlers/event/eventHistory"
+	"Events-Service/internal/http-server/handlers/event/eventHistory/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNew_Success(t *testing.T) {
+	mockService := new(mocks.EventHistory)
+
+	changedAt := time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC)
+	mockService.On("EventHistory", mock.Anything, int64(1), int64(7)).
+		Return([]models.Revision{
+			{ID: 1, EventID: 7, UserID: 1, Action: models.ActionCreate, ChangedAt: changedAt,
+				NewDate: "2025-03-20", NewText: "Dentist"},
+			{ID: 2, EventID: 7, UserID: 1, Action: models.ActionUpdate, ChangedAt: changedAt.Add(time.Hour),
+				OldDate: "2025-03-20", NewDate: "2025-03-21", OldText: "Dentist", NewText: "Dentist"},
+		}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/event_history?event_id=7", nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := eventHistory.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp eventHistory.Response
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), resp.EventId)
+	assert.Equal(t, []eventHistory.RevisionResponse{
+		{
+			RevisionId: 1, UserId: 1, Action: "create", ChangedAt: "2025-03-14T09:30:00Z",
+			Changes: []eventHistory.ChangeResponse{
+				{Field: "date", New: "2025-03-20"},
+				{Field: "text", New: "Dentist"},
+			},
+		},
+		{
+			RevisionId: 2, UserId: 1, Action: "update", ChangedAt: "2025-03-14T10:30:00Z",
+			Changes: []eventHistory.ChangeResponse{
+				{Field: "date", Old: "2025-03-20", New: "2025-03-21"},
+			},
+		},
+	}, resp.Revisions)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		serviceErr error
+		callsStore bool
+		wantStatus int
+	}{
+		{name: "event not found", query: "?event_id=7", serviceErr: storage.ErrEventNotFound, callsStore: true, wantStatus: http.StatusNotFound},
+		{name: "storage error", query: "?event_id=7", serviceErr: errors.New("database error"), callsStore: true, wantStatus: http.StatusInternalServerError},
+		{name: "missing event id", query: "", wantStatus: http.StatusBadRequest},
+		{name: "invalid event id", query: "?event_id=seven", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.EventHistory)
+			if tt.callsStore {
+				mockService.On("EventHistory", mock.Anything, int64(1), int64(7)).Return(nil, tt.serviceErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/event_history"+tt.query, nil)
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			handler := eventHistory.New(testLogger, mockService)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
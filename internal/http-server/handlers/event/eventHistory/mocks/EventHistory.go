@@ -0,0 +1,60 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "Events-Service/internal/models"
+)
+
+// EventHistory is an autogenerated mock type for the EventHistory type
+type EventHistory struct {
+	mock.Mock
+}
+
+// EventHistory provides a mock function with given fields: ctx, userID, eventID
+func (_m *EventHistory) EventHistory(ctx context.Context, userID int64, eventID int64) ([]models.Revision, error) {
+	ret := _m.Called(ctx, userID, eventID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EventHistory")
+	}
+
+	var r0 []models.Revision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]models.Revision, error)); ok {
+		return rf(ctx, userID, eventID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []models.Revision); ok {
+		r0 = rf(ctx, userID, eventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Revision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userID, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewEventHistory creates a new instance of EventHistory. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventHistory(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventHistory {
+	mock := &EventHistory{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
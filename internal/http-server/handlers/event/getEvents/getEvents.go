@@ -1,9 +1,13 @@
 package getEvents
 
 import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/etag"
 	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/eventtime"
 	"Events-Service/internal/lib/logger/sl"
 	"Events-Service/internal/models"
+	"context"
 	"errors"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
@@ -13,13 +17,45 @@ import (
 )
 
 type EventResponse struct {
-	Date string `json:"date"`
-	Text string `json:"text"`
+	EventId   int64  `json:"event_id"`
+	Date      string `json:"date"`
+	Text      string `json:"text"`
+	StartTime string `json:"start_time,omitempty"`
+	EndTime   string `json:"end_time,omitempty"`
+	TimeZone  string `json:"time_zone,omitempty"`
+	// Recurrence — правило серии, к которой относится повторение.
+	Recurrence string `json:"rrule,omitempty"`
+	// OccurrenceDate — исходная дата повторения, по ней повторение изменяют или удаляют отдельно от серии.
+	OccurrenceDate string   `json:"occurrence_date,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	CalendarId     int64    `json:"calendar_id"`
+	// Attendees — приглашенные пользователи и их ответы, RsvpStatus — ответ самого пользователя,
+	// если событие принадлежит другому пользователю и он пригласил его.
+	Attendees  []AttendeeResponse `json:"attendees,omitempty"`
+	RsvpStatus string             `json:"rsvp_status,omitempty"`
+	// Reminders — за сколько минут до начала владельцу придут напоминания о событии.
+	Reminders []int `json:"reminders,omitempty"`
+	// Version и ETag — версия события (у повторений — серии). ETag передается в If-Match при изменении.
+	Version int64  `json:"version"`
+	ETag    string `json:"etag"`
+}
+
+type AttendeeResponse struct {
+	UserId      int64  `json:"user_id"`
+	Status      string `json:"status"`
+	RespondedAt string `json:"responded_at,omitempty"`
 }
 
 type Request struct {
-	UserId int64  `json:"user_id"`
-	Date   string `json:"date"`
+	Date string `json:"date"`
+	// Tags оставляет события хотя бы с одним из тегов (TagMatch "any", по умолчанию)
+	// или со всеми сразу (TagMatch "all").
+	Tags     []string `json:"tags,omitempty"`
+	TagMatch string   `json:"tag_match,omitempty" validate:"omitempty,oneof=any all"`
+	// Calendars оставляет события только из этих календарей, по умолчанию — из всех.
+	Calendars []int64 `json:"calendars,omitempty" validate:"dive,min=1"`
+	// OwnerId — владелец открытых пользователю календарей, по умолчанию — сам пользователь.
+	OwnerId int64 `json:"owner_id,omitempty" validate:"min=0"`
 }
 
 type Response struct {
@@ -29,19 +65,24 @@ type Response struct {
 
 //go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=GetEvents
 type GetEvents interface {
-	GetEventsByDay(userID int64, date string) ([]models.Event, error)
-	GetEventsByWeek(userID int64, date time.Time) ([]models.Event, error)
-	GetEventsByMonth(userID int64, year int, month time.Month) ([]models.Event, error)
+	GetEventsByDay(ctx context.Context, userID int64, date string, filter models.EventFilter) ([]models.Event, error)
+	GetEventsByWeek(ctx context.Context, userID int64, date time.Time, filter models.EventFilter) ([]models.Event, error)
+	GetEventsByMonth(ctx context.Context, userID int64, year int, month time.Month, filter models.EventFilter) ([]models.Event, error)
 }
 
 func ByDay(log *slog.Logger, event GetEvents) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.event.getEvents.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 		)
 
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
 		var req Request
 
 		err := render.DecodeJSON(r.Body, &req)
@@ -66,22 +107,14 @@ func ByDay(log *slog.Logger, event GetEvents) http.HandlerFunc {
 			return
 		}
 
-		events, err := event.GetEventsByDay(req.UserId, req.Date)
+		events, err := event.GetEventsByDay(r.Context(), userID, req.Date, req.filter())
 		if err != nil {
-			log.Error("failed to get events", sl.Err(err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, response.Error("failed to get events"))
+			response.StorageError(w, r, log, err, "failed to get events")
 
 			return
 		}
 
-		responseEvents := make([]EventResponse, 0, len(events))
-		for _, e := range events {
-			responseEvents = append(responseEvents, EventResponse{
-				Date: e.Date,
-				Text: e.Text,
-			})
-		}
+		responseEvents := toResponse(userID, events)
 
 		log.Info("got events")
 
@@ -93,10 +126,15 @@ func ByWeek(log *slog.Logger, event GetEvents) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.event.getEvents.ByWeek"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 		)
 
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
 		var req Request
 
 		err := render.DecodeJSON(r.Body, &req)
@@ -129,22 +167,14 @@ func ByWeek(log *slog.Logger, event GetEvents) http.HandlerFunc {
 			return
 		}
 
-		events, err := event.GetEventsByWeek(req.UserId, parsedDate)
+		events, err := event.GetEventsByWeek(r.Context(), userID, parsedDate, req.filter())
 		if err != nil {
-			log.Error("failed to get events", sl.Err(err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, response.Error("failed to get events"))
+			response.StorageError(w, r, log, err, "failed to get events")
 
 			return
 		}
 
-		responseEvents := make([]EventResponse, 0, len(events))
-		for _, e := range events {
-			responseEvents = append(responseEvents, EventResponse{
-				Date: e.Date,
-				Text: e.Text,
-			})
-		}
+		responseEvents := toResponse(userID, events)
 
 		log.Info("got events")
 
@@ -156,10 +186,15 @@ func ByMonth(log *slog.Logger, event GetEvents) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.event.getEvents.ByMonth"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 		)
 
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
 		var req Request
 
 		err := render.DecodeJSON(r.Body, &req)
@@ -195,22 +230,14 @@ func ByMonth(log *slog.Logger, event GetEvents) http.HandlerFunc {
 		year := parsedDate.Year()
 		month := parsedDate.Month()
 
-		events, err := event.GetEventsByMonth(req.UserId, year, month)
+		events, err := event.GetEventsByMonth(r.Context(), userID, year, month, req.filter())
 		if err != nil {
-			log.Error("failed to get events", sl.Err(err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, response.Error("failed to get events"))
+			response.StorageError(w, r, log, err, "failed to get events")
 
 			return
 		}
 
-		responseEvents := make([]EventResponse, 0, len(events))
-		for _, e := range events {
-			responseEvents = append(responseEvents, EventResponse{
-				Date: e.Date,
-				Text: e.Text,
-			})
-		}
+		responseEvents := toResponse(userID, events)
 
 		log.Info("got events")
 
@@ -218,6 +245,70 @@ func ByMonth(log *slog.Logger, event GetEvents) http.HandlerFunc {
 	}
 }
 
+func (req Request) filter() models.EventFilter {
+	return models.EventFilter{
+		Tags:      models.NormalizeTags(req.Tags),
+		All:       req.TagMatch == "all",
+		Calendars: req.Calendars,
+		Owner:     req.OwnerId,
+	}
+}
+
+func toResponse(userID int64, events []models.Event) []EventResponse {
+	responseEvents := make([]EventResponse, 0, len(events))
+	for _, e := range events {
+		responseEvents = append(responseEvents, EventResponse{
+			EventId:        e.ID,
+			Date:           e.Date,
+			Text:           e.Text,
+			StartTime:      eventtime.Format(e.StartsAt, e.TimeZone),
+			EndTime:        eventtime.Format(e.EndsAt, e.TimeZone),
+			TimeZone:       e.TimeZone,
+			Recurrence:     e.Recurrence,
+			OccurrenceDate: e.OccurrenceDate,
+			Tags:           e.Tags,
+			CalendarId:     e.CalendarID,
+			Attendees:      toAttendeesResponse(e.Attendees),
+			RsvpStatus:     string(e.AttendeeStatus(userID)),
+			Reminders:      toMinutes(e.Reminders),
+			Version:        e.Version,
+			ETag:           etag.Format(e.Version),
+		})
+	}
+
+	return responseEvents
+}
+
+func toAttendeesResponse(attendees []models.Attendee) []AttendeeResponse {
+	if len(attendees) == 0 {
+		return nil
+	}
+
+	res := make([]AttendeeResponse, 0, len(attendees))
+	for _, a := range attendees {
+		attendee := AttendeeResponse{UserId: a.UserID, Status: string(a.Status)}
+		if a.RespondedAt != nil {
+			attendee.RespondedAt = a.RespondedAt.UTC().Format(time.RFC3339)
+		}
+		res = append(res, attendee)
+	}
+
+	return res
+}
+
+func toMinutes(reminders []time.Duration) []int {
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	res := make([]int, 0, len(reminders))
+	for _, before := range reminders {
+		res = append(res, int(before/time.Minute))
+	}
+
+	return res
+}
+
 func responseOK(w http.ResponseWriter, r *http.Request, events []EventResponse) {
 	render.JSON(w, r, Response{
 		Response: response.OK(),
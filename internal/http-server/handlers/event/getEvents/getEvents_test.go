@@ -3,14 +3,18 @@ package getEvents_test
 import (
 	"Events-Service/internal/http-server/handlers/event/getEvents"
 	"Events-Service/internal/http-server/handlers/event/getEvents/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
 	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
 	"bytes"
 	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -19,18 +23,18 @@ import (
 func TestByWeek_Success(t *testing.T) {
 	mockService := new(mocks.GetEvents)
 
-	mockService.On("GetEventsByWeek", mock.AnythingOfType("int64"), mock.AnythingOfType("time.Time")).
+	mockService.On("GetEventsByWeek", mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("time.Time"), models.EventFilter{}).
 		Return([]models.Event{
-			{Date: "2025-08-02", Text: "Event A"},
-			{Date: "2025-08-05", Text: "Event B"},
+			{Date: "2025-08-02", Text: "Event A", Version: 1},
+			{Date: "2025-08-05", Text: "Event B", Version: 4},
 		}, nil).Once()
 
 	requestBody := getEvents.Request{
-		UserId: 1,
-		Date:   "2025-08-05",
+		Date: "2025-08-05",
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodPost, "/events/by-week", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
@@ -47,8 +51,8 @@ func TestByWeek_Success(t *testing.T) {
 	assert.NoError(t, err)
 
 	expectedEvents := []getEvents.EventResponse{
-		{Date: "2025-08-02", Text: "Event A"},
-		{Date: "2025-08-05", Text: "Event B"},
+		{Date: "2025-08-02", Text: "Event A", Version: 1, ETag: `"1"`},
+		{Date: "2025-08-05", Text: "Event B", Version: 4, ETag: `"4"`},
 	}
 	assert.Equal(t, expectedEvents, resp.Events)
 
@@ -58,15 +62,15 @@ func TestByWeek_Success(t *testing.T) {
 func TestByWeek_ServiceError(t *testing.T) {
 	mockService := new(mocks.GetEvents)
 
-	mockService.On("GetEventsByWeek", mock.AnythingOfType("int64"), mock.AnythingOfType("time.Time")).
+	mockService.On("GetEventsByWeek", mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("time.Time"), models.EventFilter{}).
 		Return(nil, errors.New("database error")).Once()
 
 	requestBody := getEvents.Request{
-		UserId: 1,
-		Date:   "2025-08-05",
+		Date: "2025-08-05",
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodPost, "/events/by-week", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 	rr := httptest.NewRecorder()
 
@@ -83,11 +87,11 @@ func TestByWeek_InvalidDate(t *testing.T) {
 	mockService := new(mocks.GetEvents)
 
 	requestBody := getEvents.Request{
-		UserId: 1,
-		Date:   "invalid-date",
+		Date: "invalid-date",
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodPost, "/events_for_week", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 	rr := httptest.NewRecorder()
 
@@ -97,5 +101,241 @@ func TestByWeek_InvalidDate(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 
-	mockService.AssertNotCalled(t, "GetEventsByWeek")
+	mockService.AssertNotCalled(t, "GetEventsByWeek", mock.Anything)
+}
+
+func TestByDay_TimedEvent(t *testing.T) {
+	mockService := new(mocks.GetEvents)
+
+	startsAt := time.Date(2025, 8, 5, 11, 0, 0, 0, time.UTC)
+	endsAt := time.Date(2025, 8, 5, 12, 30, 0, 0, time.UTC)
+	mockService.On("GetEventsByDay", mock.Anything, int64(1), "2025-08-05", models.EventFilter{}).
+		Return([]models.Event{
+			{Date: "2025-08-05", Text: "Meeting", StartsAt: &startsAt, EndsAt: &endsAt, TimeZone: "Europe/Moscow", Version: 1},
+		}, nil).Once()
+
+	requestBody := getEvents.Request{
+		Date: "2025-08-05",
+	}
+	body, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodGet, "/events_for_day", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := getEvents.ByDay(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp getEvents.Response
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+
+	expectedEvents := []getEvents.EventResponse{
+		{
+			Date:      "2025-08-05",
+			Text:      "Meeting",
+			StartTime: "2025-08-05T14:00:00+03:00",
+			EndTime:   "2025-08-05T15:30:00+03:00",
+			TimeZone:  "Europe/Moscow",
+			Version:   1,
+			ETag:      `"1"`,
+		},
+	}
+	assert.Equal(t, expectedEvents, resp.Events)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestByMonth_TagFilter(t *testing.T) {
+	mockService := new(mocks.GetEvents)
+
+	filter := models.EventFilter{Tags: []string{"oncall", "work"}, All: true}
+	mockService.On("GetEventsByMonth", mock.Anything, int64(1), 2025, time.August, filter).
+		Return([]models.Event{
+			{ID: 3, Date: "2025-08-05", Text: "Incident review", Tags: []string{"oncall", "work"}, Version: 2},
+		}, nil).Once()
+
+	requestBody := getEvents.Request{
+		Date:     "2025-08-05",
+		Tags:     []string{"Work", " oncall"},
+		TagMatch: "all",
+	}
+	body, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodGet, "/events_for_month", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := getEvents.ByMonth(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp getEvents.Response
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, []getEvents.EventResponse{
+		{EventId: 3, Date: "2025-08-05", Text: "Incident review", Tags: []string{"oncall", "work"}, Version: 2, ETag: `"2"`},
+	}, resp.Events)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestByMonth_CalendarFilter(t *testing.T) {
+	mockService := new(mocks.GetEvents)
+
+	filter := models.EventFilter{Calendars: []int64{2, 5}}
+	mockService.On("GetEventsByMonth", mock.Anything, int64(1), 2025, time.August, filter).
+		Return([]models.Event{
+			{ID: 3, CalendarID: 5, Date: "2025-08-05", Text: "Standup", Version: 1},
+		}, nil).Once()
+
+	requestBody := getEvents.Request{
+		Date:      "2025-08-05",
+		Calendars: []int64{2, 5},
+	}
+	body, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodGet, "/events_for_month", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := getEvents.ByMonth(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp getEvents.Response
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, []getEvents.EventResponse{
+		{EventId: 3, Date: "2025-08-05", Text: "Standup", CalendarId: 5, Version: 1, ETag: `"1"`},
+	}, resp.Events)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestByMonth_SharedCalendars(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceErr error
+		wantStatus int
+	}{
+		{name: "shared", wantStatus: http.StatusOK},
+		{name: "not shared", serviceErr: storage.ErrCalendarForbidden, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.GetEvents)
+
+			filter := models.EventFilter{Owner: 3}
+			mockService.On("GetEventsByMonth", mock.Anything, int64(1), 2025, time.August, filter).
+				Return([]models.Event{}, tt.serviceErr).Once()
+
+			body, _ := json.Marshal(getEvents.Request{Date: "2025-08-05", OwnerId: 3})
+			req := httptest.NewRequest(http.MethodGet, "/events_for_month", bytes.NewReader(body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			getEvents.ByMonth(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestByMonth_InvalidTagMatch(t *testing.T) {
+	mockService := new(mocks.GetEvents)
+
+	requestBody := getEvents.Request{
+		Date:     "2025-08-05",
+		Tags:     []string{"work"},
+		TagMatch: "none",
+	}
+	body, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodGet, "/events_for_month", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := getEvents.ByMonth(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockService.AssertNotCalled(t, "GetEventsByMonth", mock.Anything)
+}
+
+func TestByDay_Invitation(t *testing.T) {
+	respondedAt := time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+
+	mockService := new(mocks.GetEvents)
+	mockService.On("GetEventsByDay", mock.Anything, int64(1), "2025-08-05", models.EventFilter{}).
+		Return([]models.Event{
+			{ID: 3, UserID: 2, CalendarID: 4, Date: "2025-08-05", Text: "Planning", Version: 1, Attendees: []models.Attendee{
+				{UserID: 1, Status: models.StatusAccepted, RespondedAt: &respondedAt},
+				{UserID: 5, Status: models.StatusNeedsAction},
+			}},
+		}, nil).Once()
+
+	body, _ := json.Marshal(getEvents.Request{Date: "2025-08-05"})
+	req := httptest.NewRequest(http.MethodGet, "/events_for_day", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	getEvents.ByDay(testLogger, mockService).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp getEvents.Response
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, []getEvents.EventResponse{{
+		EventId: 3, Date: "2025-08-05", Text: "Planning", CalendarId: 4, Version: 1, ETag: `"1"`,
+		Attendees: []getEvents.AttendeeResponse{
+			{UserId: 1, Status: "accepted", RespondedAt: "2025-08-01T09:00:00Z"},
+			{UserId: 5, Status: "needs-action"},
+		},
+		RsvpStatus: "accepted",
+	}}, resp.Events)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestByWeek_LoggerPerRequest(t *testing.T) {
+	mockService := new(mocks.GetEvents)
+
+	mockService.On("GetEventsByWeek", mock.Anything, mock.AnythingOfType("int64"), mock.AnythingOfType("time.Time"), models.EventFilter{}).
+		Return([]models.Event{}, nil).Twice()
+
+	var logs bytes.Buffer
+	handler := getEvents.ByWeek(slog.New(slog.NewTextHandler(&logs, nil)), mockService)
+
+	// Атрибуты логгера одного запроса не переходят в следующий.
+	for i := 0; i < 2; i++ {
+		logs.Reset()
+		body, _ := json.Marshal(getEvents.Request{Date: "2025-08-05"})
+		req := httptest.NewRequest(http.MethodPost, "/events/by-week", bytes.NewReader(body))
+		req = req.WithContext(auth.WithUserID(req.Context(), 1))
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+		line, _, _ := strings.Cut(logs.String(), "\n")
+		assert.Equal(t, 1, strings.Count(line, "op=handlers.event.getEvents.ByWeek"), line)
+	}
+
+	mockService.AssertExpectations(t)
 }
@@ -0,0 +1,164 @@
+package getEvents
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/cursor"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const defaultListLimit = 100
+
+// ListRequest читается из параметров запроса:
+// /events?from=2025-01-01&to=2025-12-31&limit=100&cursor=...&calendars=1,2&owner_id=3
+// From и To включительно, Cursor — NextCursor предыдущей страницы,
+// Calendars оставляет события только из этих календарей,
+// OwnerId — владелец открытых пользователю календарей.
+type ListRequest struct {
+	From      string `validate:"required,datetime=2006-01-02"`
+	To        string `validate:"required,datetime=2006-01-02"`
+	Limit     int    `validate:"min=1,max=1000"`
+	Cursor    string
+	Calendars []int64
+	OwnerId   int64
+}
+
+// ListResponse — страница событий по возрастанию даты начала и ID. NextCursor пуст на последней странице.
+type ListResponse struct {
+	response.Response
+	Events     []EventResponse `json:"events"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=ListEvents
+type ListEvents interface {
+	ListEvents(ctx context.Context, userID int64, query models.ListQuery) ([]models.Event, error)
+}
+
+func List(log *slog.Logger, event ListEvents) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.event.getEvents.List"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		req, err := parseListRequest(r)
+		if err != nil {
+			log.Error("failed to parse query", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+
+			return
+		}
+
+		log.Info("request parsed", slog.Any("request", req))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		if req.To < req.From {
+			log.Error("invalid date range", slog.String("from", req.From), slog.String("to", req.To))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("to must not be before from"))
+
+			return
+		}
+
+		// Лишнее событие показывает, есть ли следующая страница.
+		query := models.ListQuery{From: req.From, To: req.To, Limit: req.Limit + 1, Calendars: req.Calendars, Owner: req.OwnerId}
+		if req.Cursor != "" {
+			after, err := cursor.Decode(req.Cursor)
+			if err != nil {
+				log.Error("invalid cursor", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, response.Error("invalid cursor"))
+
+				return
+			}
+			query.After = &after
+		}
+
+		events, err := event.ListEvents(r.Context(), userID, query)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to list events")
+
+			return
+		}
+
+		var next string
+		if len(events) > req.Limit {
+			events = events[:req.Limit]
+			next = cursor.Encode(events[len(events)-1].Key())
+		}
+
+		log.Info("listed events", slog.Int("count", len(events)), slog.Bool("more", next != ""))
+
+		render.JSON(w, r, ListResponse{
+			Response:   response.OK(),
+			Events:     toResponse(userID, events),
+			NextCursor: next,
+		})
+	}
+}
+
+func parseListRequest(r *http.Request) (ListRequest, error) {
+	query := r.URL.Query()
+	req := ListRequest{
+		From:   query.Get("from"),
+		To:     query.Get("to"),
+		Limit:  defaultListLimit,
+		Cursor: query.Get("cursor"),
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return ListRequest{}, fmt.Errorf("invalid limit: %q", value)
+		}
+		req.Limit = limit
+	}
+
+	if value := query.Get("calendars"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+			if err != nil || id < 1 {
+				return ListRequest{}, fmt.Errorf("invalid calendars: %q", value)
+			}
+			req.Calendars = append(req.Calendars, id)
+		}
+	}
+
+	if value := query.Get("owner_id"); value != "" {
+		owner, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || owner < 1 {
+			return ListRequest{}, fmt.Errorf("invalid owner_id: %q", value)
+		}
+		req.OwnerId = owner
+	}
+
+	return req, nil
+}
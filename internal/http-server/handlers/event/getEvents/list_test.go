@@ -0,0 +1,131 @@
+package getEvents_test
+
+import (
+	"Events-Service/internal/http-server/handlers/event/getEvents"
+	"Events-Service/internal/http-server/handlers/event/getEvents/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/cursor"
+	"Events-Service/internal/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func serveList(mockService *mocks.ListEvents, url string) (*httptest.ResponseRecorder, getEvents.ListResponse) {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	getEvents.List(testLogger, mockService).ServeHTTP(rr, req)
+
+	var resp getEvents.ListResponse
+	_ = json.Unmarshal(rr.Body.Bytes(), &resp)
+
+	return rr, resp
+}
+
+func TestList_Pages(t *testing.T) {
+	mockService := new(mocks.ListEvents)
+	mockService.On("ListEvents", mock.Anything, int64(1), models.ListQuery{From: "2025-01-01", To: "2025-01-31", Limit: 3}).
+		Return([]models.Event{
+			{ID: 1, Date: "2025-01-02", Text: "A", Version: 1},
+			{ID: 5, Date: "2025-01-06", Text: "Standup", OccurrenceDate: "2025-01-06", Version: 1},
+			{ID: 3, Date: "2025-01-07", Text: "C", Version: 1},
+		}, nil).Once()
+
+	rr, resp := serveList(mockService, "/events?from=2025-01-01&to=2025-01-31&limit=2")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	if assert.Len(t, resp.Events, 2) {
+		assert.Equal(t, "Standup", resp.Events[1].Text)
+	}
+	assert.Equal(t, cursor.Encode(models.EventKey{Date: "2025-01-06", ID: 5, OccurrenceDate: "2025-01-06"}), resp.NextCursor)
+
+	after := models.EventKey{Date: "2025-01-06", ID: 5, OccurrenceDate: "2025-01-06"}
+	mockService.On("ListEvents", mock.Anything, int64(1), models.ListQuery{From: "2025-01-01", To: "2025-01-31", Limit: 3, After: &after}).
+		Return([]models.Event{{ID: 3, Date: "2025-01-07", Text: "C", Version: 1}}, nil).Once()
+
+	rr, resp = serveList(mockService, "/events?from=2025-01-01&to=2025-01-31&limit=2&cursor="+resp.NextCursor)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, resp.Events, 1)
+	assert.Empty(t, resp.NextCursor)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestList_DefaultLimit(t *testing.T) {
+	mockService := new(mocks.ListEvents)
+	mockService.On("ListEvents", mock.Anything, int64(1), mock.MatchedBy(func(q models.ListQuery) bool {
+		return q.Limit == 101
+	})).Return(nil, nil).Once()
+
+	rr, resp := serveList(mockService, "/events?from=2025-01-01&to=2025-01-01")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotNil(t, resp.Events)
+	mockService.AssertExpectations(t)
+}
+
+func TestList_Calendars(t *testing.T) {
+	mockService := new(mocks.ListEvents)
+	mockService.On("ListEvents", mock.Anything, int64(1), mock.MatchedBy(func(q models.ListQuery) bool {
+		return assert.ObjectsAreEqual([]int64{2, 5}, q.Calendars)
+	})).Return(nil, nil).Once()
+
+	rr, _ := serveList(mockService, "/events?from=2025-01-01&to=2025-01-31&calendars=2,5")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestList_Owner(t *testing.T) {
+	mockService := new(mocks.ListEvents)
+	mockService.On("ListEvents", mock.Anything, int64(1), mock.MatchedBy(func(q models.ListQuery) bool {
+		return q.Owner == 3
+	})).Return(nil, nil).Once()
+
+	rr, _ := serveList(mockService, "/events?from=2025-01-01&to=2025-01-31&owner_id=3")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestList_InvalidRequest(t *testing.T) {
+	for _, url := range []string{
+		"/events?to=2025-01-31",
+		"/events?from=01.01.2025&to=2025-01-31",
+		"/events?from=2025-02-01&to=2025-01-31",
+		"/events?from=2025-01-01&to=2025-01-31&limit=0",
+		"/events?from=2025-01-01&to=2025-01-31&limit=5000",
+		"/events?from=2025-01-01&to=2025-01-31&cursor=garbage",
+		"/events?from=2025-01-01&to=2025-01-31&calendars=work",
+		"/events?from=2025-01-01&to=2025-01-31&owner_id=-1",
+	} {
+		mockService := new(mocks.ListEvents)
+
+		rr, _ := serveList(mockService, url)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, url)
+		mockService.AssertNotCalled(t, "ListEvents", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestList_ServiceError(t *testing.T) {
+	mockService := new(mocks.ListEvents)
+	mockService.On("ListEvents", mock.Anything, int64(1), mock.Anything).Return(nil, errors.New("db error")).Once()
+
+	rr, resp := serveList(mockService, "/events?from=2025-01-01&to=2025-01-31")
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Equal(t, "failed to list events", resp.Error)
+	mockService.AssertExpectations(t)
+}
@@ -3,10 +3,12 @@
 package mocks
 
 import (
-	models "Events-Service/internal/models"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
 
+	models "Events-Service/internal/models"
+
 	time "time"
 )
 
@@ -15,9 +17,9 @@ type GetEvents struct {
 	mock.Mock
 }
 
-// GetEventsByDay provides a mock function with given fields: userID, date
-func (_m *GetEvents) GetEventsByDay(userID int64, date string) ([]models.Event, error) {
-	ret := _m.Called(userID, date)
+// GetEventsByDay provides a mock function with given fields: ctx, userID, date, filter
+func (_m *GetEvents) GetEventsByDay(ctx context.Context, userID int64, date string, filter models.EventFilter) ([]models.Event, error) {
+	ret := _m.Called(ctx, userID, date, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetEventsByDay")
@@ -25,19 +27,19 @@ func (_m *GetEvents) GetEventsByDay(userID int64, date string) ([]models.Event,
 
 	var r0 []models.Event
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, string) ([]models.Event, error)); ok {
-		return rf(userID, date)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, models.EventFilter) ([]models.Event, error)); ok {
+		return rf(ctx, userID, date, filter)
 	}
-	if rf, ok := ret.Get(0).(func(int64, string) []models.Event); ok {
-		r0 = rf(userID, date)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, models.EventFilter) []models.Event); ok {
+		r0 = rf(ctx, userID, date, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Event)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, string) error); ok {
-		r1 = rf(userID, date)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, models.EventFilter) error); ok {
+		r1 = rf(ctx, userID, date, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -45,9 +47,9 @@ func (_m *GetEvents) GetEventsByDay(userID int64, date string) ([]models.Event,
 	return r0, r1
 }
 
-// GetEventsByMonth provides a mock function with given fields: userID, year, month
-func (_m *GetEvents) GetEventsByMonth(userID int64, year int, month time.Month) ([]models.Event, error) {
-	ret := _m.Called(userID, year, month)
+// GetEventsByMonth provides a mock function with given fields: ctx, userID, year, month, filter
+func (_m *GetEvents) GetEventsByMonth(ctx context.Context, userID int64, year int, month time.Month, filter models.EventFilter) ([]models.Event, error) {
+	ret := _m.Called(ctx, userID, year, month, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetEventsByMonth")
@@ -55,19 +57,19 @@ func (_m *GetEvents) GetEventsByMonth(userID int64, year int, month time.Month)
 
 	var r0 []models.Event
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int, time.Month) ([]models.Event, error)); ok {
-		return rf(userID, year, month)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, time.Month, models.EventFilter) ([]models.Event, error)); ok {
+		return rf(ctx, userID, year, month, filter)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int, time.Month) []models.Event); ok {
-		r0 = rf(userID, year, month)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, time.Month, models.EventFilter) []models.Event); ok {
+		r0 = rf(ctx, userID, year, month, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Event)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int, time.Month) error); ok {
-		r1 = rf(userID, year, month)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, time.Month, models.EventFilter) error); ok {
+		r1 = rf(ctx, userID, year, month, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -75,9 +77,9 @@ func (_m *GetEvents) GetEventsByMonth(userID int64, year int, month time.Month)
 	return r0, r1
 }
 
-// GetEventsByWeek provides a mock function with given fields: userID, date
-func (_m *GetEvents) GetEventsByWeek(userID int64, date time.Time) ([]models.Event, error) {
-	ret := _m.Called(userID, date)
+// GetEventsByWeek provides a mock function with given fields: ctx, userID, date, filter
+func (_m *GetEvents) GetEventsByWeek(ctx context.Context, userID int64, date time.Time, filter models.EventFilter) ([]models.Event, error) {
+	ret := _m.Called(ctx, userID, date, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetEventsByWeek")
@@ -85,19 +87,19 @@ func (_m *GetEvents) GetEventsByWeek(userID int64, date time.Time) ([]models.Eve
 
 	var r0 []models.Event
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, time.Time) ([]models.Event, error)); ok {
-		return rf(userID, date)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, models.EventFilter) ([]models.Event, error)); ok {
+		return rf(ctx, userID, date, filter)
 	}
-	if rf, ok := ret.Get(0).(func(int64, time.Time) []models.Event); ok {
-		r0 = rf(userID, date)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, models.EventFilter) []models.Event); ok {
+		r0 = rf(ctx, userID, date, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Event)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, time.Time) error); ok {
-		r1 = rf(userID, date)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time, models.EventFilter) error); ok {
+		r1 = rf(ctx, userID, date, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "Events-Service/internal/models"
+)
+
+// ListEvents is an autogenerated mock type for the ListEvents type
+type ListEvents struct {
+	mock.Mock
+}
+
+// ListEvents provides a mock function with given fields: ctx, userID, query
+func (_m *ListEvents) ListEvents(ctx context.Context, userID int64, query models.ListQuery) ([]models.Event, error) {
+	ret := _m.Called(ctx, userID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEvents")
+	}
+
+	var r0 []models.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.ListQuery) ([]models.Event, error)); ok {
+		return rf(ctx, userID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.ListQuery) []models.Event); ok {
+		r0 = rf(ctx, userID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.ListQuery) error); ok {
+		r1 = rf(ctx, userID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewListEvents creates a new instance of ListEvents. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewListEvents(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ListEvents {
+	mock := &ListEvents{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,67 @@
+package listTags
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/models"
+	"context"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+type TagResponse struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type Response struct {
+	response.Response
+	Tags []TagResponse `json:"tags"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=ListTags
+type ListTags interface {
+	ListTags(ctx context.Context, userID int64) ([]models.TagCount, error)
+}
+
+func New(log *slog.Logger, tags ListTags) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.event.listTags.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		userTags, err := tags.ListTags(r.Context(), userID)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to list tags")
+
+			return
+		}
+
+		log.Info("got tags", slog.Int("count", len(userTags)))
+
+		responseOK(w, r, userTags)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, tags []models.TagCount) {
+	responseTags := make([]TagResponse, 0, len(tags))
+	for _, tag := range tags {
+		responseTags = append(responseTags, TagResponse{
+			Name:  tag.Name,
+			Count: tag.Count,
+		})
+	}
+
+	render.JSON(w, r, Response{
+		Response: response.OK(),
+		Tags:     responseTags,
+	})
+}
@@ -0,0 +1,82 @@
+package listTags_test
+
+import (
+	"Events-Service/internal/http-server/handlers/event/listTags"
+	"Events-Service/internal/http-server/handlers/event/listTags/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNew_Success(t *testing.T) {
+	mockService := new(mocks.ListTags)
+
+	mockService.On("ListTags", mock.Anything, int64(1)).
+		Return([]models.TagCount{
+			{Name: "work", Count: 5},
+			{Name: "oncall", Count: 2},
+		}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := listTags.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp listTags.Response
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, []listTags.TagResponse{
+		{Name: "work", Count: 5},
+		{Name: "oncall", Count: 2},
+	}, resp.Tags)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_ServiceError(t *testing.T) {
+	mockService := new(mocks.ListTags)
+
+	mockService.On("ListTags", mock.Anything, int64(1)).
+		Return(nil, errors.New("database error")).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := listTags.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_Unauthenticated(t *testing.T) {
+	mockService := new(mocks.ListTags)
+
+	req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := listTags.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	mockService.AssertNotCalled(t, "ListTags", mock.Anything, mock.Anything)
+}
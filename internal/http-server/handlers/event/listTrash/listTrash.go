@@ -0,0 +1,89 @@
+package listTrash
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/eventtime"
+	"Events-Service/internal/models"
+	"context"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// EventResponse — событие в корзине. DeletedAt — момент удаления в UTC (RFC 3339).
+type EventResponse struct {
+	EventId    int64    `json:"event_id"`
+	Date       string   `json:"date"`
+	Text       string   `json:"text"`
+	StartTime  string   `json:"start_time,omitempty"`
+	EndTime    string   `json:"end_time,omitempty"`
+	TimeZone   string   `json:"time_zone,omitempty"`
+	Recurrence string   `json:"rrule,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	DeletedAt  string   `json:"deleted_at"`
+}
+
+type Response struct {
+	response.Response
+	Events []EventResponse `json:"events"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=ListTrash
+type ListTrash interface {
+	ListTrash(ctx context.Context, userID int64) ([]models.Event, error)
+}
+
+func New(log *slog.Logger, trash ListTrash) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.event.listTrash.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		events, err := trash.ListTrash(r.Context(), userID)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to list trash")
+
+			return
+		}
+
+		log.Info("got trash", slog.Int("count", len(events)))
+
+		responseOK(w, r, events)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, events []models.Event) {
+	responseEvents := make([]EventResponse, 0, len(events))
+	for _, e := range events {
+		var deletedAt string
+		if e.DeletedAt != nil {
+			deletedAt = e.DeletedAt.UTC().Format(time.RFC3339)
+		}
+
+		responseEvents = append(responseEvents, EventResponse{
+			EventId:    e.ID,
+			Date:       e.Date,
+			Text:       e.Text,
+			StartTime:  eventtime.Format(e.StartsAt, e.TimeZone),
+			EndTime:    eventtime.Format(e.EndsAt, e.TimeZone),
+			TimeZone:   e.TimeZone,
+			Recurrence: e.Recurrence,
+			Tags:       e.Tags,
+			DeletedAt:  deletedAt,
+		})
+	}
+
+	render.JSON(w, r, Response{
+		Response: response.OK(),
+		Events:   responseEvents,
+	})
+}
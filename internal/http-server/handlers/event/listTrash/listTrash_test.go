@@ -0,0 +1,82 @@
+package listTrash_test
+
+import (
+	"Events-Service/internal/http-server/handlers/event/listTrash"
+	"Events-Service/internal/http-server/handlers/event/listTrash/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNew_Success(t *testing.T) {
+	mockService := new(mocks.ListTrash)
+
+	deletedAt := time.Date(2025, 3, 14, 9, 30, 0, 0, time.UTC)
+	mockService.On("ListTrash", mock.Anything, int64(1)).
+		Return([]models.Event{
+			{ID: 7, UserID: 1, Date: "2025-03-20", Text: "Dentist", Tags: []string{"health"}, DeletedAt: &deletedAt},
+		}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/trash", nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := listTrash.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp listTrash.Response
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, []listTrash.EventResponse{
+		{EventId: 7, Date: "2025-03-20", Text: "Dentist", Tags: []string{"health"}, DeletedAt: "2025-03-14T09:30:00Z"},
+	}, resp.Events)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_ServiceError(t *testing.T) {
+	mockService := new(mocks.ListTrash)
+
+	mockService.On("ListTrash", mock.Anything, int64(1)).
+		Return(nil, errors.New("database error")).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/trash", nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := listTrash.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_Unauthenticated(t *testing.T) {
+	mockService := new(mocks.ListTrash)
+
+	req := httptest.NewRequest(http.MethodGet, "/trash", nil)
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := listTrash.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	mockService.AssertNotCalled(t, "ListTrash", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,47 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "Events-Service/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RespondEvent is an autogenerated mock type for the RespondEvent type
+type RespondEvent struct {
+	mock.Mock
+}
+
+// RespondToEvent provides a mock function with given fields: ctx, userID, eventID, status
+func (_m *RespondEvent) RespondToEvent(ctx context.Context, userID int64, eventID int64, status models.AttendeeStatus) error {
+	ret := _m.Called(ctx, userID, eventID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RespondToEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, models.AttendeeStatus) error); ok {
+		r0 = rf(ctx, userID, eventID, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRespondEvent creates a new instance of RespondEvent. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRespondEvent(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RespondEvent {
+	mock := &RespondEvent{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
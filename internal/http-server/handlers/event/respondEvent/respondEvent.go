@@ -0,0 +1,85 @@
+package respondEvent
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+// Request — ответ на приглашение: accepted, declined или tentative. Ответ можно менять.
+type Request struct {
+	EventId int64  `json:"event_id" validate:"required"`
+	Status  string `json:"status" validate:"required,oneof=accepted declined tentative"`
+}
+
+type Response struct {
+	response.Response
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=RespondEvent
+type RespondEvent interface {
+	RespondToEvent(ctx context.Context, userID, eventID int64, status models.AttendeeStatus) error
+}
+
+func New(log *slog.Logger, event RespondEvent) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.event.respondEvent.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		err = event.RespondToEvent(r.Context(), userID, req.EventId, models.AttendeeStatus(req.Status))
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to respond to event")
+
+			return
+		}
+
+		log.Info("event invitation answered", slog.Int64("id", req.EventId), slog.String("status", req.Status))
+
+		responseOK(w, r)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, Response{
+		Response: response.OK(),
+	})
+}
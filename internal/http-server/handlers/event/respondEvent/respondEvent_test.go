@@ -0,0 +1,61 @@
+package respondEvent_test
+
+import (
+	"Events-Service/internal/http-server/handlers/event/respondEvent"
+	"Events-Service/internal/http-server/handlers/event/respondEvent/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		callsStore bool
+		wantStatus int
+	}{
+		{name: "accepted", body: `{"event_id": 7, "status": "accepted"}`, callsStore: true, wantStatus: http.StatusOK},
+		{name: "not invited", body: `{"event_id": 7, "status": "accepted"}`, serviceErr: storage.ErrInvitationNotFound, callsStore: true, wantStatus: http.StatusNotFound},
+		{name: "storage error", body: `{"event_id": 7, "status": "accepted"}`, serviceErr: errors.New("database error"), callsStore: true, wantStatus: http.StatusInternalServerError},
+		{name: "missing status", body: `{"event_id": 7}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown status", body: `{"event_id": 7, "status": "needs-action"}`, wantStatus: http.StatusBadRequest},
+		{name: "missing event id", body: `{"status": "declined"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.RespondEvent)
+			if tt.callsStore {
+				mockService.On("RespondToEvent", mock.Anything, int64(1), int64(7), models.StatusAccepted).Return(tt.serviceErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/rsvp", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			handler := respondEvent.New(testLogger, mockService)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+
+			var resp respondEvent.Response
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
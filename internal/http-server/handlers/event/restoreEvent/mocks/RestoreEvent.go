@@ -0,0 +1,46 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RestoreEvent is an autogenerated mock type for the RestoreEvent type
+type RestoreEvent struct {
+	mock.Mock
+}
+
+// RestoreEvent provides a mock function with given fields: ctx, userID, eventID
+func (_m *RestoreEvent) RestoreEvent(ctx context.Context, userID int64, eventID int64) error {
+	ret := _m.Called(ctx, userID, eventID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userID, eventID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRestoreEvent creates a new instance of RestoreEvent. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRestoreEvent(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RestoreEvent {
+	mock := &RestoreEvent{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+package restoreEvent_test
+
+import (
+	"Events-Service/internal/http-server/handlers/event/restoreEvent"
+	"Events-Service/internal/http-server/handlers/event/restoreEvent/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/storage"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		callsStore bool
+		wantStatus int
+	}{
+		{name: "restored", body: `{"event_id": 7}`, callsStore: true, wantStatus: http.StatusOK},
+		{name: "not in trash", body: `{"event_id": 7}`, serviceErr: storage.ErrEventNotFound, callsStore: true, wantStatus: http.StatusNotFound},
+		{name: "storage error", body: `{"event_id": 7}`, serviceErr: errors.New("database error"), callsStore: true, wantStatus: http.StatusInternalServerError},
+		{name: "missing event id", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid json", body: `{"event_id": `, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.RestoreEvent)
+			if tt.callsStore {
+				mockService.On("RestoreEvent", mock.Anything, int64(1), int64(7)).Return(tt.serviceErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/restore_event", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			handler := restoreEvent.New(testLogger, mockService)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+
+			var resp restoreEvent.Response
+			assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "Events-Service/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SearchEvents is an autogenerated mock type for the SearchEvents type
+type SearchEvents struct {
+	mock.Mock
+}
+
+// SearchEvents provides a mock function with given fields: ctx, userID, query
+func (_m *SearchEvents) SearchEvents(ctx context.Context, userID int64, query models.SearchQuery) ([]models.SearchResult, error) {
+	ret := _m.Called(ctx, userID, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchEvents")
+	}
+
+	var r0 []models.SearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.SearchQuery) ([]models.SearchResult, error)); ok {
+		return rf(ctx, userID, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.SearchQuery) []models.SearchResult); ok {
+		r0 = rf(ctx, userID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.SearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.SearchQuery) error); ok {
+		r1 = rf(ctx, userID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSearchEvents creates a new instance of SearchEvents. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSearchEvents(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SearchEvents {
+	mock := &SearchEvents{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,143 @@
+package searchEvents
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/eventtime"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+const defaultLimit = 20
+
+// Request ищет события, текст которых содержит все слова Query.
+// From и To (включительно) ограничивают поиск событиями, которые занимают хотя бы один день диапазона.
+type Request struct {
+	Query string `json:"query" validate:"required,max=256"`
+	From  string `json:"from,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	To    string `json:"to,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Limit int    `json:"limit,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// ResultResponse — найденное событие. В Snippet найденные слова выделены тегами <b>,
+// Rank сравним только внутри одного ответа.
+type ResultResponse struct {
+	EventId    int64    `json:"event_id"`
+	Date       string   `json:"date"`
+	Text       string   `json:"text"`
+	StartTime  string   `json:"start_time,omitempty"`
+	EndTime    string   `json:"end_time,omitempty"`
+	TimeZone   string   `json:"time_zone,omitempty"`
+	Recurrence string   `json:"rrule,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Rank       float64  `json:"rank"`
+	Snippet    string   `json:"snippet"`
+}
+
+type Response struct {
+	response.Response
+	Results []ResultResponse `json:"results"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=SearchEvents
+type SearchEvents interface {
+	SearchEvents(ctx context.Context, userID int64, query models.SearchQuery) ([]models.SearchResult, error)
+}
+
+func New(log *slog.Logger, search SearchEvents) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.event.searchEvents.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		if req.From != "" && req.To != "" && req.From > req.To {
+			log.Error("invalid date range", slog.String("from", req.From), slog.String("to", req.To))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("from must not be after to"))
+
+			return
+		}
+
+		limit := req.Limit
+		if limit == 0 {
+			limit = defaultLimit
+		}
+
+		results, err := search.SearchEvents(r.Context(), userID, models.SearchQuery{
+			Text:  req.Query,
+			From:  req.From,
+			To:    req.To,
+			Limit: limit,
+		})
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to search events")
+
+			return
+		}
+
+		log.Info("events found", slog.Int("count", len(results)))
+
+		responseOK(w, r, results)
+	}
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, results []models.SearchResult) {
+	responseResults := make([]ResultResponse, 0, len(results))
+	for _, res := range results {
+		e := res.Event
+		responseResults = append(responseResults, ResultResponse{
+			EventId:    e.ID,
+			Date:       e.Date,
+			Text:       e.Text,
+			StartTime:  eventtime.Format(e.StartsAt, e.TimeZone),
+			EndTime:    eventtime.Format(e.EndsAt, e.TimeZone),
+			TimeZone:   e.TimeZone,
+			Recurrence: e.Recurrence,
+			Tags:       e.Tags,
+			Rank:       res.Rank,
+			Snippet:    res.Snippet,
+		})
+	}
+
+	render.JSON(w, r, Response{
+		Response: response.OK(),
+		Results:  responseResults,
+	})
+}
@@ -0,0 +1,91 @@
+package searchEvents_test
+
+import (
+	"Events-Service/internal/http-server/handlers/event/searchEvents"
+	"Events-Service/internal/http-server/handlers/event/searchEvents/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNew_Success(t *testing.T) {
+	mockService := new(mocks.SearchEvents)
+
+	mockService.On("SearchEvents", mock.Anything, int64(1), models.SearchQuery{Text: "dentist", From: "2025-01-01", Limit: 20}).
+		Return([]models.SearchResult{
+			{
+				Event:   models.Event{ID: 7, Date: "2025-03-14", Text: "Dentist appointment"},
+				Rank:    0.5,
+				Snippet: "<b>Dentist</b> appointment",
+			},
+		}, nil).Once()
+
+	body, _ := json.Marshal(searchEvents.Request{Query: "dentist", From: "2025-01-01"})
+	req := httptest.NewRequest(http.MethodGet, "/search_events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := searchEvents.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp searchEvents.Response
+	err := json.Unmarshal(rr.Body.Bytes(), &resp)
+	assert.NoError(t, err)
+	assert.Equal(t, []searchEvents.ResultResponse{
+		{EventId: 7, Date: "2025-03-14", Text: "Dentist appointment", Rank: 0.5, Snippet: "<b>Dentist</b> appointment"},
+	}, resp.Results)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_ServiceError(t *testing.T) {
+	mockService := new(mocks.SearchEvents)
+
+	mockService.On("SearchEvents", mock.Anything, int64(1), mock.AnythingOfType("models.SearchQuery")).
+		Return(nil, errors.New("database error")).Once()
+
+	body, _ := json.Marshal(searchEvents.Request{Query: "dentist"})
+	req := httptest.NewRequest(http.MethodGet, "/search_events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := searchEvents.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_InvalidRange(t *testing.T) {
+	mockService := new(mocks.SearchEvents)
+
+	body, _ := json.Marshal(searchEvents.Request{Query: "dentist", From: "2025-05-01", To: "2025-04-01"})
+	req := httptest.NewRequest(http.MethodGet, "/search_events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := searchEvents.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockService.AssertNotCalled(t, "SearchEvents", mock.Anything)
+}
@@ -2,29 +2,51 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	models "Events-Service/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // UpdateEvent is an autogenerated mock type for the UpdateEvent type
 type UpdateEvent struct {
 	mock.Mock
 }
 
-// UpdateEvent provides a mock function with given fields: userID, eventID, dateStr, text
-func (_m *UpdateEvent) UpdateEvent(userID int64, eventID int64, dateStr string, text string) error {
-	ret := _m.Called(userID, eventID, dateStr, text)
+// UpdateEvent provides a mock function with given fields: ctx, event, scope, occurrenceDate
+func (_m *UpdateEvent) UpdateEvent(ctx context.Context, event models.Event, scope models.Scope, occurrenceDate string) (int64, int64, error) {
+	ret := _m.Called(ctx, event, scope, occurrenceDate)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateEvent")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(int64, int64, string, string) error); ok {
-		r0 = rf(userID, eventID, dateStr, text)
+	var r0 int64
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Event, models.Scope, string) (int64, int64, error)); ok {
+		return rf(ctx, event, scope, occurrenceDate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Event, models.Scope, string) int64); ok {
+		r0 = rf(ctx, event, scope, occurrenceDate)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Event, models.Scope, string) int64); ok {
+		r1 = rf(ctx, event, scope, occurrenceDate)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, models.Event, models.Scope, string) error); ok {
+		r2 = rf(ctx, event, scope, occurrenceDate)
 	} else {
-		r0 = ret.Error(0)
+		r2 = ret.Error(2)
 	}
 
-	return r0
+	return r0, r1, r2
 }
 
 // NewUpdateEvent creates a new instance of UpdateEvent. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
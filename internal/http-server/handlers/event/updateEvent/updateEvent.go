@@ -1,9 +1,14 @@
 package updateEvent
 
 import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/etag"
 	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/eventtime"
 	"Events-Service/internal/lib/logger/sl"
-	"Events-Service/internal/storage"
+	"Events-Service/internal/lib/rrule"
+	"Events-Service/internal/models"
+	"context"
 	"errors"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
@@ -11,30 +16,62 @@ import (
 	"net/http"
 )
 
+// Request заменяет время события целиком: только Date делает событие событием на весь день,
+// StartTime и EndTime — событием со временем.
 type Request struct {
-	UserId  int64  `json:"user_id" validate:"required"`
-	EventId int64  `json:"event_id" validate:"required"`
-	Date    string `json:"date" validate:"required"`
-	Text    string `json:"text" validate:"required"`
+	EventId   int64  `json:"event_id" validate:"required"`
+	Date      string `json:"date,omitempty" validate:"required_without=StartTime"`
+	Text      string `json:"text" validate:"required"`
+	StartTime string `json:"start_time,omitempty" validate:"required_with=EndTime"`
+	EndTime   string `json:"end_time,omitempty" validate:"required_with=StartTime"`
+	TimeZone  string `json:"time_zone,omitempty"`
+	// Recurrence — правило повторения RRULE, например "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=10".
+	// Без поля правило не меняется, при scope following новая серия продолжает правило старой.
+	Recurrence string `json:"rrule,omitempty"`
+	// Tags — теги события, регистр не учитывается. Без поля теги не меняются, пустой массив удаляет их.
+	Tags []string `json:"tags,omitempty" validate:"dive,max=64"`
+	// Attendees — ID приглашенных пользователей. Без поля участники не меняются, пустой массив удаляет их,
+	// а оставшиеся участники сохраняют свои ответы.
+	Attendees []int64 `json:"attendees,omitempty" validate:"dive,min=1"`
+	// Reminders — за сколько минут до начала напомнить о событии. Без поля напоминания не меняются,
+	// пустой массив удаляет их.
+	Reminders []int `json:"reminders,omitempty" validate:"dive,min=0,max=40320"`
+	// CalendarId переносит событие в другой календарь, для одного повторения серии недоступен.
+	CalendarId int64 `json:"calendar_id,omitempty"`
+	// Scope — что менять в серии: this (одно повторение), following (это и следующие) или all (по умолчанию).
+	Scope string `json:"scope,omitempty"`
+	// OccurrenceDate — исходная дата повторения, обязательна для scope this и following.
+	OccurrenceDate string `json:"occurrence_date,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	// ExpectedVersion — версия, которую клиент видел последней. Вместо нее можно передать заголовок If-Match.
+	ExpectedVersion int64 `json:"expected_version,omitempty" validate:"omitempty,min=1"`
 }
 
+// Response возвращает ID и новую версию события с изменениями: при scope following это новая серия.
+// Версия дублируется в заголовке ETag.
 type Response struct {
 	response.Response
+	EventId int64 `json:"event_id,omitempty"`
+	Version int64 `json:"version,omitempty"`
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=UpdateEvent
 type UpdateEvent interface {
-	UpdateEvent(userID, eventID int64, dateStr, text string) error
+	UpdateEvent(ctx context.Context, event models.Event, scope models.Scope, occurrenceDate string) (eventID, version int64, err error)
 }
 
 func New(log *slog.Logger, event UpdateEvent) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.event.updateEvent.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 		)
 
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
 		var req Request
 
 		err := render.DecodeJSON(r.Body, &req)
@@ -59,31 +96,81 @@ func New(log *slog.Logger, event UpdateEvent) http.HandlerFunc {
 			return
 		}
 
-		eventId := req.EventId
-		err = event.UpdateEvent(req.UserId, req.EventId, req.Date, req.Text)
-		if errors.Is(err, storage.ErrEventNotFound) {
-			log.Info("event not found", slog.Int64("event", eventId))
-			render.Status(r, http.StatusServiceUnavailable)
-			render.JSON(w, r, response.Error("event not found"))
+		scope, err := models.ParseScope(req.Scope)
+		if err == nil && scope != models.ScopeAll && req.OccurrenceDate == "" {
+			err = errors.New("occurrence_date is required for scope " + string(scope))
+		}
+		if err != nil {
+			log.Error("invalid scope", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
 
 			return
 		}
+
+		expectedVersion, err := etag.ExpectedVersion(r, req.ExpectedVersion)
+		if err != nil {
+			log.Error("invalid expected version", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+
+			return
+		}
+
+		timing, err := eventtime.Parse(req.Date, req.StartTime, req.EndTime, req.TimeZone)
+		if err != nil {
+			log.Error("invalid event time", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+
+			return
+		}
+
+		var recurrence string
+		if req.Recurrence != "" {
+			rule, err := rrule.Parse(req.Recurrence)
+			if err != nil {
+				log.Error("invalid recurrence rule", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, response.Error("invalid rrule: "+err.Error()))
+
+				return
+			}
+			recurrence = rule.String()
+		}
+
+		eventId, version, err := event.UpdateEvent(r.Context(), models.Event{
+			ID:         req.EventId,
+			UserID:     userID,
+			CalendarID: req.CalendarId,
+			Date:       timing.Date,
+			Text:       req.Text,
+			StartsAt:   timing.StartsAt,
+			EndsAt:     timing.EndsAt,
+			TimeZone:   timing.TimeZone,
+			Recurrence: recurrence,
+			Tags:       models.NormalizeTags(req.Tags),
+			Attendees:  models.Invite(req.Attendees),
+			Reminders:  models.RemindBefore(req.Reminders),
+			Version:    expectedVersion,
+		}, scope, req.OccurrenceDate)
 		if err != nil {
-			log.Error("failed to update event", sl.Err(err))
-			render.Status(r, http.StatusInternalServerError)
-			render.JSON(w, r, response.Error("failed to update event"))
+			response.StorageError(w, r, log, err, "failed to update event")
 
 			return
 		}
 
 		log.Info("event updated", slog.Int64("id", eventId))
 
-		responseOK(w, r)
+		responseOK(w, r, eventId, version)
 	}
 }
 
-func responseOK(w http.ResponseWriter, r *http.Request) {
+func responseOK(w http.ResponseWriter, r *http.Request, eventId, version int64) {
+	w.Header().Set("ETag", etag.Format(version))
 	render.JSON(w, r, Response{
 		Response: response.OK(),
+		EventId:  eventId,
+		Version:  version,
 	})
 }
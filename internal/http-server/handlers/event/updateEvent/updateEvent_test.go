@@ -2,6 +2,7 @@ package updateEvent_test
 
 import (
 	"Events-Service/internal/http-server/handlers/event/updateEvent"
+	"Events-Service/internal/http-server/middleware/auth"
 	"bytes"
 	"encoding/json"
 	"errors"
@@ -9,8 +10,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"Events-Service/internal/http-server/handlers/event/updateEvent/mocks"
+	"Events-Service/internal/models"
 	"Events-Service/internal/storage"
 
 	"github.com/stretchr/testify/assert"
@@ -20,17 +23,17 @@ import (
 func TestNew_Success(t *testing.T) {
 	mockService := new(mocks.UpdateEvent)
 
-	mockService.On("UpdateEvent", mock.AnythingOfType("int64"), mock.AnythingOfType("int64"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).
-		Return(nil).Once()
+	mockService.On("UpdateEvent", mock.Anything, mock.AnythingOfType("models.Event"), models.ScopeAll, "").
+		Return(int64(101), int64(2), nil).Once()
 
 	requestBody := updateEvent.Request{
-		UserId:  1,
 		EventId: 101,
 		Date:    "2025-08-05",
 		Text:    "Updated event",
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodPut, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
@@ -39,24 +42,79 @@ func TestNew_Success(t *testing.T) {
 	handler.ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+
+	var resp updateEvent.Response
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, int64(2), resp.Version)
 
 	mockService.AssertExpectations(t)
 }
 
+func TestNew_IfMatch(t *testing.T) {
+	tests := []struct {
+		name            string
+		ifMatch         string
+		expectedVersion int64
+		serviceErr      error
+		wantVersion     int64
+		wantStatus      int
+	}{
+		{name: "header", ifMatch: `"3"`, wantVersion: 3, wantStatus: http.StatusOK},
+		{name: "body", expectedVersion: 3, wantVersion: 3, wantStatus: http.StatusOK},
+		{name: "mismatch", ifMatch: `"3"`, wantVersion: 3, serviceErr: storage.ErrVersionMismatch, wantStatus: http.StatusPreconditionFailed},
+		{name: "header and body disagree", ifMatch: `"3"`, expectedVersion: 2, wantStatus: http.StatusBadRequest},
+		{name: "invalid header", ifMatch: "3", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.UpdateEvent)
+			if tt.wantVersion != 0 {
+				mockService.On("UpdateEvent", mock.Anything, mock.MatchedBy(func(e models.Event) bool {
+					return e.Version == tt.wantVersion
+				}), models.ScopeAll, "").Return(int64(101), int64(4), tt.serviceErr).Once()
+			}
+
+			body, _ := json.Marshal(updateEvent.Request{
+				EventId:         101,
+				Date:            "2025-08-05",
+				Text:            "Updated event",
+				ExpectedVersion: tt.expectedVersion,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/update_event", bytes.NewReader(body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			rr := httptest.NewRecorder()
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			handler := updateEvent.New(testLogger, mockService)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestNew_EventNotFound(t *testing.T) {
 	mockService := new(mocks.UpdateEvent)
 
-	mockService.On("UpdateEvent", mock.AnythingOfType("int64"), mock.AnythingOfType("int64"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).
-		Return(storage.ErrEventNotFound).Once()
+	mockService.On("UpdateEvent", mock.Anything, mock.AnythingOfType("models.Event"), models.ScopeAll, "").
+		Return(int64(0), int64(0), storage.ErrEventNotFound).Once()
 
 	requestBody := updateEvent.Request{
-		UserId:  1,
 		EventId: 999,
 		Date:    "2025-08-05",
 		Text:    "Updated event",
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodPut, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
@@ -64,7 +122,7 @@ func TestNew_EventNotFound(t *testing.T) {
 	handler := updateEvent.New(testLogger, mockService)
 	handler.ServeHTTP(rr, req)
 
-	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
 
 	mockService.AssertExpectations(t)
 }
@@ -72,17 +130,17 @@ func TestNew_EventNotFound(t *testing.T) {
 func TestNew_InternalServerError(t *testing.T) {
 	mockService := new(mocks.UpdateEvent)
 
-	mockService.On("UpdateEvent", mock.AnythingOfType("int64"), mock.AnythingOfType("int64"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).
-		Return(errors.New("database connection failed")).Once()
+	mockService.On("UpdateEvent", mock.Anything, mock.AnythingOfType("models.Event"), models.ScopeAll, "").
+		Return(int64(0), int64(0), errors.New("database connection failed")).Once()
 
 	requestBody := updateEvent.Request{
-		UserId:  1,
 		EventId: 101,
 		Date:    "2025-08-05",
 		Text:    "Updated event",
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodPut, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
@@ -99,13 +157,69 @@ func TestNew_ValidationError(t *testing.T) {
 	mockService := new(mocks.UpdateEvent)
 
 	requestBody := updateEvent.Request{
-		UserId:  0,
 		EventId: 0,
 		Date:    "",
 		Text:    "",
 	}
 	body, _ := json.Marshal(requestBody)
 	req := httptest.NewRequest(http.MethodPut, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := updateEvent.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockService.AssertNotCalled(t, "UpdateEvent", mock.Anything)
+}
+
+func TestNew_FollowingOccurrences(t *testing.T) {
+	mockService := new(mocks.UpdateEvent)
+
+	mockService.On("UpdateEvent", mock.Anything, mock.AnythingOfType("models.Event"), models.ScopeFollowing, "2025-09-11").
+		Return(int64(102), int64(1), nil).Once()
+
+	requestBody := updateEvent.Request{
+		EventId:        101,
+		Date:           "2025-09-12",
+		Text:           "Standup",
+		Scope:          "following",
+		OccurrenceDate: "2025-09-11",
+	}
+	body, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodPut, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := updateEvent.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp updateEvent.Response
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, int64(102), resp.EventId)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_ScopeWithoutOccurrenceDate(t *testing.T) {
+	mockService := new(mocks.UpdateEvent)
+
+	requestBody := updateEvent.Request{
+		EventId: 101,
+		Date:    "2025-09-12",
+		Text:    "Standup",
+		Scope:   "this",
+	}
+	body, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodPut, "/events", bytes.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
@@ -115,5 +229,73 @@ func TestNew_ValidationError(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 
-	mockService.AssertNotCalled(t, "UpdateEvent")
+	mockService.AssertNotCalled(t, "UpdateEvent", mock.Anything)
+}
+
+func TestNew_Attendees(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		wantAttendees []models.Attendee
+	}{
+		{name: "unchanged", body: `{"event_id": 101, "date": "2025-08-05", "text": "Planning"}`},
+		{name: "removed", body: `{"event_id": 101, "date": "2025-08-05", "text": "Planning", "attendees": []}`, wantAttendees: []models.Attendee{}},
+		{
+			name:          "replaced",
+			body:          `{"event_id": 101, "date": "2025-08-05", "text": "Planning", "attendees": [4]}`,
+			wantAttendees: []models.Attendee{{UserID: 4, Status: models.StatusNeedsAction}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.UpdateEvent)
+			mockService.On("UpdateEvent", mock.Anything, mock.MatchedBy(func(e models.Event) bool {
+				return assert.ObjectsAreEqual(tt.wantAttendees, e.Attendees)
+			}), models.ScopeAll, "").Return(int64(101), int64(2), nil).Once()
+
+			req := httptest.NewRequest(http.MethodPut, "/events", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			updateEvent.New(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNew_Reminders(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		wantReminders []time.Duration
+	}{
+		{name: "unchanged", body: `{"event_id": 101, "date": "2025-08-05", "text": "Planning"}`},
+		{name: "removed", body: `{"event_id": 101, "date": "2025-08-05", "text": "Planning", "reminders": []}`, wantReminders: []time.Duration{}},
+		{name: "replaced", body: `{"event_id": 101, "date": "2025-08-05", "text": "Planning", "reminders": [30]}`, wantReminders: []time.Duration{30 * time.Minute}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.UpdateEvent)
+			mockService.On("UpdateEvent", mock.Anything, mock.MatchedBy(func(e models.Event) bool {
+				return assert.ObjectsAreEqual(tt.wantReminders, e.Reminders)
+			}), models.ScopeAll, "").Return(int64(101), int64(2), nil).Once()
+
+			req := httptest.NewRequest(http.MethodPut, "/events", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			updateEvent.New(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
 }
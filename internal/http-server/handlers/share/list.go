@@ -0,0 +1,69 @@
+package share
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/models"
+	"context"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type ShareResponse struct {
+	CalendarId int64  `json:"calendar_id"`
+	UserId     int64  `json:"user_id"`
+	Access     string `json:"access"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type ListResponse struct {
+	response.Response
+	Shares []ShareResponse `json:"shares"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=ShareLister
+type ShareLister interface {
+	ListShares(ctx context.Context, ownerID int64) ([]models.Share, error)
+}
+
+// List возвращает доступы, выданные к календарям пользователя.
+func List(log *slog.Logger, shareLister ShareLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.share.List"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		shares, err := shareLister.ListShares(r.Context(), userID)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to list shares")
+
+			return
+		}
+
+		log.Info("got shares", slog.Int("count", len(shares)))
+
+		responseShares := make([]ShareResponse, 0, len(shares))
+		for _, share := range shares {
+			responseShares = append(responseShares, ShareResponse{
+				CalendarId: share.CalendarID,
+				UserId:     share.UserID,
+				Access:     string(share.Access),
+				CreatedAt:  share.CreatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+
+		render.JSON(w, r, ListResponse{
+			Response: response.OK(),
+			Shares:   responseShares,
+		})
+	}
+}
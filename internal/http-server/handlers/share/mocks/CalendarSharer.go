@@ -0,0 +1,47 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "Events-Service/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CalendarSharer is an autogenerated mock type for the CalendarSharer type
+type CalendarSharer struct {
+	mock.Mock
+}
+
+// ShareCalendar provides a mock function with given fields: ctx, _a1
+func (_m *CalendarSharer) ShareCalendar(ctx context.Context, _a1 models.Share) error {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ShareCalendar")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Share) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCalendarSharer creates a new instance of CalendarSharer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCalendarSharer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CalendarSharer {
+	mock := &CalendarSharer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
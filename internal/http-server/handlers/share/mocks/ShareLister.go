@@ -0,0 +1,59 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "Events-Service/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ShareLister is an autogenerated mock type for the ShareLister type
+type ShareLister struct {
+	mock.Mock
+}
+
+// ListShares provides a mock function with given fields: ctx, ownerID
+func (_m *ShareLister) ListShares(ctx context.Context, ownerID int64) ([]models.Share, error) {
+	ret := _m.Called(ctx, ownerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListShares")
+	}
+
+	var r0 []models.Share
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.Share, error)); ok {
+		return rf(ctx, ownerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.Share); ok {
+		r0 = rf(ctx, ownerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Share)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, ownerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewShareLister creates a new instance of ShareLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewShareLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ShareLister {
+	mock := &ShareLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
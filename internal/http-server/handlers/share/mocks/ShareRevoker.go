@@ -0,0 +1,46 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ShareRevoker is an autogenerated mock type for the ShareRevoker type
+type ShareRevoker struct {
+	mock.Mock
+}
+
+// RevokeShare provides a mock function with given fields: ctx, ownerID, calendarID, userID
+func (_m *ShareRevoker) RevokeShare(ctx context.Context, ownerID int64, calendarID int64, userID int64) error {
+	ret := _m.Called(ctx, ownerID, calendarID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeShare")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) error); ok {
+		r0 = rf(ctx, ownerID, calendarID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewShareRevoker creates a new instance of ShareRevoker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewShareRevoker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ShareRevoker {
+	mock := &ShareRevoker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
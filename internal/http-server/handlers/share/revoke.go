@@ -0,0 +1,73 @@
+package share
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+type RevokeRequest struct {
+	CalendarId int64 `json:"calendar_id" validate:"required"`
+	UserId     int64 `json:"user_id" validate:"required"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=ShareRevoker
+type ShareRevoker interface {
+	RevokeShare(ctx context.Context, ownerID, calendarID, userID int64) error
+}
+
+// Revoke закрывает пользователю доступ к календарю.
+func Revoke(log *slog.Logger, shareRevoker ShareRevoker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.share.Revoke"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req RevokeRequest
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		if err = shareRevoker.RevokeShare(r.Context(), userID, req.CalendarId, req.UserId); err != nil {
+			response.StorageError(w, r, log, err, "failed to revoke share")
+
+			return
+		}
+
+		log.Info("share revoked", slog.Int64("id", req.CalendarId), slog.Int64("user_id", req.UserId))
+
+		render.JSON(w, r, response.OK())
+	}
+}
@@ -0,0 +1,82 @@
+package share
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	CalendarId int64  `json:"calendar_id" validate:"required"`
+	UserId     int64  `json:"user_id" validate:"required"`
+	Access     string `json:"access" validate:"required,oneof=freebusy read write"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=CalendarSharer
+type CalendarSharer interface {
+	ShareCalendar(ctx context.Context, share models.Share) error
+}
+
+// New открывает календарь пользователю: freebusy — только занятость, read — события целиком,
+// write — еще и их изменение. Повторный запрос меняет доступ.
+func New(log *slog.Logger, calendarSharer CalendarSharer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.share.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		err = calendarSharer.ShareCalendar(r.Context(), models.Share{
+			CalendarID: req.CalendarId,
+			OwnerID:    userID,
+			UserID:     req.UserId,
+			Access:     models.Access(req.Access),
+		})
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to share calendar")
+
+			return
+		}
+
+		log.Info("calendar shared", slog.Int64("id", req.CalendarId), slog.Int64("user_id", req.UserId))
+
+		render.JSON(w, r, response.OK())
+	}
+}
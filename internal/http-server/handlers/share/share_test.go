@@ -0,0 +1,120 @@
+package share_test
+
+import (
+	"Events-Service/internal/http-server/handlers/share"
+	"Events-Service/internal/http-server/handlers/share/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		callsStore bool
+		wantStatus int
+	}{
+		{name: "shared", body: `{"calendar_id": 2, "user_id": 5, "access": "read"}`, callsStore: true, wantStatus: http.StatusOK},
+		{name: "calendar not found", body: `{"calendar_id": 2, "user_id": 5, "access": "read"}`, serviceErr: storage.ErrCalendarNotFound, callsStore: true, wantStatus: http.StatusNotFound},
+		{name: "user not found", body: `{"calendar_id": 2, "user_id": 5, "access": "read"}`, serviceErr: storage.ErrUserNotFound, callsStore: true, wantStatus: http.StatusNotFound},
+		{name: "shared with owner", body: `{"calendar_id": 2, "user_id": 5, "access": "read"}`, serviceErr: storage.ErrShareOwner, callsStore: true, wantStatus: http.StatusBadRequest},
+		{name: "missing user", body: `{"calendar_id": 2, "access": "read"}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown access", body: `{"calendar_id": 2, "user_id": 5, "access": "admin"}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.CalendarSharer)
+			if tt.callsStore {
+				mockService.On("ShareCalendar", mock.Anything, models.Share{
+					CalendarID: 2, OwnerID: 1, UserID: 5, Access: models.AccessRead,
+				}).Return(tt.serviceErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/share_calendar", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			share.New(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestList_Success(t *testing.T) {
+	created := time.Date(2025, 8, 5, 10, 0, 0, 0, time.UTC)
+
+	mockService := new(mocks.ShareLister)
+	mockService.On("ListShares", mock.Anything, int64(1)).Return([]models.Share{
+		{CalendarID: 2, OwnerID: 1, UserID: 5, Access: models.AccessFreeBusy, CreatedAt: created},
+		{CalendarID: 2, OwnerID: 1, UserID: 6, Access: models.AccessWrite, CreatedAt: created},
+	}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/shares", nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	share.List(testLogger, mockService).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp share.ListResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, []share.ShareResponse{
+		{CalendarId: 2, UserId: 5, Access: "freebusy", CreatedAt: "2025-08-05T10:00:00Z"},
+		{CalendarId: 2, UserId: 6, Access: "write", CreatedAt: "2025-08-05T10:00:00Z"},
+	}, resp.Shares)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRevoke(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		callsStore bool
+		wantStatus int
+	}{
+		{name: "revoked", body: `{"calendar_id": 2, "user_id": 5}`, callsStore: true, wantStatus: http.StatusOK},
+		{name: "share not found", body: `{"calendar_id": 2, "user_id": 5}`, serviceErr: storage.ErrShareNotFound, callsStore: true, wantStatus: http.StatusNotFound},
+		{name: "missing calendar id", body: `{"user_id": 5}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.ShareRevoker)
+			if tt.callsStore {
+				mockService.On("RevokeShare", mock.Anything, int64(1), int64(2), int64(5)).Return(tt.serviceErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/revoke_share", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			share.Revoke(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "Events-Service/internal/models"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Issuer is an autogenerated mock type for the Issuer type
+type Issuer struct {
+	mock.Mock
+}
+
+// IssueAccess provides a mock function with given fields: userID, now
+func (_m *Issuer) IssueAccess(userID int64, now time.Time) (string, time.Time, error) {
+	ret := _m.Called(userID, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueAccess")
+	}
+
+	var r0 string
+	var r1 time.Time
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int64, time.Time) (string, time.Time, error)); ok {
+		return rf(userID, now)
+	}
+	if rf, ok := ret.Get(0).(func(int64, time.Time) string); ok {
+		r0 = rf(userID, now)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, time.Time) time.Time); ok {
+		r1 = rf(userID, now)
+	} else {
+		r1 = ret.Get(1).(time.Time)
+	}
+
+	if rf, ok := ret.Get(2).(func(int64, time.Time) error); ok {
+		r2 = rf(userID, now)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// IssueRefresh provides a mock function with given fields: now
+func (_m *Issuer) IssueRefresh(now time.Time) (string, models.RefreshToken, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueRefresh")
+	}
+
+	var r0 string
+	var r1 models.RefreshToken
+	var r2 error
+	if rf, ok := ret.Get(0).(func(time.Time) (string, models.RefreshToken, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) string); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) models.RefreshToken); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Get(1).(models.RefreshToken)
+	}
+
+	if rf, ok := ret.Get(2).(func(time.Time) error); ok {
+		r2 = rf(now)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewIssuer creates a new instance of Issuer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIssuer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Issuer {
+	mock := &Issuer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
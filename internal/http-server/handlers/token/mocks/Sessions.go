@@ -0,0 +1,85 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "Events-Service/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Sessions is an autogenerated mock type for the Sessions type
+type Sessions struct {
+	mock.Mock
+}
+
+// CreateRefreshToken provides a mock function with given fields: ctx, keyHash, _a2
+func (_m *Sessions) CreateRefreshToken(ctx context.Context, keyHash string, _a2 models.RefreshToken) (int64, error) {
+	ret := _m.Called(ctx, keyHash, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRefreshToken")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.RefreshToken) (int64, error)); ok {
+		return rf(ctx, keyHash, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.RefreshToken) int64); ok {
+		r0 = rf(ctx, keyHash, _a2)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.RefreshToken) error); ok {
+		r1 = rf(ctx, keyHash, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RotateRefreshToken provides a mock function with given fields: ctx, hash, next
+func (_m *Sessions) RotateRefreshToken(ctx context.Context, hash string, next models.RefreshToken) (int64, error) {
+	ret := _m.Called(ctx, hash, next)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RotateRefreshToken")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.RefreshToken) (int64, error)); ok {
+		return rf(ctx, hash, next)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, models.RefreshToken) int64); ok {
+		r0 = rf(ctx, hash, next)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, models.RefreshToken) error); ok {
+		r1 = rf(ctx, hash, next)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSessions creates a new instance of Sessions. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessions(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Sessions {
+	mock := &Sessions{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,157 @@
+package token
+
+import (
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/apikey"
+	"Events-Service/internal/lib/jwtauth"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	GrantAPIKey       = "api_key"
+	GrantRefreshToken = "refresh_token"
+)
+
+type Request struct {
+	GrantType    string `json:"grant_type" validate:"required,oneof=api_key refresh_token"`
+	ApiKey       string `json:"api_key,omitempty" validate:"required_if=GrantType api_key"`
+	RefreshToken string `json:"refresh_token,omitempty" validate:"required_if=GrantType refresh_token"`
+}
+
+type Response struct {
+	response.Response
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=Sessions
+type Sessions interface {
+	CreateRefreshToken(ctx context.Context, keyHash string, token models.RefreshToken) (int64, error)
+	RotateRefreshToken(ctx context.Context, hash string, next models.RefreshToken) (int64, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=Issuer
+type Issuer interface {
+	IssueAccess(userID int64, now time.Time) (string, time.Time, error)
+	IssueRefresh(now time.Time) (string, models.RefreshToken, error)
+}
+
+// New выдает пару токенов в обмен на ключ API или на токен обновления.
+// Использованный токен обновления становится недействительным.
+func New(log *slog.Logger, sessions Sessions, issuer Issuer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.token.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		// Тело запроса содержит секреты, поэтому в лог попадает только тип запроса.
+		log.Info("request body decoded", slog.String("grant_type", req.GrantType))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		now := time.Now()
+
+		refresh, record, err := issuer.IssueRefresh(now)
+		if err != nil {
+			log.Error("failed to issue refresh token", sl.Err(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to issue token"))
+
+			return
+		}
+
+		var userID int64
+
+		switch req.GrantType {
+		case GrantAPIKey:
+			if !apikey.Valid(req.ApiKey) {
+				unauthorized(w, r, log, "invalid api key")
+
+				return
+			}
+
+			userID, err = sessions.CreateRefreshToken(r.Context(), apikey.Hash(req.ApiKey), record)
+			if errors.Is(err, storage.ErrAPIKeyNotFound) {
+				unauthorized(w, r, log, "invalid api key")
+
+				return
+			}
+		case GrantRefreshToken:
+			if !jwtauth.ValidRefresh(req.RefreshToken) {
+				unauthorized(w, r, log, "invalid refresh token")
+
+				return
+			}
+
+			userID, err = sessions.RotateRefreshToken(r.Context(), apikey.Hash(req.RefreshToken), record)
+			if errors.Is(err, storage.ErrRefreshTokenNotFound) {
+				unauthorized(w, r, log, "invalid refresh token")
+
+				return
+			}
+		}
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to issue token")
+
+			return
+		}
+
+		access, expiresAt, err := issuer.IssueAccess(userID, now)
+		if err != nil {
+			log.Error("failed to issue access token", sl.Err(err))
+			render.Status(r, http.StatusInternalServerError)
+			render.JSON(w, r, response.Error("failed to issue token"))
+
+			return
+		}
+
+		log.Info("tokens issued", slog.Int64("user_id", userID))
+
+		render.JSON(w, r, Response{
+			Response:     response.OK(),
+			AccessToken:  access,
+			TokenType:    "Bearer",
+			ExpiresIn:    int64(expiresAt.Sub(now).Seconds()),
+			RefreshToken: refresh,
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, log *slog.Logger, msg string) {
+	log.Info("token request rejected", slog.String("reason", msg))
+	render.Status(r, http.StatusUnauthorized)
+	render.JSON(w, r, response.Error(msg))
+}
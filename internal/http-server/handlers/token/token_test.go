@@ -0,0 +1,119 @@
+package token_test
+
+import (
+	"Events-Service/internal/http-server/handlers/token"
+	"Events-Service/internal/http-server/handlers/token/mocks"
+	"Events-Service/internal/lib/apikey"
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const refreshToken = "evr_AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+
+func serve(sessions token.Sessions, issuer token.Issuer, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/token", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	token.New(testLogger, sessions, issuer).ServeHTTP(rr, req)
+
+	return rr
+}
+
+func newIssuer(userID int64) *mocks.Issuer {
+	issuer := new(mocks.Issuer)
+	issuer.On("IssueRefresh", mock.Anything).
+		Return("evr_next", models.RefreshToken{Hash: "next-hash"}, nil).Once()
+	issuer.On("IssueAccess", userID, mock.Anything).
+		Return("access", time.Now().Add(15*time.Minute), nil).Once()
+
+	return issuer
+}
+
+func TestNew_APIKeyGrant(t *testing.T) {
+	key, record, err := apikey.Generate()
+	require.NoError(t, err)
+
+	sessions := new(mocks.Sessions)
+	sessions.On("CreateRefreshToken", mock.Anything, record.Hash, models.RefreshToken{Hash: "next-hash"}).
+		Return(int64(3), nil).Once()
+	issuer := newIssuer(3)
+
+	rr := serve(sessions, issuer, `{"grant_type":"api_key","api_key":"`+key+`"}`)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp token.Response
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "access", resp.AccessToken)
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.Equal(t, "evr_next", resp.RefreshToken)
+	assert.InDelta(t, 15*60, resp.ExpiresIn, 1)
+
+	sessions.AssertExpectations(t)
+	issuer.AssertExpectations(t)
+}
+
+func TestNew_RefreshGrant(t *testing.T) {
+	sessions := new(mocks.Sessions)
+	sessions.On("RotateRefreshToken", mock.Anything, apikey.Hash(refreshToken), models.RefreshToken{Hash: "next-hash"}).
+		Return(int64(4), nil).Once()
+	issuer := newIssuer(4)
+
+	rr := serve(sessions, issuer, `{"grant_type":"refresh_token","refresh_token":"`+refreshToken+`"}`)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	sessions.AssertExpectations(t)
+	issuer.AssertExpectations(t)
+}
+
+func TestNew_Rejected(t *testing.T) {
+	key, _, err := apikey.Generate()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		body       string
+		storeErr   error
+		wantStatus int
+	}{
+		{name: "unknown grant", body: `{"grant_type":"password"}`, wantStatus: http.StatusBadRequest},
+		{name: "missing api key", body: `{"grant_type":"api_key"}`, wantStatus: http.StatusBadRequest},
+		{name: "malformed api key", body: `{"grant_type":"api_key","api_key":"12345"}`, wantStatus: http.StatusUnauthorized},
+		{name: "malformed refresh token", body: `{"grant_type":"refresh_token","refresh_token":"12345"}`, wantStatus: http.StatusUnauthorized},
+		{name: "revoked api key", body: `{"grant_type":"api_key","api_key":"` + key + `"}`, storeErr: storage.ErrAPIKeyNotFound, wantStatus: http.StatusUnauthorized},
+		{name: "used refresh token", body: `{"grant_type":"refresh_token","refresh_token":"` + refreshToken + `"}`, storeErr: storage.ErrRefreshTokenNotFound, wantStatus: http.StatusUnauthorized},
+		{name: "storage error", body: `{"grant_type":"api_key","api_key":"` + key + `"}`, storeErr: errors.New("connection reset"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessions := new(mocks.Sessions)
+			if tt.storeErr != nil {
+				sessions.On("CreateRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), tt.storeErr).Maybe()
+				sessions.On("RotateRefreshToken", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), tt.storeErr).Maybe()
+			}
+			issuer := new(mocks.Issuer)
+			issuer.On("IssueRefresh", mock.Anything).Return("evr_next", models.RefreshToken{}, nil).Maybe()
+
+			rr := serve(sessions, issuer, tt.body)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			issuer.AssertNotCalled(t, "IssueAccess", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package user
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"context"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=UserDeleter
+type UserDeleter interface {
+	DeleteUser(ctx context.Context, userID int64) error
+}
+
+// Delete удаляет пользователя окончательно, вместе с его событиями: в корзину они не попадают.
+func Delete(log *slog.Logger, userDeleter UserDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.Delete"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		if err := userDeleter.DeleteUser(r.Context(), userID); err != nil {
+			response.StorageError(w, r, log, err, "failed to delete user")
+
+			return
+		}
+
+		log.Info("user deleted", slog.Int64("id", userID))
+
+		render.JSON(w, r, response.OK())
+	}
+}
@@ -0,0 +1,44 @@
+package user_test
+
+import (
+	"Events-Service/internal/http-server/handlers/user"
+	"Events-Service/internal/http-server/handlers/user/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/storage"
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "deleted", wantStatus: http.StatusOK},
+		{name: "user not found", err: storage.ErrUserNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.UserDeleter)
+			mockService.On("DeleteUser", mock.Anything, int64(7)).Return(tt.err).Once()
+
+			req := httptest.NewRequest(http.MethodPost, "/delete_user", nil)
+			req = req.WithContext(auth.WithUserID(req.Context(), 7))
+
+			rr := httptest.NewRecorder()
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			user.Delete(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
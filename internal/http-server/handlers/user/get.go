@@ -0,0 +1,78 @@
+package user
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/models"
+	"context"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ProfileResponse — профиль пользователя. CreatedAt — время создания в UTC (RFC 3339),
+// пустое у пользователей, созданных до появления профилей.
+type ProfileResponse struct {
+	UserId    int64  `json:"user_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	TimeZone  string `json:"time_zone"`
+	Locale    string `json:"locale"`
+	CreatedAt string `json:"created_at,omitempty"`
+}
+
+// UserResponse возвращается /get_user и /update_user.
+type UserResponse struct {
+	response.Response
+	User ProfileResponse `json:"user"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=UserGetter
+type UserGetter interface {
+	GetUser(ctx context.Context, userID int64) (models.User, error)
+}
+
+func Get(log *slog.Logger, userGetter UserGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.Get"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		user, err := userGetter.GetUser(r.Context(), userID)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to get user")
+
+			return
+		}
+
+		log.Info("got user", slog.Int64("id", user.ID))
+
+		responseUser(w, r, user)
+	}
+}
+
+func responseUser(w http.ResponseWriter, r *http.Request, user models.User) {
+	profile := ProfileResponse{
+		UserId:   user.ID,
+		Name:     user.Name,
+		Email:    user.Email,
+		TimeZone: user.TimeZone,
+		Locale:   user.Locale,
+	}
+	if !user.CreatedAt.IsZero() {
+		profile.CreatedAt = user.CreatedAt.UTC().Format(time.RFC3339)
+	}
+
+	render.JSON(w, r, UserResponse{
+		Response: response.OK(),
+		User:     profile,
+	})
+}
@@ -0,0 +1,79 @@
+package user_test
+
+import (
+	"Events-Service/internal/http-server/handlers/user"
+	"Events-Service/internal/http-server/handlers/user/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGet_Success(t *testing.T) {
+	mockService := new(mocks.UserGetter)
+	mockService.On("GetUser", mock.Anything, int64(7)).Return(models.User{
+		ID:        7,
+		Name:      "Anna",
+		Email:     "anna@example.com",
+		TimeZone:  "Europe/Moscow",
+		Locale:    "ru-RU",
+		CreatedAt: time.Date(2025, 8, 5, 10, 0, 0, 0, time.UTC),
+	}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/get_user", nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), 7))
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	user.Get(testLogger, mockService).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp user.UserResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, user.ProfileResponse{
+		UserId:    7,
+		Name:      "Anna",
+		Email:     "anna@example.com",
+		TimeZone:  "Europe/Moscow",
+		Locale:    "ru-RU",
+		CreatedAt: "2025-08-05T10:00:00Z",
+	}, resp.User)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGet_NotFound(t *testing.T) {
+	mockService := new(mocks.UserGetter)
+	mockService.On("GetUser", mock.Anything, int64(7)).Return(models.User{}, storage.ErrUserNotFound).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/get_user", nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), 7))
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	user.Get(testLogger, mockService).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGet_Unauthenticated(t *testing.T) {
+	mockService := new(mocks.UserGetter)
+
+	req := httptest.NewRequest(http.MethodGet, "/get_user", nil)
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	user.Get(testLogger, mockService).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	mockService.AssertNotCalled(t, "GetUser", mock.Anything, mock.Anything)
+}
@@ -2,16 +2,21 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	models "Events-Service/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // UserCreator is an autogenerated mock type for the UserCreator type
 type UserCreator struct {
 	mock.Mock
 }
 
-// CreateUser provides a mock function with no fields
-func (_m *UserCreator) CreateUser() (int64, error) {
-	ret := _m.Called()
+// CreateUser provides a mock function with given fields: ctx, _a1, key
+func (_m *UserCreator) CreateUser(ctx context.Context, _a1 models.User, key models.APIKey) (int64, error) {
+	ret := _m.Called(ctx, _a1, key)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUser")
@@ -19,17 +24,17 @@ func (_m *UserCreator) CreateUser() (int64, error) {
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (int64, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, models.User, models.APIKey) (int64, error)); ok {
+		return rf(ctx, _a1, key)
 	}
-	if rf, ok := ret.Get(0).(func() int64); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, models.User, models.APIKey) int64); ok {
+		r0 = rf(ctx, _a1, key)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, models.User, models.APIKey) error); ok {
+		r1 = rf(ctx, _a1, key)
 	} else {
 		r1 = ret.Error(1)
 	}
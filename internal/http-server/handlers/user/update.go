@@ -0,0 +1,87 @@
+package user
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+// UpdateRequest меняет только переданные поля профиля.
+type UpdateRequest struct {
+	Name     string `json:"name,omitempty" validate:"max=200"`
+	Email    string `json:"email,omitempty" validate:"omitempty,email,max=254"`
+	TimeZone string `json:"time_zone,omitempty"`
+	Locale   string `json:"locale,omitempty" validate:"omitempty,bcp47_language_tag"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=UserUpdater
+type UserUpdater interface {
+	UpdateUser(ctx context.Context, user models.User) (models.User, error)
+}
+
+func Update(log *slog.Logger, userUpdater UserUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.user.Update"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req UpdateRequest
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		profile, err := newProfile(req.Name, req.Email, req.TimeZone, req.Locale)
+		if err != nil {
+			log.Error("invalid profile", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+
+			return
+		}
+		profile.ID = userID
+
+		user, err := userUpdater.UpdateUser(r.Context(), profile)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to update user")
+
+			return
+		}
+
+		log.Info("user updated", slog.Int64("id", user.ID))
+
+		responseUser(w, r, user)
+	}
+}
@@ -0,0 +1,72 @@
+package user_test
+
+import (
+	"Events-Service/internal/http-server/handlers/user"
+	"Events-Service/internal/http-server/handlers/user/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdate_Success(t *testing.T) {
+	mockService := new(mocks.UserUpdater)
+	mockService.On("UpdateUser", mock.Anything, models.User{ID: 7, Email: "anna@example.com"}).
+		Return(models.User{ID: 7, Name: "Anna", Email: "anna@example.com", TimeZone: "UTC", Locale: "en"}, nil).Once()
+
+	body, _ := json.Marshal(user.UpdateRequest{Email: "ANNA@example.com"})
+	req := httptest.NewRequest(http.MethodPost, "/update_user", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(auth.WithUserID(req.Context(), 7))
+
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	user.Update(testLogger, mockService).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp user.UserResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, "Anna", resp.User.Name)
+	assert.Equal(t, "anna@example.com", resp.User.Email)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdate_StorageErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "user not found", err: storage.ErrUserNotFound, wantStatus: http.StatusNotFound},
+		{name: "email taken", err: storage.ErrEmailTaken, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.UserUpdater)
+			mockService.On("UpdateUser", mock.Anything, mock.Anything).Return(models.User{}, tt.err).Once()
+
+			body, _ := json.Marshal(user.UpdateRequest{Email: "anna@example.com"})
+			req := httptest.NewRequest(http.MethodPost, "/update_user", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req = req.WithContext(auth.WithUserID(req.Context(), 7))
+
+			rr := httptest.NewRecorder()
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			user.Update(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -2,31 +2,47 @@ package user
 
 import (
 	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/apikey"
 	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
 	"errors"
+	"fmt"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
 	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 )
 
-type Request struct{}
+// Request — профиль нового пользователя, все поля необязательны. TimeZone — часовой пояс IANA
+// (по умолчанию UTC), Locale — языковой тег BCP 47 (по умолчанию en).
+type Request struct {
+	Name     string `json:"name,omitempty" validate:"max=200"`
+	Email    string `json:"email,omitempty" validate:"omitempty,email,max=254"`
+	TimeZone string `json:"time_zone,omitempty"`
+	Locale   string `json:"locale,omitempty" validate:"omitempty,bcp47_language_tag"`
+}
 
+// Response возвращает ключ API в открытом виде — он показывается только один раз,
+// в хранилище остаётся лишь его хеш.
 type Response struct {
 	response.Response
-	UserId int64 `json:"user_id"`
+	UserId int64  `json:"user_id"`
+	ApiKey string `json:"api_key"`
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=UserCreator
 type UserCreator interface {
-	CreateUser() (int64, error)
+	CreateUser(ctx context.Context, user models.User, key models.APIKey) (int64, error)
 }
 
 func New(log *slog.Logger, userCreator UserCreator) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.user.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 		)
 
@@ -54,24 +70,64 @@ func New(log *slog.Logger, userCreator UserCreator) http.HandlerFunc {
 			return
 		}
 
-		userId, err := userCreator.CreateUser()
+		profile, err := newProfile(req.Name, req.Email, req.TimeZone, req.Locale)
+		if err != nil {
+			log.Error("invalid profile", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+
+			return
+		}
+		if profile.TimeZone == "" {
+			profile.TimeZone = models.DefaultTimeZone
+		}
+		if profile.Locale == "" {
+			profile.Locale = models.DefaultLocale
+		}
+
+		key, record, err := apikey.Generate()
 		if err != nil {
-			log.Error("failed to create user", sl.Err(err))
+			log.Error("failed to generate api key", sl.Err(err))
 			render.Status(r, http.StatusInternalServerError)
 			render.JSON(w, r, response.Error("failed to create user"))
 
 			return
 		}
 
+		userId, err := userCreator.CreateUser(r.Context(), profile, record)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to create user")
+
+			return
+		}
+
 		log.Info("user created", slog.Int64("id", userId))
 
-		responseOK(w, r, userId)
+		responseOK(w, r, userId, key)
 	}
 }
 
-func responseOK(w http.ResponseWriter, r *http.Request, id int64) {
+// newProfile проверяет часовой пояс профиля и приводит email к нижнему регистру.
+// Пустые поля остаются пустыми.
+func newProfile(name, email, timeZone, locale string) (models.User, error) {
+	if timeZone != "" {
+		if _, err := time.LoadLocation(timeZone); err != nil {
+			return models.User{}, fmt.Errorf("unknown time zone %q", timeZone)
+		}
+	}
+
+	return models.User{
+		Name:     strings.TrimSpace(name),
+		Email:    strings.ToLower(strings.TrimSpace(email)),
+		TimeZone: timeZone,
+		Locale:   locale,
+	}, nil
+}
+
+func responseOK(w http.ResponseWriter, r *http.Request, id int64, key string) {
 	render.JSON(w, r, Response{
 		Response: response.OK(),
 		UserId:   id,
+		ApiKey:   key,
 	})
 }
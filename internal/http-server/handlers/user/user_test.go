@@ -2,6 +2,9 @@ package user_test
 
 import (
 	"Events-Service/internal/http-server/handlers/user"
+	"Events-Service/internal/lib/apikey"
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
 	"bytes"
 	"encoding/json"
 	"errors"
@@ -13,12 +16,19 @@ import (
 	"Events-Service/internal/http-server/handlers/user/mocks"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestNew_Success(t *testing.T) {
 	mockService := new(mocks.UserCreator)
 
-	mockService.On("CreateUser").Return(int64(42), nil).Once()
+	var issued models.APIKey
+	mockService.On("CreateUser", mock.Anything, models.User{
+		TimeZone: models.DefaultTimeZone,
+		Locale:   models.DefaultLocale,
+	}, mock.AnythingOfType("models.APIKey")).Run(func(args mock.Arguments) {
+		issued = args.Get(2).(models.APIKey)
+	}).Return(int64(42), nil).Once()
 
 	reqBody, _ := json.Marshal(user.Request{})
 	req := httptest.NewRequest(http.MethodPost, "/user", bytes.NewReader(reqBody))
@@ -35,6 +45,8 @@ func TestNew_Success(t *testing.T) {
 	err := json.Unmarshal(rr.Body.Bytes(), &resp)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(42), resp.UserId)
+	assert.True(t, apikey.Valid(resp.ApiKey))
+	assert.Equal(t, apikey.Hash(resp.ApiKey), issued.Hash)
 
 	mockService.AssertExpectations(t)
 }
@@ -42,7 +54,7 @@ func TestNew_Success(t *testing.T) {
 func TestNew_InternalServerError(t *testing.T) {
 	mockService := new(mocks.UserCreator)
 
-	mockService.On("CreateUser").Return(int64(0), errors.New("database error")).Once()
+	mockService.On("CreateUser", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), errors.New("database error")).Once()
 
 	reqBody, _ := json.Marshal(user.Request{})
 	req := httptest.NewRequest(http.MethodPost, "/user", bytes.NewReader(reqBody))
@@ -71,5 +83,82 @@ func TestNew_BadRequest(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 
-	mockService.AssertNotCalled(t, "CreateUser")
+	mockService.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNew_Profile(t *testing.T) {
+	mockService := new(mocks.UserCreator)
+
+	mockService.On("CreateUser", mock.Anything, models.User{
+		Name:     "Anna",
+		Email:    "anna@example.com",
+		TimeZone: "Europe/Moscow",
+		Locale:   "ru-RU",
+	}, mock.Anything).Return(int64(7), nil).Once()
+
+	reqBody, _ := json.Marshal(user.Request{
+		Name:     " Anna ",
+		Email:    "Anna@Example.com",
+		TimeZone: "Europe/Moscow",
+		Locale:   "ru-RU",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/user", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := user.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestNew_InvalidProfile(t *testing.T) {
+	tests := []struct {
+		name string
+		req  user.Request
+	}{
+		{name: "email", req: user.Request{Email: "not-an-email"}},
+		{name: "time zone", req: user.Request{TimeZone: "Mars/Olympus"}},
+		{name: "locale", req: user.Request{Locale: "not a locale"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.UserCreator)
+
+			reqBody, _ := json.Marshal(tt.req)
+			req := httptest.NewRequest(http.MethodPost, "/user", bytes.NewReader(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			handler := user.New(testLogger, mockService)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			mockService.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestNew_EmailTaken(t *testing.T) {
+	mockService := new(mocks.UserCreator)
+
+	mockService.On("CreateUser", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), storage.ErrEmailTaken).Once()
+
+	reqBody, _ := json.Marshal(user.Request{Email: "anna@example.com"})
+	req := httptest.NewRequest(http.MethodPost, "/user", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	handler := user.New(testLogger, mockService)
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	mockService.AssertExpectations(t)
 }
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+type DeleteRequest struct {
+	WebhookId int64 `json:"webhook_id" validate:"required"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=WebhookDeleter
+type WebhookDeleter interface {
+	DeleteWebhook(ctx context.Context, userID, webhookID int64) error
+}
+
+// Delete удаляет вебхук вместе с историей его доставок; неотправленные доставки не уходят.
+func Delete(log *slog.Logger, webhookDeleter WebhookDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.webhook.Delete"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req DeleteRequest
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Any("request", req))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		if err = webhookDeleter.DeleteWebhook(r.Context(), userID, req.WebhookId); err != nil {
+			response.StorageError(w, r, log, err, "failed to delete webhook")
+
+			return
+		}
+
+		log.Info("webhook deleted", slog.Int64("id", req.WebhookId))
+
+		render.JSON(w, r, response.OK())
+	}
+}
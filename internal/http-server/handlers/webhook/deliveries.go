@@ -0,0 +1,166 @@
+package webhook
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const defaultDeliveriesLimit = 20
+
+// DeliveriesRequest читается из параметров запроса: /webhook_deliveries?webhook_id=3&limit=50.
+type DeliveriesRequest struct {
+	WebhookId int64 `validate:"required"`
+	Limit     int   `validate:"omitempty,min=1,max=100"`
+}
+
+// AttemptResponse — попытка доставки. StatusCode нет, если вебхук не ответил.
+type AttemptResponse struct {
+	AttemptedAt string `json:"attempted_at"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	Error       string `json:"error,omitempty"`
+	DurationMs  int64  `json:"duration_ms"`
+}
+
+// DeliveryResponse — доставка изменения с телом запроса в Payload. NextAttemptAt — время
+// следующей попытки отложенной доставки.
+type DeliveryResponse struct {
+	DeliveryId    int64             `json:"delivery_id"`
+	EventType     string            `json:"event_type"`
+	Status        string            `json:"status"`
+	Payload       json.RawMessage   `json:"payload"`
+	CreatedAt     string            `json:"created_at"`
+	NextAttemptAt string            `json:"next_attempt_at,omitempty"`
+	Attempts      []AttemptResponse `json:"attempts"`
+}
+
+type DeliveriesResponse struct {
+	response.Response
+	WebhookId  int64              `json:"webhook_id"`
+	Deliveries []DeliveryResponse `json:"deliveries"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=DeliveryLister
+type DeliveryLister interface {
+	ListDeliveries(ctx context.Context, userID, webhookID int64, limit int) ([]models.Delivery, error)
+}
+
+// Deliveries возвращает последние доставки вебхука, от новых к старым, с их попытками.
+func Deliveries(log *slog.Logger, deliveryLister DeliveryLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.webhook.Deliveries"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		req, err := parseDeliveriesRequest(r)
+		if err != nil {
+			log.Error("failed to parse query", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error(err.Error()))
+
+			return
+		}
+
+		log.Info("request parsed", slog.Any("request", req))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		limit := req.Limit
+		if limit == 0 {
+			limit = defaultDeliveriesLimit
+		}
+
+		deliveries, err := deliveryLister.ListDeliveries(r.Context(), userID, req.WebhookId, limit)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to list webhook deliveries")
+
+			return
+		}
+
+		log.Info("got webhook deliveries", slog.Int("count", len(deliveries)))
+
+		responseDeliveries := make([]DeliveryResponse, 0, len(deliveries))
+		for _, d := range deliveries {
+			responseDeliveries = append(responseDeliveries, toDeliveryResponse(d))
+		}
+
+		render.JSON(w, r, DeliveriesResponse{
+			Response:   response.OK(),
+			WebhookId:  req.WebhookId,
+			Deliveries: responseDeliveries,
+		})
+	}
+}
+
+func parseDeliveriesRequest(r *http.Request) (DeliveriesRequest, error) {
+	var req DeliveriesRequest
+	query := r.URL.Query()
+
+	if value := query.Get("webhook_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return DeliveriesRequest{}, fmt.Errorf("invalid webhook_id: %q", value)
+		}
+		req.WebhookId = id
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return DeliveriesRequest{}, fmt.Errorf("invalid limit: %q", value)
+		}
+		req.Limit = limit
+	}
+
+	return req, nil
+}
+
+func toDeliveryResponse(d models.Delivery) DeliveryResponse {
+	resp := DeliveryResponse{
+		DeliveryId: d.ID,
+		EventType:  d.EventType,
+		Status:     string(d.Status),
+		Payload:    json.RawMessage(d.Payload),
+		CreatedAt:  d.CreatedAt.UTC().Format(time.RFC3339),
+		Attempts:   make([]AttemptResponse, 0, len(d.History)),
+	}
+	if d.NextAttemptAt != nil {
+		resp.NextAttemptAt = d.NextAttemptAt.UTC().Format(time.RFC3339)
+	}
+	for _, a := range d.History {
+		resp.Attempts = append(resp.Attempts, AttemptResponse{
+			AttemptedAt: a.AttemptedAt.UTC().Format(time.RFC3339),
+			StatusCode:  a.StatusCode,
+			Error:       a.Error,
+			DurationMs:  a.Duration.Milliseconds(),
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/models"
+	"context"
+	"github.com/go-chi/render"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// WebhookResponse — вебхук без секрета. Пустой EventTypes означает все типы изменений,
+// Failures — неудачные попытки доставки подряд, DisabledAt — когда вебхук был выключен.
+type WebhookResponse struct {
+	WebhookId  int64    `json:"webhook_id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Active     bool     `json:"active"`
+	Failures   int      `json:"failures"`
+	CreatedAt  string   `json:"created_at"`
+	DisabledAt string   `json:"disabled_at,omitempty"`
+}
+
+type ListResponse struct {
+	response.Response
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=WebhookLister
+type WebhookLister interface {
+	ListWebhooks(ctx context.Context, userID int64) ([]models.Webhook, error)
+}
+
+func List(log *slog.Logger, webhookLister WebhookLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.webhook.List"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		webhooks, err := webhookLister.ListWebhooks(r.Context(), userID)
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to list webhooks")
+
+			return
+		}
+
+		log.Info("got webhooks", slog.Int("count", len(webhooks)))
+
+		responseWebhooks := make([]WebhookResponse, 0, len(webhooks))
+		for _, webhook := range webhooks {
+			responseWebhooks = append(responseWebhooks, toResponse(webhook))
+		}
+
+		render.JSON(w, r, ListResponse{
+			Response: response.OK(),
+			Webhooks: responseWebhooks,
+		})
+	}
+}
+
+func toResponse(webhook models.Webhook) WebhookResponse {
+	resp := WebhookResponse{
+		WebhookId:  webhook.ID,
+		URL:        webhook.URL,
+		EventTypes: append([]string{}, webhook.EventTypes...),
+		Active:     webhook.Active,
+		Failures:   webhook.Failures,
+		CreatedAt:  webhook.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if webhook.DisabledAt != nil {
+		resp.DisabledAt = webhook.DisabledAt.UTC().Format(time.RFC3339)
+	}
+
+	return resp
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "Events-Service/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeliveryLister is an autogenerated mock type for the DeliveryLister type
+type DeliveryLister struct {
+	mock.Mock
+}
+
+// ListDeliveries provides a mock function with given fields: ctx, userID, webhookID, limit
+func (_m *DeliveryLister) ListDeliveries(ctx context.Context, userID int64, webhookID int64, limit int) ([]models.Delivery, error) {
+	ret := _m.Called(ctx, userID, webhookID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeliveries")
+	}
+
+	var r0 []models.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int) ([]models.Delivery, error)); ok {
+		return rf(ctx, userID, webhookID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int) []models.Delivery); ok {
+		r0 = rf(ctx, userID, webhookID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int) error); ok {
+		r1 = rf(ctx, userID, webhookID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDeliveryLister creates a new instance of DeliveryLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeliveryLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeliveryLister {
+	mock := &DeliveryLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// URLChecker is an autogenerated mock type for the URLChecker type
+type URLChecker struct {
+	mock.Mock
+}
+
+// CheckURL provides a mock function with given fields: ctx, rawURL
+func (_m *URLChecker) CheckURL(ctx context.Context, rawURL string) error {
+	ret := _m.Called(ctx, rawURL)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckURL")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, rawURL)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewURLChecker creates a new instance of URLChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLChecker {
+	mock := &URLChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	models "Events-Service/internal/models"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebhookUpdater is an autogenerated mock type for the WebhookUpdater type
+type WebhookUpdater struct {
+	mock.Mock
+}
+
+// UpdateWebhook provides a mock function with given fields: ctx, patch
+func (_m *WebhookUpdater) UpdateWebhook(ctx context.Context, patch models.WebhookPatch) (models.Webhook, error) {
+	ret := _m.Called(ctx, patch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWebhook")
+	}
+
+	var r0 models.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.WebhookPatch) (models.Webhook, error)); ok {
+		return rf(ctx, patch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.WebhookPatch) models.Webhook); ok {
+		r0 = rf(ctx, patch)
+	} else {
+		r0 = ret.Get(0).(models.Webhook)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.WebhookPatch) error); ok {
+		r1 = rf(ctx, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWebhookUpdater creates a new instance of WebhookUpdater. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookUpdater(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookUpdater {
+	mock := &WebhookUpdater{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
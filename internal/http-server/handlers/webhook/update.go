@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+)
+
+// UpdateRequest меняет только переданные поля вебхука: пустой массив EventTypes подписывает его
+// на все изменения. Active выключает вебхук или включает выключенный, в том числе после неудачных
+// доставок; отложенные доставки включенного вебхука отправляются заново.
+type UpdateRequest struct {
+	WebhookId  int64    `json:"webhook_id" validate:"required"`
+	URL        string   `json:"url,omitempty" validate:"omitempty,http_url,max=2048"`
+	Secret     string   `json:"secret,omitempty" validate:"omitempty,min=16,max=256"`
+	EventTypes []string `json:"event_types,omitempty" validate:"dive,oneof=event.created event.updated event.deleted event.restored"`
+	Active     *bool    `json:"active,omitempty"`
+}
+
+// UpdateResponse возвращает вебхук после изменения.
+type UpdateResponse struct {
+	response.Response
+	Webhook WebhookResponse `json:"webhook"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=WebhookUpdater
+type WebhookUpdater interface {
+	UpdateWebhook(ctx context.Context, patch models.WebhookPatch) (models.Webhook, error)
+}
+
+func Update(log *slog.Logger, webhookUpdater WebhookUpdater, urlChecker URLChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.webhook.Update"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req UpdateRequest
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		log.Info("request body decoded", slog.Int64("webhook_id", req.WebhookId), slog.String("url", req.URL))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		if req.URL != "" {
+			if err = urlChecker.CheckURL(r.Context(), req.URL); err != nil {
+				log.Error("webhook url is not allowed", sl.Err(err))
+				render.Status(r, http.StatusBadRequest)
+				render.JSON(w, r, response.Error("webhook url is not allowed: "+err.Error()))
+
+				return
+			}
+		}
+
+		webhook, err := webhookUpdater.UpdateWebhook(r.Context(), models.WebhookPatch{
+			ID:         req.WebhookId,
+			UserID:     userID,
+			URL:        req.URL,
+			Secret:     req.Secret,
+			EventTypes: eventTypes(req.EventTypes),
+			Active:     req.Active,
+		})
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to update webhook")
+
+			return
+		}
+
+		log.Info("webhook updated", slog.Int64("id", webhook.ID), slog.Bool("active", webhook.Active))
+
+		render.JSON(w, r, UpdateResponse{
+			Response: response.OK(),
+			Webhook:  toResponse(webhook),
+		})
+	}
+}
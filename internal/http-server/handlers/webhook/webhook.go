@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"log/slog"
+	"net/http"
+	"slices"
+)
+
+// Request — новый вебхук. Secret подписывает доставки (заголовок X-Webhook-Signature) и больше
+// не показывается. EventTypes — типы изменений, на которые подписан вебхук, пустой — все.
+type Request struct {
+	URL        string   `json:"url" validate:"required,http_url,max=2048"`
+	Secret     string   `json:"secret" validate:"required,min=16,max=256"`
+	EventTypes []string `json:"event_types,omitempty" validate:"dive,oneof=event.created event.updated event.deleted event.restored"`
+}
+
+type Response struct {
+	response.Response
+	WebhookId int64 `json:"webhook_id"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=WebhookCreator
+type WebhookCreator interface {
+	CreateWebhook(ctx context.Context, webhook models.Webhook) (int64, error)
+}
+
+// URLChecker отклоняет адреса вебхуков во внутренних сетях сервиса.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=URLChecker
+type URLChecker interface {
+	CheckURL(ctx context.Context, rawURL string) error
+}
+
+func New(log *slog.Logger, webhookCreator WebhookCreator, urlChecker URLChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.webhook.New"
+
+		log := log.With(
+			slog.String("op", op),
+		)
+
+		userID, ok := auth.RequireUser(w, r, log)
+		if !ok {
+			return
+		}
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("failed to decode request"))
+
+			return
+		}
+
+		log.Info("request body decoded", slog.String("url", req.URL), slog.Any("event_types", req.EventTypes))
+
+		if err = validator.New().Struct(req); err != nil {
+			var validateErr validator.ValidationErrors
+			errors.As(err, &validateErr)
+
+			log.Error("invalid request", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.ValidationError(validateErr))
+
+			return
+		}
+
+		if err = urlChecker.CheckURL(r.Context(), req.URL); err != nil {
+			log.Error("webhook url is not allowed", sl.Err(err))
+			render.Status(r, http.StatusBadRequest)
+			render.JSON(w, r, response.Error("webhook url is not allowed: "+err.Error()))
+
+			return
+		}
+
+		webhookId, err := webhookCreator.CreateWebhook(r.Context(), models.Webhook{
+			UserID:     userID,
+			URL:        req.URL,
+			Secret:     req.Secret,
+			EventTypes: eventTypes(req.EventTypes),
+		})
+		if err != nil {
+			response.StorageError(w, r, log, err, "failed to create webhook")
+
+			return
+		}
+
+		log.Info("webhook created", slog.Int64("id", webhookId))
+
+		render.JSON(w, r, Response{
+			Response:  response.OK(),
+			WebhookId: webhookId,
+		})
+	}
+}
+
+// eventTypes убирает повторы из типов изменений и упорядочивает их. nil остается nil.
+func eventTypes(types []string) []string {
+	if types == nil {
+		return nil
+	}
+
+	types = slices.Clone(types)
+	slices.Sort(types)
+
+	return slices.Compact(types)
+}
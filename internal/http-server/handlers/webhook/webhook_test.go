@@ -0,0 +1,312 @@
+package webhook_test
+
+import (
+	"Events-Service/internal/http-server/handlers/webhook"
+	"Events-Service/internal/http-server/handlers/webhook/mocks"
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/lib/netguard"
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantWebhook models.Webhook
+		urlErr      error
+		checksURL   bool
+		serviceErr  error
+		callsStore  bool
+		wantStatus  int
+	}{
+		{
+			name:        "created",
+			body:        `{"url": "https://example.com/hook", "secret": "0123456789abcdef"}`,
+			wantWebhook: models.Webhook{UserID: 1, URL: "https://example.com/hook", Secret: "0123456789abcdef"},
+			checksURL:   true,
+			callsStore:  true,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "event types deduplicated",
+			body:        `{"url": "https://example.com/hook", "secret": "0123456789abcdef", "event_types": ["event.deleted", "event.created", "event.deleted"]}`,
+			wantWebhook: models.Webhook{UserID: 1, URL: "https://example.com/hook", Secret: "0123456789abcdef", EventTypes: []string{"event.created", "event.deleted"}},
+			checksURL:   true,
+			callsStore:  true,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "user not found",
+			body:        `{"url": "https://example.com/hook", "secret": "0123456789abcdef"}`,
+			wantWebhook: models.Webhook{UserID: 1, URL: "https://example.com/hook", Secret: "0123456789abcdef"},
+			checksURL:   true,
+			serviceErr:  storage.ErrUserNotFound,
+			callsStore:  true,
+			wantStatus:  http.StatusNotFound,
+		},
+		{
+			name:       "internal address",
+			body:       `{"url": "http://169.254.169.254/latest/meta-data", "secret": "0123456789abcdef"}`,
+			urlErr:     netguard.ErrForbidden,
+			checksURL:  true,
+			wantStatus: http.StatusBadRequest,
+		},
+		{name: "missing url", body: `{"secret": "0123456789abcdef"}`, wantStatus: http.StatusBadRequest},
+		{name: "not http url", body: `{"url": "ftp://example.com", "secret": "0123456789abcdef"}`, wantStatus: http.StatusBadRequest},
+		{name: "short secret", body: `{"url": "https://example.com/hook", "secret": "short"}`, wantStatus: http.StatusBadRequest},
+		{name: "unknown event type", body: `{"url": "https://example.com/hook", "secret": "0123456789abcdef", "event_types": ["event.moved"]}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.WebhookCreator)
+			if tt.callsStore {
+				mockService.On("CreateWebhook", mock.Anything, tt.wantWebhook).Return(int64(3), tt.serviceErr).Once()
+			}
+			mockChecker := new(mocks.URLChecker)
+			if tt.checksURL {
+				mockChecker.On("CheckURL", mock.Anything, mock.Anything).Return(tt.urlErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/create_webhook", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			webhook.New(testLogger, mockService, mockChecker).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus == http.StatusOK {
+				var resp webhook.Response
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, int64(3), resp.WebhookId)
+			}
+			mockService.AssertExpectations(t)
+			mockChecker.AssertExpectations(t)
+		})
+	}
+}
+
+func TestList_Success(t *testing.T) {
+	created := time.Date(2025, 8, 5, 10, 0, 0, 0, time.UTC)
+	disabled := created.Add(time.Hour)
+
+	mockService := new(mocks.WebhookLister)
+	mockService.On("ListWebhooks", mock.Anything, int64(1)).Return([]models.Webhook{
+		{ID: 1, UserID: 1, URL: "https://example.com/a", Secret: "0123456789abcdef", Active: true, CreatedAt: created},
+		{ID: 2, UserID: 1, URL: "https://example.com/b", Secret: "0123456789abcdef", EventTypes: []string{"event.deleted"},
+			Failures: 20, CreatedAt: created, DisabledAt: &disabled},
+	}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks", nil)
+	req = req.WithContext(auth.WithUserID(req.Context(), 1))
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	webhook.List(testLogger, mockService).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "0123456789abcdef")
+
+	var resp webhook.ListResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, []webhook.WebhookResponse{
+		{WebhookId: 1, URL: "https://example.com/a", EventTypes: []string{}, Active: true, CreatedAt: "2025-08-05T10:00:00Z"},
+		{WebhookId: 2, URL: "https://example.com/b", EventTypes: []string{"event.deleted"}, Failures: 20,
+			CreatedAt: "2025-08-05T10:00:00Z", DisabledAt: "2025-08-05T11:00:00Z"},
+	}, resp.Webhooks)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdate(t *testing.T) {
+	active := true
+
+	tests := []struct {
+		name       string
+		body       string
+		wantPatch  models.WebhookPatch
+		urlErr     error
+		checksURL  bool
+		serviceErr error
+		callsStore bool
+		wantStatus int
+	}{
+		{
+			name:       "reactivated",
+			body:       `{"webhook_id": 2, "active": true}`,
+			wantPatch:  models.WebhookPatch{ID: 2, UserID: 1, Active: &active},
+			callsStore: true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "subscribed to all event types",
+			body:       `{"webhook_id": 2, "event_types": []}`,
+			wantPatch:  models.WebhookPatch{ID: 2, UserID: 1, EventTypes: []string{}},
+			callsStore: true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "webhook not found",
+			body:       `{"webhook_id": 2, "url": "https://example.com/new"}`,
+			wantPatch:  models.WebhookPatch{ID: 2, UserID: 1, URL: "https://example.com/new"},
+			checksURL:  true,
+			serviceErr: storage.ErrWebhookNotFound,
+			callsStore: true,
+			wantStatus: http.StatusNotFound,
+		},
+		{name: "missing webhook id", body: `{"active": true}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid url", body: `{"webhook_id": 2, "url": "example"}`, wantStatus: http.StatusBadRequest},
+		{
+			name:       "internal address",
+			body:       `{"webhook_id": 2, "url": "http://127.0.0.1:8080/hook"}`,
+			urlErr:     netguard.ErrForbidden,
+			checksURL:  true,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.WebhookUpdater)
+			if tt.callsStore {
+				mockService.On("UpdateWebhook", mock.Anything, tt.wantPatch).
+					Return(models.Webhook{ID: 2, UserID: 1, URL: "https://example.com/hook", Active: true}, tt.serviceErr).Once()
+			}
+			mockChecker := new(mocks.URLChecker)
+			if tt.checksURL {
+				mockChecker.On("CheckURL", mock.Anything, mock.Anything).Return(tt.urlErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/update_webhook", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			webhook.Update(testLogger, mockService, mockChecker).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus == http.StatusOK {
+				var resp webhook.UpdateResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.True(t, resp.Webhook.Active)
+			}
+			mockService.AssertExpectations(t)
+			mockChecker.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		serviceErr error
+		callsStore bool
+		wantStatus int
+	}{
+		{name: "deleted", body: `{"webhook_id": 2}`, callsStore: true, wantStatus: http.StatusOK},
+		{name: "webhook not found", body: `{"webhook_id": 2}`, serviceErr: storage.ErrWebhookNotFound, callsStore: true, wantStatus: http.StatusNotFound},
+		{name: "missing webhook id", body: `{}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.WebhookDeleter)
+			if tt.callsStore {
+				mockService.On("DeleteWebhook", mock.Anything, int64(1), int64(2)).Return(tt.serviceErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/delete_webhook", bytes.NewBufferString(tt.body))
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			webhook.Delete(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeliveries(t *testing.T) {
+	created := time.Date(2025, 8, 5, 10, 0, 0, 0, time.UTC)
+	retryAt := created.Add(time.Minute)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantLimit  int
+		serviceErr error
+		callsStore bool
+		wantStatus int
+	}{
+		{name: "default limit", query: "?webhook_id=2", wantLimit: 20, callsStore: true, wantStatus: http.StatusOK},
+		{name: "custom limit", query: "?webhook_id=2&limit=50", wantLimit: 50, callsStore: true, wantStatus: http.StatusOK},
+		{name: "webhook not found", query: "?webhook_id=2", wantLimit: 20, serviceErr: storage.ErrWebhookNotFound, callsStore: true, wantStatus: http.StatusNotFound},
+		{name: "missing webhook id", query: "", wantStatus: http.StatusBadRequest},
+		{name: "invalid webhook id", query: "?webhook_id=abc", wantStatus: http.StatusBadRequest},
+		{name: "limit too large", query: "?webhook_id=2&limit=1000", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.DeliveryLister)
+			if tt.callsStore {
+				mockService.On("ListDeliveries", mock.Anything, int64(1), int64(2), tt.wantLimit).Return([]models.Delivery{{
+					ID:            7,
+					WebhookID:     2,
+					EventType:     models.WebhookEventCreated,
+					Payload:       []byte(`{"type":"event.created","event_id":5}`),
+					Status:        models.DeliveryPending,
+					Attempts:      1,
+					NextAttemptAt: &retryAt,
+					CreatedAt:     created,
+					History: []models.DeliveryAttempt{
+						{DeliveryID: 7, AttemptedAt: created, StatusCode: 503, Error: "unexpected response status 503 Service Unavailable", Duration: 120 * time.Millisecond},
+					},
+				}}, tt.serviceErr).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/webhook_deliveries"+tt.query, nil)
+			req = req.WithContext(auth.WithUserID(req.Context(), 1))
+			rr := httptest.NewRecorder()
+
+			testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+			webhook.Deliveries(testLogger, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus == http.StatusOK {
+				var resp webhook.DeliveriesResponse
+				assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				assert.Equal(t, []webhook.DeliveryResponse{{
+					DeliveryId:    7,
+					EventType:     "event.created",
+					Status:        "pending",
+					Payload:       json.RawMessage(`{"type":"event.created","event_id":5}`),
+					CreatedAt:     "2025-08-05T10:00:00Z",
+					NextAttemptAt: "2025-08-05T10:01:00Z",
+					Attempts: []webhook.AttemptResponse{
+						{AttemptedAt: "2025-08-05T10:00:00Z", StatusCode: 503, Error: "unexpected response status 503 Service Unavailable", DurationMs: 120},
+					},
+				}}, resp.Deliveries)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/lib/apikey"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/storage"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=Authenticator
+type Authenticator interface {
+	UserByAPIKey(ctx context.Context, hash string) (int64, error)
+}
+
+// TokenVerifier проверяет токен доступа (JWT) и возвращает его пользователя.
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=TokenVerifier
+type TokenVerifier interface {
+	Verify(token string) (int64, error)
+}
+
+type ctxKey struct{}
+
+// New проверяет ключ API или токен доступа из заголовка Authorization: Bearer и кладет
+// пользователя в контекст запроса. Запросы без ключа, с неизвестным или отозванным ключом
+// и с непрошедшим проверку токеном получают 401. С нулевым verifier принимаются только ключи.
+func New(log *slog.Logger, authenticator Authenticator, verifier TokenVerifier) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(slog.String("component", "middleware/auth"))
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key, ok := bearerToken(r)
+			if !ok || key == "" {
+				log.Info("missing credentials")
+				unauthorized(w, r, "api key is required")
+
+				return
+			}
+
+			if !apikey.Valid(key) {
+				if verifier == nil {
+					log.Info("malformed api key")
+					unauthorized(w, r, "api key is required")
+
+					return
+				}
+
+				userID, err := verifier.Verify(key)
+				if err != nil {
+					log.Info("invalid access token", sl.Err(err))
+					unauthorized(w, r, "invalid access token")
+
+					return
+				}
+
+				next.ServeHTTP(w, r.WithContext(WithUserID(r.Context(), userID)))
+
+				return
+			}
+
+			userID, err := authenticator.UserByAPIKey(r.Context(), apikey.Hash(key))
+			if errors.Is(err, storage.ErrAPIKeyNotFound) {
+				log.Info("unknown or revoked api key")
+				unauthorized(w, r, "invalid api key")
+
+				return
+			}
+			if err != nil {
+				response.StorageError(w, r, log, err, "failed to check api key")
+
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithUserID(r.Context(), userID)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// WithUserID возвращает контекст запроса от имени пользователя userID.
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return context.WithValue(ctx, ctxKey{}, userID)
+}
+
+// UserID возвращает пользователя, от имени которого выполняется запрос.
+func UserID(ctx context.Context) (int64, bool) {
+	userID, ok := ctx.Value(ctxKey{}).(int64)
+	return userID, ok
+}
+
+// RequireUser возвращает пользователя запроса, а если запрос прошел мимо New, отвечает 401.
+func RequireUser(w http.ResponseWriter, r *http.Request, log *slog.Logger) (int64, bool) {
+	userID, ok := UserID(r.Context())
+	if !ok {
+		log.Error("request is not authenticated")
+		unauthorized(w, r, "api key is required")
+	}
+
+	return userID, ok
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	return strings.TrimSpace(token), true
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, msg string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="events-service"`)
+	render.Status(r, http.StatusUnauthorized)
+	render.JSON(w, r, response.Error(msg))
+}
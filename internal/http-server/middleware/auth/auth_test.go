@@ -0,0 +1,117 @@
+package auth_test
+
+import (
+	"Events-Service/internal/http-server/middleware/auth"
+	"Events-Service/internal/http-server/middleware/auth/mocks"
+	"Events-Service/internal/lib/apikey"
+	"Events-Service/internal/storage"
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(authenticator auth.Authenticator, verifier auth.TokenVerifier, header string) (*httptest.ResponseRecorder, int64, bool) {
+	var userID int64
+	var called bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, called = auth.UserID(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	if header != "" {
+		req.Header.Set("Authorization", header)
+	}
+	rr := httptest.NewRecorder()
+
+	testLogger := slog.New(slog.NewTextHandler(bytes.NewBuffer(nil), nil))
+	auth.New(testLogger, authenticator, verifier)(next).ServeHTTP(rr, req)
+
+	return rr, userID, called
+}
+
+func TestNew_ValidKey(t *testing.T) {
+	key, record, err := apikey.Generate()
+	require.NoError(t, err)
+
+	authenticator := new(mocks.Authenticator)
+	authenticator.On("UserByAPIKey", mock.Anything, record.Hash).Return(int64(42), nil).Once()
+
+	rr, userID, called := serve(authenticator, nil, "Bearer "+key)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, called)
+	assert.Equal(t, int64(42), userID)
+
+	authenticator.AssertExpectations(t)
+}
+
+func TestNew_Rejected(t *testing.T) {
+	key, _, err := apikey.Generate()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		header     string
+		lookupErr  error
+		wantStatus int
+	}{
+		{name: "no header", wantStatus: http.StatusUnauthorized},
+		{name: "basic auth", header: "Basic dXNlcjpwYXNz", wantStatus: http.StatusUnauthorized},
+		{name: "malformed key", header: "Bearer 12345", wantStatus: http.StatusUnauthorized},
+		{name: "unknown key", header: "Bearer " + key, lookupErr: storage.ErrAPIKeyNotFound, wantStatus: http.StatusUnauthorized},
+		{name: "storage error", header: "Bearer " + key, lookupErr: errors.New("connection reset"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := new(mocks.Authenticator)
+			if tt.lookupErr != nil {
+				authenticator.On("UserByAPIKey", mock.Anything, mock.Anything).Return(int64(0), tt.lookupErr).Once()
+			}
+
+			rr, _, called := serve(authenticator, nil, tt.header)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			assert.False(t, called)
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.NotEmpty(t, rr.Header().Get("WWW-Authenticate"))
+			}
+			authenticator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNew_AccessToken(t *testing.T) {
+	verifier := new(mocks.TokenVerifier)
+	verifier.On("Verify", "header.payload.signature").Return(int64(7), nil).Once()
+	authenticator := new(mocks.Authenticator)
+
+	rr, userID, called := serve(authenticator, verifier, "Bearer header.payload.signature")
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, called)
+	assert.Equal(t, int64(7), userID)
+
+	verifier.AssertExpectations(t)
+	authenticator.AssertExpectations(t)
+}
+
+func TestNew_InvalidAccessToken(t *testing.T) {
+	verifier := new(mocks.TokenVerifier)
+	verifier.On("Verify", "expired.token.value").Return(int64(0), errors.New("token is expired")).Once()
+
+	rr, _, called := serve(new(mocks.Authenticator), verifier, "Bearer expired.token.value")
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.False(t, called)
+	assert.NotEmpty(t, rr.Header().Get("WWW-Authenticate"))
+
+	verifier.AssertExpectations(t)
+}
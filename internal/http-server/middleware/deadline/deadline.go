@@ -0,0 +1,26 @@
+package deadline
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// New ограничивает контекст запроса сроком timeout: хранилище прерывает запросы к базе,
+// не уложившиеся в него. Нулевой timeout оставляет контекст без срока.
+func New(timeout time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
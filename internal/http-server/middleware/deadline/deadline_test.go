@@ -0,0 +1,27 @@
+package deadline_test
+
+import (
+	"Events-Service/internal/http-server/middleware/deadline"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	var got time.Time
+	var hasDeadline bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, hasDeadline = r.Context().Deadline()
+	})
+
+	start := time.Now()
+	deadline.New(time.Second)(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.True(t, hasDeadline)
+	assert.WithinDuration(t, start.Add(time.Second), got, 100*time.Millisecond)
+
+	deadline.New(0)(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.False(t, hasDeadline)
+}
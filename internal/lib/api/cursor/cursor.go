@@ -0,0 +1,47 @@
+package cursor
+
+import (
+	"Events-Service/internal/models"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalid = errors.New("invalid cursor")
+
+// Encode упаковывает ключ последнего события страницы в непрозрачную строку для URL.
+func Encode(key models.EventKey) string {
+	raw := key.Date + "|" + strconv.FormatInt(key.ID, 10) + "|" + key.OccurrenceDate
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// Decode разбирает строку из Encode. Любая другая строка дает ErrInvalid.
+func Decode(value string) (models.EventKey, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return models.EventKey{}, ErrInvalid
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return models.EventKey{}, ErrInvalid
+	}
+
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id < 1 {
+		return models.EventKey{}, ErrInvalid
+	}
+	if _, err = time.Parse("2006-01-02", parts[0]); err != nil {
+		return models.EventKey{}, ErrInvalid
+	}
+	if parts[2] != "" {
+		if _, err = time.Parse("2006-01-02", parts[2]); err != nil {
+			return models.EventKey{}, ErrInvalid
+		}
+	}
+
+	return models.EventKey{Date: parts[0], ID: id, OccurrenceDate: parts[2]}, nil
+}
@@ -0,0 +1,43 @@
+package cursor_test
+
+import (
+	"Events-Service/internal/lib/api/cursor"
+	"Events-Service/internal/models"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	for _, key := range []models.EventKey{
+		{Date: "2025-01-01", ID: 7},
+		{Date: "2025-01-02", ID: 12, OccurrenceDate: "2024-12-30"},
+	} {
+		value := cursor.Encode(key)
+		assert.NotContains(t, value, "|")
+
+		decoded, err := cursor.Decode(value)
+		require.NoError(t, err)
+		assert.Equal(t, key, decoded)
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	for _, bad := range []string{
+		"not base64!",
+		encode("2025-01-01|7"),
+		encode("2025-01-01|seven|"),
+		encode("2025-01-01|0|"),
+		encode("01.01.2025|7|"),
+		encode("2025-01-01|7|yesterday"),
+	} {
+		_, err := cursor.Decode(bad)
+		assert.ErrorIs(t, err, cursor.ErrInvalid, bad)
+	}
+}
@@ -0,0 +1,56 @@
+package etag
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Format возвращает сильный ETag для версии события, например "3" в кавычках.
+func Format(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// Parse разбирает значение If-Match с одним ETag из Format. Слабый ETag (W/"3") тоже принимается,
+// "*" и пустое значение означают любую версию и возвращают 0.
+func Parse(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	unquoted := strings.TrimPrefix(value, "W/")
+	if len(unquoted) < 2 || unquoted[0] != '"' || unquoted[len(unquoted)-1] != '"' {
+		return 0, fmt.Errorf("invalid etag %q", value)
+	}
+
+	version, err := strconv.ParseInt(unquoted[1:len(unquoted)-1], 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid etag %q", value)
+	}
+
+	return version, nil
+}
+
+// ExpectedVersion возвращает версию, которую клиент ожидает изменить: из заголовка If-Match
+// или, если его нет, из поля expected_version тела запроса. 0 означает, что проверка не нужна.
+func ExpectedVersion(r *http.Request, bodyVersion int64) (int64, error) {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return bodyVersion, nil
+	}
+
+	version, err := Parse(header)
+	if err != nil {
+		return 0, err
+	}
+	if bodyVersion != 0 && version != 0 && version != bodyVersion {
+		return 0, fmt.Errorf("If-Match %s does not match expected_version %d", header, bodyVersion)
+	}
+	if version == 0 {
+		return bodyVersion, nil
+	}
+
+	return version, nil
+}
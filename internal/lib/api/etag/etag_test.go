@@ -0,0 +1,60 @@
+package etag_test
+
+import (
+	"Events-Service/internal/lib/api/etag"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatParse(t *testing.T) {
+	assert.Equal(t, `"3"`, etag.Format(3))
+
+	for value, want := range map[string]int64{`"3"`: 3, `W/"12"`: 12, ` "7" `: 7, "*": 0, "": 0} {
+		version, err := etag.Parse(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, version, value)
+	}
+
+	for _, bad := range []string{`3`, `"three"`, `"0"`, `"-1"`, `"`, `"1", "2"`} {
+		_, err := etag.Parse(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestExpectedVersion(t *testing.T) {
+	cases := []struct {
+		name     string
+		ifMatch  string
+		body     int64
+		want     int64
+		wantsErr bool
+	}{
+		{name: "nothing", want: 0},
+		{name: "body only", body: 4, want: 4},
+		{name: "header only", ifMatch: `"5"`, want: 5},
+		{name: "header and body agree", ifMatch: `"5"`, body: 5, want: 5},
+		{name: "header and body disagree", ifMatch: `"5"`, body: 4, wantsErr: true},
+		{name: "wildcard header", ifMatch: "*", body: 4, want: 4},
+		{name: "invalid header", ifMatch: "five", wantsErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/update_event", nil)
+			if c.ifMatch != "" {
+				r.Header.Set("If-Match", c.ifMatch)
+			}
+
+			version, err := etag.ExpectedVersion(r, c.body)
+			if c.wantsErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.want, version)
+		})
+	}
+}
@@ -17,6 +17,9 @@ const (
 	StatusError = "Error"
 )
 
+// StatusClientClosedRequest — код ответа на запрос, который клиент отменил до его завершения (как в nginx).
+const StatusClientClosedRequest = 499
+
 func OK() Response {
 	return Response{
 		Status: StatusOK,
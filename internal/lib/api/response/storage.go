@@ -0,0 +1,52 @@
+package response
+
+import (
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+// StorageStatus возвращает код ответа и текст ошибки для ошибки хранилища err по ее виду.
+// Ошибки без вида считаются внутренними: для них возвращается 500 и fallback.
+func StorageStatus(err error, fallback string) (int, string) {
+	switch {
+	case errors.Is(err, storage.ErrCanceled):
+		return StatusClientClosedRequest, "request canceled"
+	case errors.Is(err, storage.ErrTimeout):
+		return http.StatusServiceUnavailable, "request timed out"
+	case errors.Is(err, storage.ErrVersionMismatch):
+		// Версия передается в If-Match или expected_version, поэтому это невыполненное предусловие.
+		return http.StatusPreconditionFailed, "event was modified by another request"
+	case errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, storage.ErrForbidden):
+		return http.StatusForbidden, err.Error()
+	case errors.Is(err, storage.ErrConflict):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, storage.ErrInvalidInput):
+		return http.StatusBadRequest, err.Error()
+	default:
+		return http.StatusInternalServerError, fallback
+	}
+}
+
+// StorageError отвечает на ошибку, которую хранилище вернуло при обработке запроса r.
+// Ошибка, случившаяся после отмены запроса или истечения его срока, считается отменой или таймаутом.
+// Внутренние ошибки логируются с уровнем Error и текстом fallback, остальные — с уровнем Info.
+func StorageError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error, fallback string) {
+	err = storage.ContextError(r.Context(), err)
+	status, msg := StorageStatus(err, fallback)
+
+	if status >= http.StatusInternalServerError {
+		log.Error(fallback, sl.Err(err))
+	} else {
+		log.Info("storage rejected request", slog.Int("status", status), sl.Err(err))
+	}
+
+	render.Status(r, status)
+	render.JSON(w, r, Error(msg))
+}
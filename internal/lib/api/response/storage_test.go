@@ -0,0 +1,41 @@
+package response_test
+
+import (
+	"Events-Service/internal/lib/api/response"
+	"Events-Service/internal/storage"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantMsg    string
+	}{
+		{"user not found", storage.ErrUserNotFound, http.StatusNotFound, "user not found"},
+		{"event not found", storage.ErrEventNotFound, http.StatusNotFound, "event not found"},
+		{"occurrence not found", storage.ErrOccurrenceNotFound, http.StatusNotFound, "occurrence not found"},
+		{"forbidden", storage.ErrEventForbidden, http.StatusForbidden, "event belongs to another user"},
+		{"conflict", storage.ErrEventNotDeleted, http.StatusConflict, "event is not in trash"},
+		{"version mismatch", storage.ErrVersionMismatch, http.StatusPreconditionFailed, "event was modified by another request"},
+		{"invalid input", storage.InvalidInput("invalid date format: %s", "2025-13-01"), http.StatusBadRequest, "invalid date format: 2025-13-01"},
+		{"canceled", storage.ErrCanceled, response.StatusClientClosedRequest, "request canceled"},
+		{"timeout", storage.ErrTimeout, http.StatusServiceUnavailable, "request timed out"},
+		{"wrapped", fmt.Errorf("apply: %w", storage.ErrEventNotFound), http.StatusNotFound, "apply: event not found"},
+		{"internal", errors.New("connection reset"), http.StatusInternalServerError, "failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, msg := response.StorageStatus(tt.err, "failed")
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantMsg, msg)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package apikey
+
+import (
+	"Events-Service/internal/models"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// keyPrefix отличает ключи сервиса от других секретов, например при поиске утечек в логах.
+const keyPrefix = "evs_"
+
+// prefixLen — сколько символов ключа после keyPrefix показывается в списке ключей.
+const prefixLen = 8
+
+// Generate создает новый ключ: возвращает сам ключ для пользователя и его запись для хранилища.
+func Generate() (string, models.APIKey, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", models.APIKey{}, fmt.Errorf("failed to generate api key: %v", err)
+	}
+
+	key := keyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return key, models.APIKey{
+		Hash:   Hash(key),
+		Prefix: key[:len(keyPrefix)+prefixLen],
+	}, nil
+}
+
+// Hash возвращает хеш ключа, под которым он хранится. Ключ случаен и длинен, поэтому
+// медленный хеш для паролей не нужен: SHA-256 позволяет искать ключ по индексу.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Valid проверяет, что строка похожа на ключ из Generate, чтобы не ходить в хранилище за мусором.
+func Valid(key string) bool {
+	secret, ok := strings.CutPrefix(key, keyPrefix)
+	if !ok {
+		return false
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(secret)
+	return err == nil && len(decoded) == 32
+}
@@ -0,0 +1,31 @@
+package apikey_test
+
+import (
+	"Events-Service/internal/lib/apikey"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	key, record, err := apikey.Generate()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key, "evs_"))
+	assert.True(t, apikey.Valid(key))
+	assert.Equal(t, apikey.Hash(key), record.Hash)
+	assert.NotContains(t, record.Hash, key)
+	assert.Equal(t, key[:12], record.Prefix)
+
+	other, _, err := apikey.Generate()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestValid(t *testing.T) {
+	for _, key := range []string{"", "evs_", "evs_short", "key_" + strings.Repeat("A", 43), "evs_" + strings.Repeat("!", 43)} {
+		assert.False(t, apikey.Valid(key), key)
+	}
+}
@@ -0,0 +1,105 @@
+package eventtime
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// localLayouts — форматы времени без смещения, они трактуются в часовом поясе события.
+var localLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+}
+
+type Timing struct {
+	Date     string
+	StartsAt *time.Time
+	EndsAt   *time.Time
+	TimeZone string
+}
+
+// Parse проверяет время события из запроса.
+// Без start и end событие длится весь день date. Со start и end событие занимает
+// промежуток [start, end) в часовом поясе zone (по умолчанию UTC), а date, если передана,
+// должна совпадать с днем начала.
+func Parse(date, start, end, zone string) (Timing, error) {
+	if start == "" && end == "" {
+		if date == "" {
+			return Timing{}, errors.New("date or start_time is required")
+		}
+
+		parsed, err := time.Parse(dateLayout, date)
+		if err != nil {
+			return Timing{}, errors.New("invalid date format, use YYYY-MM-DD")
+		}
+
+		return Timing{Date: parsed.Format(dateLayout)}, nil
+	}
+
+	if start == "" || end == "" {
+		return Timing{}, errors.New("start_time and end_time must be set together")
+	}
+
+	if zone == "" {
+		zone = "UTC"
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return Timing{}, fmt.Errorf("unknown time zone %q", zone)
+	}
+
+	startsAt, err := parseInstant(start, loc)
+	if err != nil {
+		return Timing{}, fmt.Errorf("invalid start_time: %v", err)
+	}
+	endsAt, err := parseInstant(end, loc)
+	if err != nil {
+		return Timing{}, fmt.Errorf("invalid end_time: %v", err)
+	}
+	if !endsAt.After(startsAt) {
+		return Timing{}, errors.New("end_time must be after start_time")
+	}
+
+	startDate := startsAt.In(loc).Format(dateLayout)
+	if date != "" && date != startDate {
+		return Timing{}, fmt.Errorf("date %s does not match start_time day %s", date, startDate)
+	}
+
+	return Timing{
+		Date:     startDate,
+		StartsAt: &startsAt,
+		EndsAt:   &endsAt,
+		TimeZone: zone,
+	}, nil
+}
+
+// Format возвращает момент в часовом поясе события в формате RFC 3339.
+func Format(t *time.Time, zone string) string {
+	if t == nil {
+		return ""
+	}
+
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return t.In(loc).Format(time.RFC3339)
+}
+
+func parseInstant(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%q is not RFC 3339 or YYYY-MM-DDTHH:MM", value)
+}
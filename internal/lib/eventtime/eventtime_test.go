@@ -0,0 +1,87 @@
+package eventtime_test
+
+import (
+	"Events-Service/internal/lib/eventtime"
+	"Events-Service/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_AllDay(t *testing.T) {
+	timing, err := eventtime.Parse("2025-08-05", "", "", "Europe/Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, eventtime.Timing{Date: "2025-08-05"}, timing)
+}
+
+func TestParse_LocalTimeInZone(t *testing.T) {
+	timing, err := eventtime.Parse("", "2025-08-05T14:00", "2025-08-05T15:30", "Europe/Moscow")
+	require.NoError(t, err)
+
+	assert.Equal(t, "2025-08-05", timing.Date)
+	assert.Equal(t, "Europe/Moscow", timing.TimeZone)
+	assert.True(t, timing.StartsAt.Equal(time.Date(2025, 8, 5, 11, 0, 0, 0, time.UTC)))
+	assert.True(t, timing.EndsAt.Equal(time.Date(2025, 8, 5, 12, 30, 0, 0, time.UTC)))
+}
+
+func TestParse_RFC3339DateFromZone(t *testing.T) {
+	// 23:30 UTC — это уже следующий день по Москве.
+	timing, err := eventtime.Parse("", "2025-08-05T23:30:00Z", "2025-08-06T00:30:00Z", "Europe/Moscow")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-08-06", timing.Date)
+}
+
+func TestParse_Errors(t *testing.T) {
+	cases := []struct {
+		name                   string
+		date, start, end, zone string
+	}{
+		{"no date", "", "", "", ""},
+		{"bad date", "05.08.2025", "", "", ""},
+		{"start without end", "", "2025-08-05T14:00", "", ""},
+		{"unknown zone", "", "2025-08-05T14:00", "2025-08-05T15:00", "Mars/Olympus"},
+		{"bad start", "", "14:00", "2025-08-05T15:00", ""},
+		{"end before start", "", "2025-08-05T15:00", "2025-08-05T14:00", ""},
+		{"date mismatch", "2025-08-04", "2025-08-05T14:00", "2025-08-05T15:00", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := eventtime.Parse(tc.date, tc.start, tc.end, tc.zone)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEventDays(t *testing.T) {
+	overnight, err := eventtime.Parse("", "2025-08-05T22:00", "2025-08-06T02:00", "Europe/Moscow")
+	require.NoError(t, err)
+	untilMidnight, err := eventtime.Parse("", "2025-08-05T22:00", "2025-08-06T00:00", "Europe/Moscow")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name        string
+		timing      eventtime.Timing
+		first, last string
+	}{
+		{"all day", eventtime.Timing{Date: "2025-08-05"}, "2025-08-05", "2025-08-05"},
+		{"overnight", overnight, "2025-08-05", "2025-08-06"},
+		{"until midnight", untilMidnight, "2025-08-05", "2025-08-05"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := models.Event{
+				Date:     tc.timing.Date,
+				StartsAt: tc.timing.StartsAt,
+				EndsAt:   tc.timing.EndsAt,
+				TimeZone: tc.timing.TimeZone,
+			}
+			first, last := e.Days()
+			assert.Equal(t, tc.first, first)
+			assert.Equal(t, tc.last, last)
+		})
+	}
+}
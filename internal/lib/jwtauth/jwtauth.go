@@ -0,0 +1,162 @@
+package jwtauth
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/lib/apikey"
+	"Events-Service/internal/models"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken — токен не прошел проверку: подпись, срок, издатель или аудитория.
+var ErrInvalidToken = errors.New("invalid token")
+
+// minSecretLen — минимальная длина секрета HMAC-SHA256 (RFC 7518, 3.2).
+const minSecretLen = 32
+
+// refreshPrefix отличает токены обновления от ключей API и других секретов.
+const refreshPrefix = "evr_"
+
+// Manager выдает и проверяет токены доступа и выдает токены обновления.
+type Manager struct {
+	issuer     string
+	audience   string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	leeway     time.Duration
+	activeKey  string
+	keys       map[string][]byte
+}
+
+func New(cfg config.JWT) (*Manager, error) {
+	if !cfg.Enabled() {
+		return nil, errors.New("no jwt signing keys configured")
+	}
+	if cfg.AccessTTL <= 0 || cfg.RefreshTTL <= 0 {
+		return nil, errors.New("jwt access and refresh ttl must be positive")
+	}
+
+	keys := make(map[string][]byte, len(cfg.SigningKeys))
+	for _, key := range cfg.SigningKeys {
+		if key.ID == "" {
+			return nil, errors.New("jwt signing key without id")
+		}
+		if _, ok := keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate jwt signing key %q", key.ID)
+		}
+		if len(key.Secret) < minSecretLen {
+			return nil, fmt.Errorf("jwt signing key %q is shorter than %d bytes", key.ID, minSecretLen)
+		}
+		keys[key.ID] = []byte(key.Secret)
+	}
+
+	activeKey := cfg.ActiveKey
+	if activeKey == "" {
+		activeKey = cfg.SigningKeys[0].ID
+	}
+	if _, ok := keys[activeKey]; !ok {
+		return nil, fmt.Errorf("active jwt signing key %q is not configured", activeKey)
+	}
+
+	return &Manager{
+		issuer:     cfg.Issuer,
+		audience:   cfg.Audience,
+		accessTTL:  cfg.AccessTTL,
+		refreshTTL: cfg.RefreshTTL,
+		leeway:     cfg.Leeway,
+		activeKey:  activeKey,
+		keys:       keys,
+	}, nil
+}
+
+// IssueAccess подписывает токен доступа пользователя userID активным ключом
+// и возвращает его вместе со сроком действия.
+func (m *Manager) IssueAccess(userID int64, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(m.accessTTL)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    m.issuer,
+		Subject:   strconv.FormatInt(userID, 10),
+		Audience:  jwt.ClaimStrings{m.audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	})
+	token.Header["kid"] = m.activeKey
+
+	signed, err := token.SignedString(m.keys[m.activeKey])
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %v", err)
+	}
+
+	return signed, expiresAt, nil
+}
+
+// Verify проверяет подпись, срок действия, издателя и аудиторию токена доступа
+// и возвращает ID пользователя из sub.
+func (m *Manager) Verify(token string) (int64, error) {
+	var claims jwt.RegisteredClaims
+
+	_, err := jwt.ParseWithClaims(token, &claims, m.key,
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(m.issuer),
+		jwt.WithAudience(m.audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(m.leeway),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, fmt.Errorf("%w: invalid subject %q", ErrInvalidToken, claims.Subject)
+	}
+
+	return userID, nil
+}
+
+// key выбирает ключ проверки по заголовку kid.
+func (m *Manager) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := m.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	return key, nil
+}
+
+// IssueRefresh создает токен обновления: возвращает сам токен для клиента и его запись для хранилища.
+func (m *Manager) IssueRefresh(now time.Time) (string, models.RefreshToken, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", models.RefreshToken{}, fmt.Errorf("failed to generate refresh token: %v", err)
+	}
+
+	token := refreshPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return token, models.RefreshToken{
+		Hash:      apikey.Hash(token),
+		ExpiresAt: now.Add(m.refreshTTL).UTC(),
+	}, nil
+}
+
+// ValidRefresh проверяет, что строка похожа на токен из IssueRefresh.
+func ValidRefresh(token string) bool {
+	secret, ok := strings.CutPrefix(token, refreshPrefix)
+	if !ok {
+		return false
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(secret)
+	return err == nil && len(decoded) == 32
+}
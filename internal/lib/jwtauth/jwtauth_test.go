@@ -0,0 +1,151 @@
+package jwtauth_test
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/lib/apikey"
+	"Events-Service/internal/lib/jwtauth"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	secretA = "0123456789abcdef0123456789abcdef"
+	secretB = "fedcba9876543210fedcba9876543210"
+)
+
+func testConfig() config.JWT {
+	return config.JWT{
+		Issuer:      "events-service",
+		Audience:    "events-web",
+		AccessTTL:   15 * time.Minute,
+		RefreshTTL:  24 * time.Hour,
+		SigningKeys: []config.SigningKey{{ID: "a", Secret: secretA}},
+	}
+}
+
+func TestIssueAndVerify(t *testing.T) {
+	m, err := jwtauth.New(testConfig())
+	require.NoError(t, err)
+
+	token, expiresAt, err := m.IssueAccess(42, time.Now())
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), expiresAt, time.Second)
+
+	userID, err := m.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), userID)
+}
+
+func TestVerify_Rejected(t *testing.T) {
+	m, err := jwtauth.New(testConfig())
+	require.NoError(t, err)
+
+	otherIssuer := testConfig()
+	otherIssuer.Issuer = "someone-else"
+	otherAudience := testConfig()
+	otherAudience.Audience = "mobile"
+	otherSecret := testConfig()
+	otherSecret.SigningKeys = []config.SigningKey{{ID: "a", Secret: secretB}}
+
+	sign := func(cfg config.JWT, now time.Time) string {
+		m, err := jwtauth.New(cfg)
+		require.NoError(t, err)
+		token, _, err := m.IssueAccess(42, now)
+		require.NoError(t, err)
+		return token
+	}
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+		Issuer:    "events-service",
+		Subject:   "42",
+		Audience:  jwt.ClaimStrings{"events-web"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"expired":        sign(testConfig(), time.Now().Add(-time.Hour)),
+		"other issuer":   sign(otherIssuer, time.Now()),
+		"other audience": sign(otherAudience, time.Now()),
+		"bad signature":  sign(otherSecret, time.Now()),
+		"alg none":       unsigned,
+		"garbage":        "not.a.token",
+	}
+	for name, token := range tests {
+		_, err := m.Verify(token)
+		assert.ErrorIs(t, err, jwtauth.ErrInvalidToken, name)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	old, err := jwtauth.New(testConfig())
+	require.NoError(t, err)
+	oldToken, _, err := old.IssueAccess(42, time.Now())
+	require.NoError(t, err)
+
+	// Новый ключ подписывает, старый еще проверяет выданные им токены.
+	rotated := testConfig()
+	rotated.ActiveKey = "b"
+	rotated.SigningKeys = append(rotated.SigningKeys, config.SigningKey{ID: "b", Secret: secretB})
+	m, err := jwtauth.New(rotated)
+	require.NoError(t, err)
+
+	newToken, _, err := m.IssueAccess(42, time.Now())
+	require.NoError(t, err)
+
+	for _, token := range []string{oldToken, newToken} {
+		userID, err := m.Verify(token)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), userID)
+	}
+
+	// После удаления старого ключа его токены не принимаются.
+	rotated.SigningKeys = rotated.SigningKeys[1:]
+	m, err = jwtauth.New(rotated)
+	require.NoError(t, err)
+
+	_, err = m.Verify(oldToken)
+	assert.ErrorIs(t, err, jwtauth.ErrInvalidToken)
+	_, err = m.Verify(newToken)
+	assert.NoError(t, err)
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	noKeys := testConfig()
+	noKeys.SigningKeys = nil
+	shortSecret := testConfig()
+	shortSecret.SigningKeys = []config.SigningKey{{ID: "a", Secret: "short"}}
+	unknownActive := testConfig()
+	unknownActive.ActiveKey = "b"
+	duplicate := testConfig()
+	duplicate.SigningKeys = append(duplicate.SigningKeys, config.SigningKey{ID: "a", Secret: secretB})
+
+	for name, cfg := range map[string]config.JWT{
+		"no keys":        noKeys,
+		"short secret":   shortSecret,
+		"unknown active": unknownActive,
+		"duplicate id":   duplicate,
+	} {
+		_, err := jwtauth.New(cfg)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestIssueRefresh(t *testing.T) {
+	m, err := jwtauth.New(testConfig())
+	require.NoError(t, err)
+
+	now := time.Now()
+	token, record, err := m.IssueRefresh(now)
+	require.NoError(t, err)
+
+	assert.True(t, jwtauth.ValidRefresh(token))
+	assert.False(t, apikey.Valid(token))
+	assert.Equal(t, apikey.Hash(token), record.Hash)
+	assert.WithinDuration(t, now.Add(24*time.Hour), record.ExpiresAt, time.Second)
+	assert.False(t, jwtauth.ValidRefresh("evr_short"))
+}
@@ -0,0 +1,109 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+// ErrForbidden — адрес во внутренней сети, на который нельзя отправлять запросы пользователей.
+var ErrForbidden = errors.New("destination address is not allowed")
+
+// blocked — диапазоны, которые не покрываются методами netip.Addr, но не ведут в интернет:
+// "эта сеть", CGNAT, служебные и тестовые сети IANA, зарезервированные адреса и NAT64,
+// через который можно обратиться к внутреннему IPv4-адресу.
+var blocked = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// Allowed сообщает, что ip — публичный адрес: не loopback, не частная и не link-local сеть,
+// не multicast и не зарезервированный диапазон.
+func Allowed(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range blocked {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Guard проверяет адреса, на которые сервис отправляет запросы по просьбе пользователей (вебхуки),
+// чтобы через них нельзя было обратиться к внутренним сервисам (SSRF). Guard с allowPrivate
+// пропускает любые адреса — для локальной разработки и тестов.
+type Guard struct {
+	allowPrivate bool
+	resolver     *net.Resolver
+}
+
+func New(allowPrivate bool) *Guard {
+	return &Guard{allowPrivate: allowPrivate, resolver: net.DefaultResolver}
+}
+
+// CheckURL проверяет, что все адреса хоста rawURL публичные. Имя, которое не удалось разрешить,
+// тоже отклоняется. Имя может позже начать указывать на другой адрес, поэтому адрес проверяется
+// еще раз при подключении (см. Control).
+func (g *Guard) CheckURL(ctx context.Context, rawURL string) error {
+	if g.allowPrivate {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %v", err)
+	}
+	host := u.Hostname()
+
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !Allowed(ip) {
+			return fmt.Errorf("%w: %s", ErrForbidden, ip)
+		}
+		return nil
+	}
+
+	ips, err := g.resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %v", host, err)
+	}
+	for _, ip := range ips {
+		if !Allowed(ip) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbidden, host, ip)
+		}
+	}
+
+	return nil
+}
+
+// Control проверяет адрес перед подключением, для net.Dialer.Control. Вызывается для уже
+// разрешенного адреса, поэтому смена DNS-записи после CheckURL не обходит проверку.
+func (g *Guard) Control(_, address string, _ syscall.RawConn) error {
+	if g.allowPrivate {
+		return nil
+	}
+
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrForbidden, address)
+	}
+	if !Allowed(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbidden, addrPort.Addr())
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package netguard_test
+
+import (
+	"Events-Service/internal/lib/netguard"
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowed(t *testing.T) {
+	for _, ip := range []string{"93.184.216.34", "8.8.8.8", "2606:2800:220:1:248:1893:25c8:1946"} {
+		assert.True(t, netguard.Allowed(netip.MustParseAddr(ip)), ip)
+	}
+
+	for _, ip := range []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "0.0.0.0", "100.64.0.1",
+		"224.0.0.1", "255.255.255.255", "::1", "::", "fe80::1", "fd00::1", "::ffff:127.0.0.1", "64:ff9b::a9fe:a9fe",
+	} {
+		assert.False(t, netguard.Allowed(netip.MustParseAddr(ip)), ip)
+	}
+}
+
+func TestGuard_CheckURL(t *testing.T) {
+	guard := netguard.New(false)
+	ctx := context.Background()
+
+	assert.NoError(t, guard.CheckURL(ctx, "https://93.184.216.34/hooks"))
+	for _, u := range []string{
+		"http://127.0.0.1:8080/hooks", "http://169.254.169.254/latest/meta-data", "http://[::1]/hooks",
+		"http://10.0.0.5/hooks", "http://localhost/hooks",
+	} {
+		assert.ErrorIs(t, guard.CheckURL(ctx, u), netguard.ErrForbidden, u)
+	}
+	assert.Error(t, guard.CheckURL(ctx, "http://no-such-host.invalid/hooks"))
+
+	assert.NoError(t, netguard.New(true).CheckURL(ctx, "http://127.0.0.1:8080/hooks"))
+}
+
+func TestGuard_Control(t *testing.T) {
+	guard := netguard.New(false)
+
+	assert.NoError(t, guard.Control("tcp4", "93.184.216.34:443", nil))
+	assert.ErrorIs(t, guard.Control("tcp4", "127.0.0.1:80", nil), netguard.ErrForbidden)
+	assert.ErrorIs(t, guard.Control("tcp6", "[fe80::1%eth0]:80", nil), netguard.ErrForbidden)
+
+	assert.NoError(t, netguard.New(true).Control("tcp4", "127.0.0.1:80", nil))
+}
@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"Events-Service/internal/config"
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Do вызывает fn, пока она не вернет nil, не закончатся policy.MaxAttempts попыток или не отменится ctx.
+// Перед каждым повтором вызывается notify (если задан) с номером неудачной попытки, ее ошибкой и задержкой.
+// MaxAttempts меньше 1 означает одну попытку без повторов.
+func Do(ctx context.Context, policy config.Retry, fn func() error, notify func(attempt int, err error, wait time.Duration)) error {
+	attempts := max(policy.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		wait := Backoff(policy, attempt, rand.Float64())
+		if notify != nil {
+			notify(attempt, err, wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("retry canceled after %d attempts: %v", attempt, err)
+		case <-timer.C:
+		}
+	}
+
+	return fmt.Errorf("gave up after %d attempts: %v", attempts, err)
+}
+
+// Backoff возвращает задержку после attempt-й неудачной попытки (attempt считается с 1).
+// r — случайное число из [0, 1), оно сдвигает задержку в пределах ±Jitter.
+func Backoff(policy config.Retry, attempt int, r float64) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	wait := float64(policy.InitialInterval) * math.Pow(multiplier, float64(attempt-1))
+	if policy.MaxInterval > 0 && wait > float64(policy.MaxInterval) {
+		wait = float64(policy.MaxInterval)
+	}
+
+	jitter := min(max(policy.Jitter, 0), 1)
+	wait *= 1 + jitter*(2*r-1)
+
+	return time.Duration(wait)
+}
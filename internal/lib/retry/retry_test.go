@@ -0,0 +1,94 @@
+package retry_test
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/lib/retry"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff_Exponential(t *testing.T) {
+	policy := config.Retry{InitialInterval: 100 * time.Millisecond, MaxInterval: time.Second, Multiplier: 2}
+
+	// r = 0.5 соответствует нулевому сдвигу.
+	assert.Equal(t, 100*time.Millisecond, retry.Backoff(policy, 1, 0.5))
+	assert.Equal(t, 200*time.Millisecond, retry.Backoff(policy, 2, 0.5))
+	assert.Equal(t, 800*time.Millisecond, retry.Backoff(policy, 4, 0.5))
+	assert.Equal(t, time.Second, retry.Backoff(policy, 5, 0.5))
+	assert.Equal(t, time.Second, retry.Backoff(policy, 50, 0.5))
+}
+
+func TestBackoff_Jitter(t *testing.T) {
+	policy := config.Retry{InitialInterval: time.Second, MaxInterval: time.Minute, Multiplier: 2, Jitter: 0.2}
+
+	assert.Equal(t, 800*time.Millisecond, retry.Backoff(policy, 1, 0))
+	assert.Equal(t, time.Second, retry.Backoff(policy, 1, 0.5))
+	assert.Equal(t, 1200*time.Millisecond, retry.Backoff(policy, 1, 1))
+}
+
+func TestDo_SucceedsAfterFailures(t *testing.T) {
+	policy := config.Retry{MaxAttempts: 5, InitialInterval: time.Millisecond, Multiplier: 2}
+
+	calls := 0
+	var notified []int
+	err := retry.Do(context.Background(), policy, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}, func(attempt int, err error, wait time.Duration) {
+		notified = append(notified, attempt)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []int{1, 2}, notified)
+}
+
+func TestDo_GivesUp(t *testing.T) {
+	policy := config.Retry{MaxAttempts: 3, InitialInterval: time.Millisecond}
+
+	calls := 0
+	err := retry.Do(context.Background(), policy, func() error {
+		calls++
+		return errors.New("connection refused")
+	}, nil)
+
+	require.Error(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Contains(t, err.Error(), "gave up after 3 attempts: connection refused")
+}
+
+func TestDo_NoRetries(t *testing.T) {
+	calls := 0
+	err := retry.Do(context.Background(), config.Retry{}, func() error {
+		calls++
+		return errors.New("connection refused")
+	}, nil)
+
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDo_Canceled(t *testing.T) {
+	policy := config.Retry{MaxAttempts: 10, InitialInterval: time.Hour}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := retry.Do(ctx, policy, func() error {
+		calls++
+		return errors.New("connection refused")
+	}, func(int, error, time.Duration) {
+		cancel()
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Contains(t, err.Error(), "retry canceled after 1 attempts")
+}
@@ -0,0 +1,443 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency int
+
+const (
+	Daily Frequency = iota + 1
+	Weekly
+	Monthly
+	Yearly
+)
+
+// maxPeriods ограничивает перебор периодов, чтобы правило без COUNT и UNTIL
+// не приводило к бесконечному циклу.
+const maxPeriods = 100_000
+
+var frequencies = map[string]Frequency{
+	"DAILY":   Daily,
+	"WEEKLY":  Weekly,
+	"MONTHLY": Monthly,
+	"YEARLY":  Yearly,
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// WeekdayNum — элемент BYDAY: день недели и, для MONTHLY и YEARLY, его номер в периоде
+// (1 — первый, -1 — последний, 0 — каждый).
+type WeekdayNum struct {
+	Day time.Weekday
+	N   int
+}
+
+// Rule — подмножество RRULE из RFC 5545: FREQ, INTERVAL, BYDAY, COUNT и UNTIL.
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []WeekdayNum
+	Count    int
+	Until    time.Time
+	// UntilDate означает, что UNTIL задан датой без времени и включает весь этот день.
+	UntilDate bool
+}
+
+// Parse разбирает строку вида "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10".
+// Префикс "RRULE:" допускается.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, errors.New("empty rule")
+	}
+
+	r := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		key = strings.ToUpper(key)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate rule part %s", key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			freq, ok := frequencies[strings.ToUpper(value)]
+			if !ok {
+				return nil, fmt.Errorf("unsupported FREQ %q", value)
+			}
+			r.Freq = freq
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", value)
+			}
+			r.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", value)
+			}
+			r.Count = count
+		case "UNTIL":
+			until, dateOnly, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = until
+			r.UntilDate = dateOnly
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				wd, err := parseWeekdayNum(day)
+				if err != nil {
+					return nil, err
+				}
+				r.ByDay = append(r.ByDay, wd)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rule part %s", key)
+		}
+	}
+
+	if r.Freq == 0 {
+		return nil, errors.New("FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, errors.New("COUNT and UNTIL must not be used together")
+	}
+	for _, wd := range r.ByDay {
+		if wd.N != 0 && r.Freq != Monthly && r.Freq != Yearly {
+			return nil, errors.New("numbered BYDAY is only allowed with MONTHLY or YEARLY")
+		}
+	}
+
+	return r, nil
+}
+
+func (r *Rule) String() string {
+	var freq string
+	for name, f := range frequencies {
+		if f == r.Freq {
+			freq = name
+		}
+	}
+
+	parts := []string{"FREQ=" + freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			days = append(days, wd.String())
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		if r.UntilDate {
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func (wd WeekdayNum) String() string {
+	var name string
+	for n, d := range weekdays {
+		if d == wd.Day {
+			name = n
+		}
+	}
+	if wd.N == 0 {
+		return name
+	}
+
+	return strconv.Itoa(wd.N) + name
+}
+
+// Bounded сообщает, конечна ли серия.
+func (r *Rule) Bounded() bool {
+	return r.Count > 0 || !r.Until.IsZero()
+}
+
+// Between возвращает начала повторений серии с первым вхождением start, попадающие в [from, to).
+// Повторения сохраняют время суток start в его часовом поясе, в том числе при переходе на летнее время.
+func (r *Rule) Between(start, from, to time.Time) []time.Time {
+	var res []time.Time
+	r.iterate(start, from, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			res = append(res, t)
+		}
+		return true
+	})
+
+	return res
+}
+
+// Last возвращает начало последнего повторения конечной серии.
+func (r *Rule) Last(start time.Time) (time.Time, bool) {
+	if !r.Bounded() {
+		return time.Time{}, false
+	}
+
+	var last time.Time
+	found := false
+	completed := r.iterate(start, time.Time{}, func(t time.Time) bool {
+		last = t
+		found = true
+		return true
+	})
+
+	// Серия, которую не удалось перебрать до конца, считается бесконечной.
+	return last, found && completed
+}
+
+// iterate перебирает повторения по порядку, пока fn возвращает true.
+// Если у правила нет COUNT, периоды, целиком лежащие до from, пропускаются без перебора.
+// Возвращает false, если перебор остановился на ограничении maxPeriods.
+func (r *Rule) iterate(start, from time.Time, fn func(time.Time) bool) bool {
+	loc := start.Location()
+	hour, minute, sec := start.Clock()
+	startDate := dateOf(start)
+
+	period := 0
+	if r.Count == 0 && !from.IsZero() && from.After(start) {
+		period = r.periodsBefore(startDate, dateOf(from.In(loc)))
+	}
+
+	emitted := 0
+	for limit := period + maxPeriods; period < limit; period++ {
+		dates := r.periodDates(startDate, period)
+		if dates == nil {
+			continue
+		}
+
+		for _, d := range dates {
+			if d.Before(startDate) {
+				continue
+			}
+
+			t := time.Date(d.Year(), d.Month(), d.Day(), hour, minute, sec, start.Nanosecond(), loc)
+			if r.afterUntil(t, d) {
+				return true
+			}
+			if !fn(t) {
+				return true
+			}
+
+			emitted++
+			if r.Count > 0 && emitted >= r.Count {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (r *Rule) afterUntil(t, date time.Time) bool {
+	if r.Until.IsZero() {
+		return false
+	}
+	if r.UntilDate {
+		return date.After(r.Until)
+	}
+
+	return t.After(r.Until)
+}
+
+// periodsBefore возвращает номер периода, с которого стоит начинать перебор,
+// чтобы не пропустить повторения, начинающиеся в день target или позже.
+func (r *Rule) periodsBefore(startDate, target time.Time) int {
+	var n int
+	switch r.Freq {
+	case Daily:
+		n = int(target.Sub(startDate).Hours()/24) / r.Interval
+	case Weekly:
+		n = int(target.Sub(weekStart(startDate)).Hours()/24) / 7 / r.Interval
+	case Monthly:
+		n = ((target.Year()-startDate.Year())*12 + int(target.Month()-startDate.Month())) / r.Interval
+	case Yearly:
+		n = (target.Year() - startDate.Year()) / r.Interval
+	}
+
+	if n > 0 {
+		n--
+	}
+
+	return n
+}
+
+// periodDates возвращает отсортированные даты-кандидаты n-го периода серии.
+func (r *Rule) periodDates(startDate time.Time, n int) []time.Time {
+	step := n * r.Interval
+
+	switch r.Freq {
+	case Daily:
+		d := startDate.AddDate(0, 0, step)
+		if len(r.ByDay) > 0 && !r.hasWeekday(d.Weekday()) {
+			return nil
+		}
+		return []time.Time{d}
+	case Weekly:
+		ws := weekStart(startDate).AddDate(0, 0, 7*step)
+		if len(r.ByDay) == 0 {
+			return []time.Time{ws.AddDate(0, 0, mondayOffset(startDate.Weekday()))}
+		}
+		var dates []time.Time
+		for i := 0; i < 7; i++ {
+			d := ws.AddDate(0, 0, i)
+			if r.hasWeekday(d.Weekday()) {
+				dates = append(dates, d)
+			}
+		}
+		return dates
+	case Monthly:
+		first := time.Date(startDate.Year(), startDate.Month()+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		if len(r.ByDay) == 0 {
+			d := first.AddDate(0, 0, startDate.Day()-1)
+			if d.Month() != first.Month() {
+				// В месяце нет такого числа (например, 31-го) — повторение пропускается.
+				return nil
+			}
+			return []time.Time{d}
+		}
+		return r.weekdaysIn(first, first.AddDate(0, 1, 0))
+	case Yearly:
+		year := startDate.Year() + step
+		if len(r.ByDay) == 0 {
+			d := time.Date(year, startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.UTC)
+			if d.Month() != startDate.Month() {
+				// 29 февраля в невисокосный год.
+				return nil
+			}
+			return []time.Time{d}
+		}
+		first := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return r.weekdaysIn(first, first.AddDate(1, 0, 0))
+	}
+
+	return nil
+}
+
+// weekdaysIn возвращает дни периода [from, to), подходящие под BYDAY с учетом номеров.
+func (r *Rule) weekdaysIn(from, to time.Time) []time.Time {
+	var dates []time.Time
+	for _, wd := range r.ByDay {
+		var matching []time.Time
+		for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+			if d.Weekday() == wd.Day {
+				matching = append(matching, d)
+			}
+		}
+
+		switch {
+		case wd.N == 0:
+			dates = append(dates, matching...)
+		case wd.N > 0 && wd.N <= len(matching):
+			dates = append(dates, matching[wd.N-1])
+		case wd.N < 0 && -wd.N <= len(matching):
+			dates = append(dates, matching[len(matching)+wd.N])
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+
+	// Один день может подойти под несколько элементов BYDAY (например, 1FR и -1FR).
+	unique := dates[:0]
+	for i, d := range dates {
+		if i == 0 || !d.Equal(dates[i-1]) {
+			unique = append(unique, d)
+		}
+	}
+
+	return unique
+}
+
+func (r *Rule) hasWeekday(day time.Weekday) bool {
+	for _, wd := range r.ByDay {
+		if wd.Day == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseUntil(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t, true, nil
+	}
+
+	return time.Time{}, false, fmt.Errorf("invalid UNTIL %q, use YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+}
+
+func parseWeekdayNum(value string) (WeekdayNum, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+
+	day, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+	}
+
+	var n int
+	if num := value[:len(value)-2]; num != "" {
+		var err error
+		n, err = strconv.Atoi(num)
+		if err != nil || n == 0 || n > 53 || n < -53 {
+			return WeekdayNum{}, fmt.Errorf("invalid BYDAY %q", value)
+		}
+	}
+
+	return WeekdayNum{Day: day, N: n}, nil
+}
+
+// dateOf возвращает календарную дату t в ее часовом поясе как полночь UTC.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// weekStart возвращает понедельник недели d (WKST=MO по умолчанию RFC 5545).
+func weekStart(d time.Time) time.Time {
+	return d.AddDate(0, 0, -mondayOffset(d.Weekday()))
+}
+
+func mondayOffset(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
@@ -0,0 +1,150 @@
+package rrule_test
+
+import (
+	"Events-Service/internal/lib/rrule"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func dates(ts []time.Time) []string {
+	res := make([]string, 0, len(ts))
+	for _, t := range ts {
+		res = append(res, t.Format("2006-01-02"))
+	}
+	return res
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;UNTIL=2025-01-01",
+		"FREQ=DAILY;COUNT=3;UNTIL=20250101",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=DAILY;BYMONTH=1",
+		"FREQ=DAILY;FREQ=WEEKLY",
+	} {
+		_, err := rrule.Parse(rule)
+		assert.Error(t, err, rule)
+	}
+}
+
+func TestParse_String(t *testing.T) {
+	rule, err := rrule.Parse("RRULE:freq=monthly;interval=2;byday=-1fr,2tu;until=20251231")
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR,2TU;UNTIL=20251231", rule.String())
+}
+
+func TestBetween(t *testing.T) {
+	cases := []struct {
+		name     string
+		rule     string
+		start    string
+		from, to string
+		want     []string
+	}{
+		{
+			name: "daily with interval", rule: "FREQ=DAILY;INTERVAL=3", start: "2025-01-01",
+			from: "2025-01-01", to: "2025-01-12",
+			want: []string{"2025-01-01", "2025-01-04", "2025-01-07", "2025-01-10"},
+		},
+		{
+			name: "weekly by day", rule: "FREQ=WEEKLY;BYDAY=MO,TH", start: "2025-08-04",
+			from: "2025-08-01", to: "2025-08-15",
+			want: []string{"2025-08-04", "2025-08-07", "2025-08-11", "2025-08-14"},
+		},
+		{
+			name: "biweekly skips start week days before start", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", start: "2025-08-06",
+			from: "2025-08-01", to: "2025-09-01",
+			want: []string{"2025-08-08", "2025-08-18", "2025-08-22"},
+		},
+		{
+			name: "weekly count", rule: "FREQ=WEEKLY;COUNT=3", start: "2025-08-05",
+			from: "2025-01-01", to: "2026-01-01",
+			want: []string{"2025-08-05", "2025-08-12", "2025-08-19"},
+		},
+		{
+			name: "count counted from start, not window", rule: "FREQ=DAILY;COUNT=5", start: "2025-08-01",
+			from: "2025-08-04", to: "2025-09-01",
+			want: []string{"2025-08-04", "2025-08-05"},
+		},
+		{
+			name: "until date inclusive", rule: "FREQ=DAILY;UNTIL=20250803", start: "2025-08-01",
+			from: "2025-08-01", to: "2025-09-01",
+			want: []string{"2025-08-01", "2025-08-02", "2025-08-03"},
+		},
+		{
+			name: "monthly skips short months", rule: "FREQ=MONTHLY", start: "2025-01-31",
+			from: "2025-01-01", to: "2025-06-01",
+			want: []string{"2025-01-31", "2025-03-31", "2025-05-31"},
+		},
+		{
+			name: "monthly last friday", rule: "FREQ=MONTHLY;BYDAY=-1FR", start: "2025-01-01",
+			from: "2025-01-01", to: "2025-04-01",
+			want: []string{"2025-01-31", "2025-02-28", "2025-03-28"},
+		},
+		{
+			name: "yearly leap day", rule: "FREQ=YEARLY", start: "2024-02-29",
+			from: "2024-01-01", to: "2029-01-01",
+			want: []string{"2024-02-29", "2028-02-29"},
+		},
+		{
+			name: "far window skips ahead", rule: "FREQ=DAILY", start: "1900-01-01",
+			from: "2025-08-01", to: "2025-08-03",
+			want: []string{"2025-08-01", "2025-08-02"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := rrule.Parse(tc.rule)
+			require.NoError(t, err)
+
+			got := rule.Between(date(tc.start), date(tc.from), date(tc.to))
+			assert.Equal(t, tc.want, dates(got))
+		})
+	}
+}
+
+func TestBetween_KeepsLocalTimeAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	rule, err := rrule.Parse("FREQ=WEEKLY;COUNT=2")
+	require.NoError(t, err)
+
+	start := time.Date(2025, 3, 24, 9, 0, 0, 0, loc)
+	got := rule.Between(start, start, start.AddDate(0, 1, 0))
+	require.Len(t, got, 2)
+
+	assert.Equal(t, 9, got[1].Hour())
+	assert.Equal(t, 7*24*time.Hour-time.Hour, got[1].Sub(got[0]))
+}
+
+func TestLast(t *testing.T) {
+	rule, err := rrule.Parse("FREQ=WEEKLY;BYDAY=TU,TH;COUNT=4")
+	require.NoError(t, err)
+	last, ok := rule.Last(date("2025-08-05"))
+	require.True(t, ok)
+	assert.Equal(t, "2025-08-14", last.Format("2006-01-02"))
+
+	rule, err = rrule.Parse("FREQ=DAILY")
+	require.NoError(t, err)
+	_, ok = rule.Last(date("2025-08-05"))
+	assert.False(t, ok)
+}
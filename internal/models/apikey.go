@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// APIKey — ключ доступа пользователя к API. Сам ключ выдается один раз, в хранилище лежит только
+// его хеш (Hash) и начало (Prefix), по которому пользователь узнает ключ в списке.
+type APIKey struct {
+	ID        int64
+	UserID    int64
+	Hash      string
+	Prefix    string
+	CreatedAt time.Time
+	// RevokedAt — момент отзыва ключа, nil у действующих ключей.
+	RevokedAt *time.Time
+}
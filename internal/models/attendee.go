@@ -0,0 +1,60 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// AttendeeStatus — ответ участника на приглашение, как PARTSTAT в RFC 5545.
+type AttendeeStatus string
+
+const (
+	// StatusNeedsAction — участник еще не ответил на приглашение.
+	StatusNeedsAction AttendeeStatus = "needs-action"
+	StatusAccepted    AttendeeStatus = "accepted"
+	StatusDeclined    AttendeeStatus = "declined"
+	StatusTentative   AttendeeStatus = "tentative"
+)
+
+// Attendee — пользователь, приглашенный на событие, и его ответ.
+// RespondedAt — момент последнего ответа, nil, пока участник не ответил.
+type Attendee struct {
+	UserID      int64
+	Status      AttendeeStatus
+	RespondedAt *time.Time
+}
+
+// Invite возвращает участников, еще не ответивших на приглашение, для пользователей userIDs
+// без повторов по возрастанию ID. nil остается nil.
+func Invite(userIDs []int64) []Attendee {
+	if userIDs == nil {
+		return nil
+	}
+
+	sorted := append([]int64(nil), userIDs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	attendees := make([]Attendee, 0, len(sorted))
+	for i, id := range sorted {
+		if i > 0 && id == sorted[i-1] {
+			continue
+		}
+		attendees = append(attendees, Attendee{UserID: id, Status: StatusNeedsAction})
+	}
+
+	return attendees
+}
+
+// AttendeeStatus возвращает ответ пользователя на приглашение на событие
+// или пустой статус, если пользователь не приглашен.
+func (e Event) AttendeeStatus(userID int64) AttendeeStatus {
+	for _, a := range e.Attendees {
+		if a.UserID == userID {
+			return a.Status
+		}
+	}
+
+	return ""
+}
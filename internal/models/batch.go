@@ -0,0 +1,18 @@
+package models
+
+// BatchOperation — одна операция пакетного запроса: создание, изменение или удаление события.
+// Для удаления из Event используются только UserID, ID и Version (ожидаемая версия).
+type BatchOperation struct {
+	Action         Action
+	Event          Event
+	Scope          Scope
+	OccurrenceDate string
+}
+
+// BatchResult — результат операции пакета: ID и версия события, в котором оказались изменения,
+// или ошибка операции.
+type BatchResult struct {
+	EventID int64
+	Version int64
+	Err     error
+}
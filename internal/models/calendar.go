@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Значения календаря по умолчанию. Календарь по умолчанию создается вместе с пользователем.
+const (
+	DefaultCalendarName  = "Default"
+	DefaultCalendarColor = "#4a90e2"
+)
+
+// Calendar — календарь пользователя. Каждое событие принадлежит ровно одному календарю,
+// TimeZone — часовой пояс IANA для событий со временем, созданных без своего часового пояса.
+// Календарь по умолчанию (Default) есть у каждого пользователя, удалить его нельзя.
+type Calendar struct {
+	ID        int64
+	UserID    int64
+	Name      string
+	Color     string
+	TimeZone  string
+	Default   bool
+	CreatedAt time.Time
+}
+
+// Updated возвращает календарь c с непустыми полями patch.
+func (c Calendar) Updated(patch Calendar) Calendar {
+	if patch.Name != "" {
+		c.Name = patch.Name
+	}
+	if patch.Color != "" {
+		c.Color = patch.Color
+	}
+	if patch.TimeZone != "" {
+		c.TimeZone = patch.TimeZone
+	}
+
+	return c
+}
@@ -1,8 +1,110 @@
 package models
 
+import (
+	"fmt"
+	"time"
+)
+
 type Event struct {
 	ID     int64
 	UserID int64
-	Date   string
-	Text   string
+	// CalendarID — календарь события. При создании нулевой CalendarID означает календарь
+	// пользователя по умолчанию, при обновлении — что календарь не меняется.
+	CalendarID int64
+	Date       string
+	Text       string
+
+	// StartsAt и EndsAt заданы только у событий со временем, у событий на весь день они nil.
+	StartsAt *time.Time
+	EndsAt   *time.Time
+	TimeZone string
+
+	// Recurrence — правило повторения RRULE (RFC 5545). Пустое у неповторяющихся событий.
+	Recurrence string
+	// OccurrenceDate — дата, на которую повторение приходится по правилу серии.
+	// Заполняется только у повторений, возвращаемых выборками.
+	OccurrenceDate string
+
+	// Tags — теги события. При обновлении nil оставляет теги без изменений, пустой срез удаляет их.
+	// Повторения и исключения серии наследуют ее теги.
+	Tags []string
+
+	// Attendees — приглашенные на событие пользователи. При обновлении nil оставляет участников
+	// без изменений, пустой срез удаляет их, а оставшиеся участники сохраняют свои ответы.
+	// Повторения и исключения серии наследуют ее участников.
+	Attendees []Attendee
+
+	// Reminders — за сколько до начала события напомнить о нем владельцу. При обновлении nil
+	// оставляет напоминания без изменений, пустой срез удаляет их. Напоминания серии
+	// срабатывают перед каждым ее повторением.
+	Reminders []time.Duration
+
+	// DeletedAt — момент перемещения события в корзину, nil у активных событий.
+	DeletedAt *time.Time
+
+	// Version растет при каждом изменении события, начиная с InitialVersion. Повторения и исключения
+	// серии получают ее версию. При обновлении ненулевая Version — версия, которую ожидает клиент.
+	Version int64
+}
+
+// InitialVersion — версия только что созданного события.
+const InitialVersion int64 = 1
+
+// Exception — изменение или отмена одного повторения серии EventID,
+// которое по правилу приходится на OriginalDate.
+type Exception struct {
+	EventID      int64
+	OriginalDate string
+	Cancelled    bool
+	// Override — повторение после изменения. У отмененных повторений не используется.
+	Override Event
+}
+
+// Scope определяет, какую часть серии затрагивает изменение или удаление.
+type Scope string
+
+const (
+	ScopeThis      Scope = "this"
+	ScopeFollowing Scope = "following"
+	ScopeAll       Scope = "all"
+)
+
+// ParseScope разбирает область изменения серии, пустая строка означает всю серию.
+func ParseScope(s string) (Scope, error) {
+	switch Scope(s) {
+	case "", ScopeAll:
+		return ScopeAll, nil
+	case ScopeThis, ScopeFollowing:
+		return Scope(s), nil
+	default:
+		return "", fmt.Errorf("unknown scope %q, use this, following or all", s)
+	}
+}
+
+func (e Event) AllDay() bool {
+	return e.StartsAt == nil
+}
+
+// Days возвращает первый и последний (включительно) дни, которые занимает событие
+// в своем часовом поясе. Событие на весь день занимает один день Date.
+func (e Event) Days() (string, string) {
+	if e.AllDay() || e.EndsAt == nil {
+		return e.Date, e.Date
+	}
+
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	first := e.StartsAt.In(loc).Format("2006-01-02")
+
+	// Конец не входит в событие: встреча до 00:00 не занимает следующий день.
+	end := *e.EndsAt
+	if end.After(*e.StartsAt) {
+		end = end.Add(-time.Nanosecond)
+	}
+	last := end.In(loc).Format("2006-01-02")
+
+	return first, last
 }
@@ -0,0 +1,36 @@
+package models
+
+// EventKey — ключ события в постраничной выборке. OccurrenceDate различает повторения серии,
+// оказавшиеся в одном дне, у неповторяющихся событий он пустой.
+type EventKey struct {
+	Date           string
+	ID             int64
+	OccurrenceDate string
+}
+
+func (e Event) Key() EventKey {
+	return EventKey{Date: e.Date, ID: e.ID, OccurrenceDate: e.OccurrenceDate}
+}
+
+func (k EventKey) Less(other EventKey) bool {
+	if k.Date != other.Date {
+		return k.Date < other.Date
+	}
+	if k.ID != other.ID {
+		return k.ID < other.ID
+	}
+	return k.OccurrenceDate < other.OccurrenceDate
+}
+
+// ListQuery — выборка событий и повторений, начинающихся в днях [From, To] (YYYY-MM-DD),
+// по возрастанию EventKey. After — ключ последнего события предыдущей страницы, nil для первой.
+// Непустой Calendars оставляет только события из этих календарей. Owner — владелец событий,
+// если это не сам пользователь: тогда видны только открытые пользователю календари владельца.
+type ListQuery struct {
+	From      string
+	To        string
+	Limit     int
+	After     *EventKey
+	Calendars []int64
+	Owner     int64
+}
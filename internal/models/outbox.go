@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Сущности, об изменениях которых сообщают записи outbox.
+const (
+	AggregateEvent = "event"
+	AggregateUser  = "user"
+)
+
+// Типы изменений пользователей. Изменения событий используют типы вебхуков (WebhookEventCreated и др.).
+const (
+	OutboxUserCreated = "user.created"
+	OutboxUserDeleted = "user.deleted"
+)
+
+// OutboxRecord — уведомление об изменении сущности AggregateType с идентификатором AggregateID,
+// записанное в одной транзакции с самим изменением. ID возрастают в порядке записи, поэтому
+// получатель может по ним отбрасывать повторы.
+type OutboxRecord struct {
+	ID            int64
+	AggregateType string
+	AggregateID   int64
+	Type          string
+	Payload       []byte
+	CreatedAt     time.Time
+}
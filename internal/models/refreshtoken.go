@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// RefreshToken — токен обновления, который /token обменивает на новый токен доступа.
+// Как и у ключа API, хранится только хеш. Токен выдается в обмен на ключ APIKeyID
+// и перестает действовать вместе с ним.
+type RefreshToken struct {
+	ID        int64
+	UserID    int64
+	APIKeyID  int64
+	Hash      string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
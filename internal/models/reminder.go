@@ -0,0 +1,58 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Notification — сработавшее напоминание о событии или повторении серии.
+type Notification struct {
+	ReminderID int64
+	EventID    int64
+	// UserID — владелец события, которому адресовано напоминание.
+	UserID int64
+	Text   string
+	// OccurrenceDate — исходная дата повторения, пустая у неповторяющихся событий.
+	OccurrenceDate string
+	StartsAt       time.Time
+	// Before — за сколько до начала события срабатывает напоминание.
+	Before time.Duration
+	// FireAt — момент, на который было назначено напоминание.
+	FireAt time.Time
+}
+
+// StartTime возвращает начало события: StartsAt у событий со временем и полночь дня Date
+// в часовом поясе события (UTC, если он не задан) у событий на весь день.
+func (e Event) StartTime() (time.Time, error) {
+	if !e.AllDay() {
+		return *e.StartsAt, nil
+	}
+
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return time.ParseInLocation("2006-01-02", e.Date, loc)
+}
+
+// RemindBefore возвращает напоминания за minutes минут до начала события без повторов
+// по возрастанию. nil остается nil.
+func RemindBefore(minutes []int) []time.Duration {
+	if minutes == nil {
+		return nil
+	}
+
+	sorted := append([]int(nil), minutes...)
+	sort.Ints(sorted)
+
+	reminders := make([]time.Duration, 0, len(sorted))
+	for i, m := range sorted {
+		if i > 0 && m == sorted[i-1] {
+			continue
+		}
+		reminders = append(reminders, time.Duration(m)*time.Minute)
+	}
+
+	return reminders
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Action — действие над событием, записанное в историю.
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRestore Action = "restore"
+)
+
+// Revision — запись истории события: кто и когда изменил его дату и текст.
+// Пустые Old* у созданий и New* у удалений означают, что значения не было.
+type Revision struct {
+	ID      int64
+	EventID int64
+	// UserID — пользователь, выполнивший действие.
+	UserID int64
+	Action Action
+	// OccurrenceDate — исходная дата повторения, если действие затронуло не всю серию,
+	// а одно повторение или повторения начиная с него.
+	OccurrenceDate string
+	ChangedAt      time.Time
+
+	OldDate string
+	NewDate string
+	OldText string
+	NewText string
+}
+
+// FieldChange — изменение одного поля события.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Changes возвращает поля, значения которых отличаются до и после действия.
+func (r Revision) Changes() []FieldChange {
+	var changes []FieldChange
+	if r.OldDate != r.NewDate {
+		changes = append(changes, FieldChange{Field: "date", Old: r.OldDate, New: r.NewDate})
+	}
+	if r.OldText != r.NewText {
+		changes = append(changes, FieldChange{Field: "text", Old: r.OldText, New: r.NewText})
+	}
+
+	return changes
+}
@@ -0,0 +1,19 @@
+package models
+
+// SearchQuery — полнотекстовый поиск по тексту событий пользователя.
+type SearchQuery struct {
+	Text string
+	// From и To (YYYY-MM-DD, включительно) оставляют события, которые занимают хотя бы один день
+	// из диапазона. Пустая граница не ограничивает поиск.
+	From  string
+	To    string
+	Limit int
+}
+
+// SearchResult — найденное событие. Чем больше Rank, тем точнее совпадение; ранги сравнимы
+// только внутри одного ответа. В Snippet найденные слова выделены тегами <b>.
+type SearchResult struct {
+	Event   Event
+	Rank    float64
+	Snippet string
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Access — доступ пользователя к чужому календарю. Уровни упорядочены: каждый следующий
+// включает предыдущие.
+type Access string
+
+const (
+	// AccessFreeBusy открывает только время событий, без текста и тегов.
+	AccessFreeBusy Access = "freebusy"
+	AccessRead     Access = "read"
+	// AccessWrite разрешает создавать, менять и удалять события календаря.
+	AccessWrite Access = "write"
+)
+
+// Allows сообщает, что доступ a включает доступ required.
+func (a Access) Allows(required Access) bool {
+	return a.level() >= required.level()
+}
+
+func (a Access) level() int {
+	switch a {
+	case AccessFreeBusy:
+		return 1
+	case AccessRead:
+		return 2
+	case AccessWrite:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Share — доступ пользователя UserID к календарю CalendarID владельца OwnerID.
+type Share struct {
+	CalendarID int64
+	OwnerID    int64
+	UserID     int64
+	Access     Access
+	CreatedAt  time.Time
+}
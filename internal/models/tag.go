@@ -0,0 +1,82 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// TagCount — тег пользователя и число событий с ним.
+type TagCount struct {
+	Name  string
+	Count int
+}
+
+// EventFilter отбирает события по тегам: хотя бы один из Tags или, если All, все сразу,
+// и по календарям: только события из Calendars. Пустой фильтр пропускает все события.
+// Owner — владелец событий, если это не сам пользователь, как в ListQuery.
+type EventFilter struct {
+	Tags      []string
+	All       bool
+	Calendars []int64
+	Owner     int64
+}
+
+// MatchCalendar сообщает, что события календаря calendarID проходят фильтр.
+func (f EventFilter) MatchCalendar(calendarID int64) bool {
+	if len(f.Calendars) == 0 {
+		return true
+	}
+
+	for _, id := range f.Calendars {
+		if id == calendarID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchTags сообщает, что событие с тегами tags проходит фильтр по тегам.
+func (f EventFilter) MatchTags(tags []string) bool {
+	if len(f.Tags) == 0 {
+		return true
+	}
+
+	has := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		has[tag] = true
+	}
+
+	for _, tag := range f.Tags {
+		if has[tag] && !f.All {
+			return true
+		}
+		if !has[tag] && f.All {
+			return false
+		}
+	}
+
+	return f.All
+}
+
+// NormalizeTags приводит теги к нижнему регистру, убирает пробелы по краям, пустые теги
+// и повторы и сортирует результат. nil остается nil.
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(tags))
+	res := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		res = append(res, tag)
+	}
+	sort.Strings(res)
+
+	return res
+}
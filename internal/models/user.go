@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Значения профиля по умолчанию.
+const (
+	DefaultTimeZone = "UTC"
+	DefaultLocale   = "en"
+)
+
+// User — профиль пользователя. TimeZone — часовой пояс IANA, Locale — языковой тег BCP 47.
+// CreatedAt нулевой у пользователей, созданных до появления профилей.
+type User struct {
+	ID        int64
+	Name      string
+	Email     string
+	TimeZone  string
+	Locale    string
+	CreatedAt time.Time
+}
+
+// Updated возвращает профиль u с непустыми полями patch.
+func (u User) Updated(patch User) User {
+	if patch.Name != "" {
+		u.Name = patch.Name
+	}
+	if patch.Email != "" {
+		u.Email = patch.Email
+	}
+	if patch.TimeZone != "" {
+		u.TimeZone = patch.TimeZone
+	}
+	if patch.Locale != "" {
+		u.Locale = patch.Locale
+	}
+
+	return u
+}
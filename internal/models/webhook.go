@@ -0,0 +1,128 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// Типы изменений событий, о которых сообщают вебхуки.
+const (
+	WebhookEventCreated  = "event.created"
+	WebhookEventUpdated  = "event.updated"
+	WebhookEventDeleted  = "event.deleted"
+	WebhookEventRestored = "event.restored"
+)
+
+// WebhookEventType возвращает тип изменения для действия из истории события.
+func WebhookEventType(action Action) string {
+	switch action {
+	case ActionCreate:
+		return WebhookEventCreated
+	case ActionUpdate:
+		return WebhookEventUpdated
+	case ActionDelete:
+		return WebhookEventDeleted
+	case ActionRestore:
+		return WebhookEventRestored
+	default:
+		return ""
+	}
+}
+
+// Webhook — адрес, на который сервис отправляет изменения событий пользователя, подписывая их Secret.
+// EventTypes — типы изменений, на которые подписан вебхук, пустой — все. Неактивный вебхук
+// (выключенный пользователем или после DisableAfter неудачных попыток подряд) ничего не получает,
+// Failures — число неудачных попыток подряд, DisabledAt — когда вебхук был выключен.
+type Webhook struct {
+	ID         int64
+	UserID     int64
+	URL        string
+	Secret     string
+	EventTypes []string
+	Active     bool
+	Failures   int
+	CreatedAt  time.Time
+	DisabledAt *time.Time
+}
+
+// Accepts сообщает, получает ли вебхук изменения типа eventType.
+func (w Webhook) Accepts(eventType string) bool {
+	return w.Active && (len(w.EventTypes) == 0 || slices.Contains(w.EventTypes, eventType))
+}
+
+// WebhookPatch — изменение вебхука ID пользователя UserID: непустые URL и Secret, ненулевой EventTypes
+// и Active заменяют значения вебхука. Включение вебхука сбрасывает счетчик неудач.
+type WebhookPatch struct {
+	ID         int64
+	UserID     int64
+	URL        string
+	Secret     string
+	EventTypes []string
+	Active     *bool
+}
+
+// Updated возвращает вебхук w с изменениями patch на момент now.
+func (w Webhook) Updated(patch WebhookPatch, now time.Time) Webhook {
+	if patch.URL != "" {
+		w.URL = patch.URL
+	}
+	if patch.Secret != "" {
+		w.Secret = patch.Secret
+	}
+	if patch.EventTypes != nil {
+		w.EventTypes = patch.EventTypes
+	}
+	if patch.Active != nil && *patch.Active != w.Active {
+		w.Active = *patch.Active
+		w.Failures = 0
+		w.DisabledAt = nil
+		if !w.Active {
+			w.DisabledAt = &now
+		}
+	}
+
+	return w
+}
+
+// DeliveryStatus — состояние доставки изменения на вебхук.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery — отправка одного изменения события на вебхук. Payload отправляется как есть и подписывается.
+// Attempts — число сделанных попыток, NextAttemptAt — когда будет следующая (nil у завершенных доставок).
+type Delivery struct {
+	ID            int64
+	WebhookID     int64
+	EventType     string
+	Payload       []byte
+	Status        DeliveryStatus
+	Attempts      int
+	NextAttemptAt *time.Time
+	CreatedAt     time.Time
+
+	// URL и Secret вебхука заполняются у доставок, забранных на отправку.
+	URL    string
+	Secret string
+
+	// History — попытки доставки, заполняется при просмотре доставок.
+	History []DeliveryAttempt
+}
+
+// DeliveryAttempt — попытка доставки: код ответа (0, если ответа нет), ошибка и длительность запроса.
+type DeliveryAttempt struct {
+	DeliveryID  int64
+	AttemptedAt time.Time
+	StatusCode  int
+	Error       string
+	Duration    time.Duration
+}
+
+// OK сообщает, что вебхук принял изменение.
+func (a DeliveryAttempt) OK() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode < 300
+}
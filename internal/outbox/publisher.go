@@ -0,0 +1,102 @@
+package outbox
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Publisher публикует записи outbox для внешних получателей. Записи передаются по порядку id,
+// и после сбоя запись может прийти повторно, поэтому получатели отбрасывают повторы по id.
+type Publisher interface {
+	Publish(ctx context.Context, record models.OutboxRecord) error
+}
+
+// NewPublisher возвращает публикацию записей, выбранную в cfg.Publisher.
+func NewPublisher(cfg config.Outbox) (Publisher, error) {
+	switch cfg.Publisher {
+	case config.PublisherMemory:
+		return &MemoryPublisher{}, nil
+	case config.PublisherFile:
+		if cfg.File == "" {
+			return nil, fmt.Errorf("outbox file is not set")
+		}
+		return &FilePublisher{path: cfg.File}, nil
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher %q, use %s or %s", cfg.Publisher, config.PublisherMemory, config.PublisherFile)
+	}
+}
+
+// MemoryPublisher хранит опубликованные записи в памяти процесса.
+type MemoryPublisher struct {
+	mu      sync.Mutex
+	records []models.OutboxRecord
+}
+
+func (m *MemoryPublisher) Publish(_ context.Context, record models.OutboxRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.records = append(m.records, record)
+
+	return nil
+}
+
+// Records возвращает копию опубликованных записей в порядке публикации.
+func (m *MemoryPublisher) Records() []models.OutboxRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.records)
+}
+
+// FilePublisher дописывает записи в файл по одному объекту JSON на строку.
+type FilePublisher struct {
+	mu   sync.Mutex
+	path string
+}
+
+// fileRecord — строка файла outbox. Payload записывается как вложенный объект.
+type fileRecord struct {
+	Id            int64           `json:"id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateId   int64           `json:"aggregate_id"`
+	Type          string          `json:"type"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func (f *FilePublisher) Publish(_ context.Context, record models.OutboxRecord) error {
+	line, err := json.Marshal(fileRecord{
+		Id:            record.ID,
+		AggregateType: record.AggregateType,
+		AggregateId:   record.AggregateID,
+		Type:          record.Type,
+		Payload:       record.Payload,
+		CreatedAt:     record.CreatedAt.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode outbox record: %v", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open outbox file: %v", err)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write outbox record: %v", err)
+	}
+
+	return nil
+}
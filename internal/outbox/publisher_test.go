@@ -0,0 +1,79 @@
+package outbox
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/models"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPublisher(t *testing.T) {
+	publisher, err := NewPublisher(config.Outbox{Publisher: config.PublisherMemory})
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryPublisher{}, publisher)
+
+	publisher, err = NewPublisher(config.Outbox{Publisher: config.PublisherFile, File: "outbox.log"})
+	require.NoError(t, err)
+	assert.IsType(t, &FilePublisher{}, publisher)
+
+	_, err = NewPublisher(config.Outbox{Publisher: config.PublisherFile})
+	assert.Error(t, err)
+
+	_, err = NewPublisher(config.Outbox{Publisher: "kafka"})
+	assert.Error(t, err)
+}
+
+func TestMemoryPublisher_RecordsCopy(t *testing.T) {
+	publisher := &MemoryPublisher{}
+	require.NoError(t, publisher.Publish(context.Background(), models.OutboxRecord{ID: 1}))
+	require.NoError(t, publisher.Publish(context.Background(), models.OutboxRecord{ID: 2}))
+
+	records := publisher.Records()
+	require.Len(t, records, 2)
+	records[0].ID = 42
+
+	assert.Equal(t, []models.OutboxRecord{{ID: 1}, {ID: 2}}, publisher.Records())
+}
+
+func TestFilePublisher_AppendsLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.log")
+	publisher, err := NewPublisher(config.Outbox{Publisher: config.PublisherFile, File: path})
+	require.NoError(t, err)
+
+	createdAt := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	for _, id := range []int64{1, 2} {
+		err = publisher.Publish(context.Background(), models.OutboxRecord{
+			ID:            id,
+			AggregateType: models.AggregateEvent,
+			AggregateID:   7,
+			Type:          models.WebhookEventCreated,
+			Payload:       []byte(`{"type":"event.created","event_id":7}`),
+			CreatedAt:     createdAt,
+		})
+		require.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var line fileRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &line))
+	assert.Equal(t, fileRecord{
+		Id:            2,
+		AggregateType: models.AggregateEvent,
+		AggregateId:   7,
+		Type:          models.WebhookEventCreated,
+		Payload:       json.RawMessage(`{"type":"event.created","event_id":7}`),
+		CreatedAt:     createdAt,
+	}, line)
+}
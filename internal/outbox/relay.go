@@ -0,0 +1,61 @@
+package outbox
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"log/slog"
+	"time"
+)
+
+type Relayer interface {
+	RelayOutbox(ctx context.Context, limit int, publish func(models.OutboxRecord) error) (int, error)
+}
+
+// Run раз в cfg.PollInterval передает publisher неотправленные записи outbox, пока не отменен ctx.
+// Первая проверка выполняется сразу, за одну проверку публикуются все накопившиеся записи.
+// Запись, которую publisher не принял, остается в outbox вместе со следующими за ней
+// и публикуется на следующей проверке.
+func Run(ctx context.Context, log *slog.Logger, relayer Relayer, publisher Publisher, cfg config.Outbox) {
+	const op = "outbox.Run"
+
+	log = log.With(
+		slog.String("op", op),
+	)
+
+	if cfg.PollInterval <= 0 || cfg.BatchSize <= 0 {
+		log.Info("outbox relay disabled")
+		return
+	}
+
+	publish := func(record models.OutboxRecord) error {
+		return publisher.Publish(ctx, record)
+	}
+
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			sent, err := relayer.RelayOutbox(ctx, cfg.BatchSize, publish)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				log.Error("failed to relay outbox", sl.Err(err), slog.Int("sent", sent))
+				break
+			}
+
+			if sent < cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package outbox
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type relayerFunc func(limit int, publish func(models.OutboxRecord) error) (int, error)
+
+func (f relayerFunc) RelayOutbox(_ context.Context, limit int, publish func(models.OutboxRecord) error) (int, error) {
+	return f(limit, publish)
+}
+
+type publisherFunc func(record models.OutboxRecord) error
+
+func (f publisherFunc) Publish(_ context.Context, record models.OutboxRecord) error {
+	return f(record)
+}
+
+func TestRun_PublishesInOrderUntilCancelled(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Записи остаются в очереди, пока publisher их не примет.
+	var mu sync.Mutex
+	pending := []models.OutboxRecord{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+	var calls int
+	relayer := relayerFunc(func(limit int, publish func(models.OutboxRecord) error) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		assert.Equal(t, 2, limit)
+		if len(pending) == 0 {
+			cancel()
+			return 0, nil
+		}
+
+		sent := 0
+		for _, record := range pending[:min(limit, len(pending))] {
+			if err := publish(record); err != nil {
+				pending = pending[sent:]
+				return sent, err
+			}
+			sent++
+		}
+		pending = pending[sent:]
+		return sent, nil
+	})
+
+	var published []int64
+	failed := false
+	publisher := publisherFunc(func(record models.OutboxRecord) error {
+		if record.ID == 4 && !failed {
+			failed = true
+			return errors.New("broker is down")
+		}
+		published = append(published, record.ID)
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, log, relayer, publisher, config.Outbox{PollInterval: time.Millisecond, BatchSize: 2})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("relay did not stop after cancel")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// Запись, которую publisher не принял, публикуется снова раньше следующих за ней.
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, published)
+	assert.Equal(t, 4, calls)
+}
+
+func TestRun_Disabled(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	relayer := relayerFunc(func(limit int, publish func(models.OutboxRecord) error) (int, error) {
+		t.Fatal("relay must not run when poll interval is zero")
+		return 0, nil
+	})
+
+	Run(context.Background(), log, relayer, nil, config.Outbox{BatchSize: 10})
+}
@@ -0,0 +1,99 @@
+package reminder
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Notifier доставляет сработавшие напоминания пользователям.
+type Notifier interface {
+	Notify(ctx context.Context, n models.Notification) error
+}
+
+// NewNotifier возвращает доставку напоминаний, выбранную в cfg.Notifier.
+func NewNotifier(log *slog.Logger, cfg config.Reminders) (Notifier, error) {
+	switch cfg.Notifier {
+	case config.NotifierLog:
+		return &LogNotifier{log: log}, nil
+	case config.NotifierFile:
+		if cfg.File == "" {
+			return nil, fmt.Errorf("reminders file is not set")
+		}
+		return &FileNotifier{path: cfg.File}, nil
+	default:
+		return nil, fmt.Errorf("unknown reminders notifier %q, use %s or %s", cfg.Notifier, config.NotifierLog, config.NotifierFile)
+	}
+}
+
+// LogNotifier пишет напоминания в журнал сервиса.
+type LogNotifier struct {
+	log *slog.Logger
+}
+
+func (l *LogNotifier) Notify(_ context.Context, n models.Notification) error {
+	l.log.Info("reminder",
+		slog.Int64("user_id", n.UserID),
+		slog.Int64("event_id", n.EventID),
+		slog.String("occurrence_date", n.OccurrenceDate),
+		slog.String("text", n.Text),
+		slog.Time("starts_at", n.StartsAt),
+	)
+
+	return nil
+}
+
+// FileNotifier дописывает напоминания в файл по одному объекту JSON на строку.
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+// fileNotification — строка файла напоминаний.
+type fileNotification struct {
+	ReminderId     int64     `json:"reminder_id"`
+	EventId        int64     `json:"event_id"`
+	UserId         int64     `json:"user_id"`
+	Text           string    `json:"text"`
+	OccurrenceDate string    `json:"occurrence_date,omitempty"`
+	StartsAt       time.Time `json:"starts_at"`
+	MinutesBefore  int       `json:"minutes_before"`
+	FireAt         time.Time `json:"fire_at"`
+}
+
+func (f *FileNotifier) Notify(_ context.Context, n models.Notification) error {
+	line, err := json.Marshal(fileNotification{
+		ReminderId:     n.ReminderID,
+		EventId:        n.EventID,
+		UserId:         n.UserID,
+		Text:           n.Text,
+		OccurrenceDate: n.OccurrenceDate,
+		StartsAt:       n.StartsAt,
+		MinutesBefore:  int(n.Before / time.Minute),
+		FireAt:         n.FireAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode reminder: %v", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open reminders file: %v", err)
+	}
+	defer file.Close()
+
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write reminder: %v", err)
+	}
+
+	return nil
+}
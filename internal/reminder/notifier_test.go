@@ -0,0 +1,75 @@
+package reminder
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/models"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNotifier(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	notifier, err := NewNotifier(log, config.Reminders{Notifier: config.NotifierLog})
+	require.NoError(t, err)
+	assert.IsType(t, &LogNotifier{}, notifier)
+
+	notifier, err = NewNotifier(log, config.Reminders{Notifier: config.NotifierFile, File: "reminders.log"})
+	require.NoError(t, err)
+	assert.IsType(t, &FileNotifier{}, notifier)
+
+	_, err = NewNotifier(log, config.Reminders{Notifier: config.NotifierFile})
+	assert.Error(t, err)
+
+	_, err = NewNotifier(log, config.Reminders{Notifier: "sms"})
+	assert.Error(t, err)
+}
+
+func TestFileNotifier_AppendsLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.log")
+	notifier, err := NewNotifier(nil, config.Reminders{Notifier: config.NotifierFile, File: path})
+	require.NoError(t, err)
+
+	startsAt := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	for _, id := range []int64{1, 2} {
+		err = notifier.Notify(context.Background(), models.Notification{
+			ReminderID:     id,
+			EventID:        7,
+			UserID:         3,
+			Text:           "Standup",
+			OccurrenceDate: "2025-03-10",
+			StartsAt:       startsAt,
+			Before:         10 * time.Minute,
+			FireAt:         startsAt.Add(-10 * time.Minute),
+		})
+		require.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var line fileNotification
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &line))
+	assert.Equal(t, fileNotification{
+		ReminderId:     2,
+		EventId:        7,
+		UserId:         3,
+		Text:           "Standup",
+		OccurrenceDate: "2025-03-10",
+		StartsAt:       startsAt,
+		MinutesBefore:  10,
+		FireAt:         startsAt.Add(-10 * time.Minute),
+	}, line)
+}
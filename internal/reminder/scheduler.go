@@ -0,0 +1,78 @@
+package reminder
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/models"
+	"context"
+	"log/slog"
+	"time"
+)
+
+type Firer interface {
+	FireReminders(ctx context.Context, now time.Time, limit int) ([]models.Notification, error)
+}
+
+// Run раз в cfg.PollInterval забирает сработавшие напоминания и передает их notifier, пока не отменен ctx.
+// Первая проверка выполняется сразу, за одну проверку забираются все накопившиеся напоминания.
+// Напоминание отмечается сработавшим до доставки, поэтому ошибка notifier его не повторяет.
+func Run(ctx context.Context, log *slog.Logger, firer Firer, notifier Notifier, cfg config.Reminders) {
+	const op = "reminder.Run"
+
+	log = log.With(
+		slog.String("op", op),
+	)
+
+	if cfg.PollInterval <= 0 || cfg.BatchSize <= 0 {
+		log.Info("reminders disabled")
+		return
+	}
+
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			now := time.Now()
+			notifications, err := firer.FireReminders(ctx, now, cfg.BatchSize)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				log.Error("failed to fire reminders", sl.Err(err))
+				break
+			}
+
+			for _, n := range notifications {
+				deliver(ctx, log, notifier, n, now, cfg.ExpireAfter)
+			}
+
+			if len(notifications) < cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver передает напоминание notifier, если оно опоздало не больше чем на expireAfter.
+func deliver(ctx context.Context, log *slog.Logger, notifier Notifier, n models.Notification, now time.Time, expireAfter time.Duration) {
+	log = log.With(
+		slog.Int64("reminder_id", n.ReminderID),
+		slog.Int64("event_id", n.EventID),
+	)
+
+	if late := now.Sub(n.FireAt); expireAfter > 0 && late > expireAfter {
+		log.Warn("reminder expired", slog.Duration("late", late))
+		return
+	}
+
+	if err := notifier.Notify(ctx, n); err != nil {
+		log.Error("failed to deliver reminder", sl.Err(err))
+	}
+}
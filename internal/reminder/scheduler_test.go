@@ -0,0 +1,116 @@
+package reminder
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type firerFunc func(now time.Time, limit int) ([]models.Notification, error)
+
+func (f firerFunc) FireReminders(_ context.Context, now time.Time, limit int) ([]models.Notification, error) {
+	return f(now, limit)
+}
+
+type notifierFunc func(n models.Notification) error
+
+func (f notifierFunc) Notify(_ context.Context, n models.Notification) error {
+	return f(n)
+}
+
+func TestRun_DeliversUntilCancelled(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	var calls int
+	firer := firerFunc(func(now time.Time, limit int) ([]models.Notification, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		assert.Equal(t, 2, limit)
+		switch calls {
+		case 1:
+			// Полная пачка: накопившиеся напоминания забираются без ожидания следующего тика.
+			return []models.Notification{
+				{ReminderID: 1, FireAt: now.Add(-2 * time.Hour)},
+				{ReminderID: 2, FireAt: now.Add(-time.Minute)},
+			}, nil
+		case 2:
+			return []models.Notification{{ReminderID: 3, FireAt: now}}, nil
+		case 3:
+			return nil, errors.New("db is down")
+		default:
+			cancel()
+			return nil, nil
+		}
+	})
+
+	var delivered []int64
+	notifier := notifierFunc(func(n models.Notification) error {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered = append(delivered, n.ReminderID)
+		if n.ReminderID == 3 {
+			return errors.New("smtp is down")
+		}
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, log, firer, notifier, config.Reminders{PollInterval: time.Millisecond, BatchSize: 2, ExpireAfter: time.Hour})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler did not stop after cancel")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 4, calls)
+	// Первое напоминание опоздало больше чем на ExpireAfter и не доставляется.
+	assert.Equal(t, []int64{2, 3}, delivered)
+}
+
+func TestRun_DeliversMissedWithoutExpiry(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	firer := firerFunc(func(now time.Time, limit int) ([]models.Notification, error) {
+		return []models.Notification{{ReminderID: 1, FireAt: now.Add(-48 * time.Hour)}}, nil
+	})
+
+	var delivered []int64
+	notifier := notifierFunc(func(n models.Notification) error {
+		delivered = append(delivered, n.ReminderID)
+		cancel()
+		return nil
+	})
+
+	Run(ctx, log, firer, notifier, config.Reminders{PollInterval: time.Millisecond, BatchSize: 10})
+
+	assert.Equal(t, []int64{1}, delivered)
+}
+
+func TestRun_Disabled(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	firer := firerFunc(func(now time.Time, limit int) ([]models.Notification, error) {
+		t.Fatal("scheduler must not run when poll interval is zero")
+		return nil, nil
+	})
+
+	Run(context.Background(), log, firer, nil, config.Reminders{BatchSize: 10})
+}
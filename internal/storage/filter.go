@@ -0,0 +1,28 @@
+package storage
+
+import "Events-Service/internal/models"
+
+// FilterEvents оставляет события и исключения серий, которые подходят под filter по тегам и календарю.
+// Вызывается до ExpandRecurring: повторения наследуют теги и календарь серии. Теги событий, которые
+// Redact скроет от пользователя с доступом grants, считаются пустыми, чтобы фильтр по тегам их не выдал.
+func FilterEvents(events []models.Event, exceptions []models.Exception, filter models.EventFilter, grants map[int64]models.Access) ([]models.Event, []models.Exception) {
+	if len(filter.Tags) == 0 && len(filter.Calendars) == 0 {
+		return events, exceptions
+	}
+
+	var filteredEvents []models.Event
+	for _, e := range events {
+		if filter.MatchTags(visibleTags(e.Tags, e.CalendarID, grants)) && filter.MatchCalendar(e.CalendarID) {
+			filteredEvents = append(filteredEvents, e)
+		}
+	}
+
+	var filteredExceptions []models.Exception
+	for _, x := range exceptions {
+		if filter.MatchTags(visibleTags(x.Override.Tags, x.Override.CalendarID, grants)) && filter.MatchCalendar(x.Override.CalendarID) {
+			filteredExceptions = append(filteredExceptions, x)
+		}
+	}
+
+	return filteredEvents, filteredExceptions
+}
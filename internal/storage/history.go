@@ -0,0 +1,46 @@
+package storage
+
+import "Events-Service/internal/models"
+
+// NewRevision описывает действие userID над событием eventID. before — событие или повторение
+// до действия, after — после; nil означает, что события еще нет (создание) или уже нет (удаление).
+func NewRevision(action models.Action, userID, eventID int64, occurrenceDate string, before, after *models.Event) models.Revision {
+	rev := models.Revision{
+		EventID:        eventID,
+		UserID:         userID,
+		Action:         action,
+		OccurrenceDate: occurrenceDate,
+	}
+	if before != nil {
+		rev.OldDate = before.Date
+		rev.OldText = before.Text
+	}
+	if after != nil {
+		rev.NewDate = after.Date
+		rev.NewText = after.Text
+	}
+
+	return rev
+}
+
+// Updated возвращает событие before после изменения change, в котором заданы только меняющиеся поля.
+func Updated(before, change models.Event) models.Event {
+	if change.Date != "" {
+		before.Date = change.Date
+	}
+	if change.Text != "" {
+		before.Text = change.Text
+	}
+
+	return before
+}
+
+// Occurrence возвращает повторение серии с исходной датой date с учетом его исключения x (может быть nil).
+func Occurrence(series models.Event, date string, x *models.Exception) models.Event {
+	if x != nil && !x.Cancelled {
+		return x.Override
+	}
+
+	series.Date = date
+	return series
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"Events-Service/internal/models"
+	"sort"
+	"time"
+)
+
+// ListPage собирает страницу выборки query из событий, серий и исключений, найденных хранилищем:
+// раскрывает серии в повторения, начинающиеся в диапазоне, и оставляет не больше query.Limit
+// событий с ключами после query.After.
+func ListPage(events []models.Event, exceptions []models.Exception, query models.ListQuery) ([]models.Event, error) {
+	lower := query.From
+	if query.After != nil && query.After.Date > lower {
+		lower = query.After.Date
+	}
+	from, err := time.Parse("2006-01-02", lower)
+	if err != nil {
+		return nil, InvalidInput("invalid date format: %v", err)
+	}
+	to, err := time.Parse("2006-01-02", query.To)
+	if err != nil {
+		return nil, InvalidInput("invalid date format: %v", err)
+	}
+
+	expanded, err := ExpandRecurring(events, exceptions, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	var res []models.Event
+	for _, e := range expanded {
+		// Окно ExpandRecurring захватывает и события, начавшиеся раньше и еще длящиеся.
+		if e.Date < lower || e.Date > query.To {
+			continue
+		}
+		if query.After != nil && !query.After.Less(e.Key()) {
+			continue
+		}
+		res = append(res, e)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Key().Less(res[j].Key())
+	})
+	if len(res) > query.Limit {
+		res = res[:query.Limit]
+	}
+
+	return res, nil
+}
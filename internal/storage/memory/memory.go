@@ -0,0 +1,2009 @@
+package memory
+
+import (
+	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Storage хранит пользователей и события в памяти процесса.
+// Повторяет поведение postgres.Storage и безопасен для конкурентного использования.
+type Storage struct {
+	mu sync.RWMutex
+	// relayMu не дает двум RelayOutbox публиковать записи одновременно.
+	relayMu sync.Mutex
+
+	users         map[int64]models.User
+	calendars     map[int64]models.Calendar
+	shares        map[shareKey]models.Share
+	apiKeys       map[int64]models.APIKey
+	refreshTokens map[string]models.RefreshToken
+	events        map[int64]record
+	revisions     map[int64][]models.Revision
+	webhooks      map[int64]models.Webhook
+	deliveries    map[int64]models.Delivery
+	// outbox — неотправленные записи outbox по возрастанию id.
+	outbox []models.OutboxRecord
+
+	lastUserID         int64
+	lastCalendarID     int64
+	lastAPIKeyID       int64
+	lastRefreshTokenID int64
+	lastEventID        int64
+	lastRevisionID     int64
+	lastReminderID     int64
+	lastWebhookID      int64
+	lastDeliveryID     int64
+	lastOutboxID       int64
+}
+
+// record — событие вместе с последним днем, который оно может занять
+// (аналог колонки series_end, пустая строка — бесконечная серия),
+// исключениями серии по исходной дате повторения и расписанием напоминаний.
+type record struct {
+	event      models.Event
+	seriesEnd  string
+	exceptions map[string]models.Exception
+	reminders  []reminder
+}
+
+// reminder — напоминание события и момент, когда оно сработает в следующий раз
+// (нулевой, если больше не сработает).
+type reminder struct {
+	id     int64
+	before time.Duration
+	fireAt time.Time
+}
+
+// shareKey — ключ доступа к календарю: календарь и пользователь, которому он открыт.
+type shareKey struct {
+	calendarID int64
+	userID     int64
+}
+
+func New() *Storage {
+	return &Storage{
+		users:         make(map[int64]models.User),
+		calendars:     make(map[int64]models.Calendar),
+		shares:        make(map[shareKey]models.Share),
+		apiKeys:       make(map[int64]models.APIKey),
+		refreshTokens: make(map[string]models.RefreshToken),
+		events:        make(map[int64]record),
+		revisions:     make(map[int64][]models.Revision),
+		webhooks:      make(map[int64]models.Webhook),
+		deliveries:    make(map[int64]models.Delivery),
+	}
+}
+
+func (s *Storage) SaveEvent(ctx context.Context, event models.Event) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.save(event)
+}
+
+// save выполняет SaveEvent. Вызывается под s.mu.
+func (s *Storage) save(event models.Event) (int64, error) {
+	date, err := time.Parse("2006-01-02", event.Date)
+	if err != nil {
+		return 0, storage.InvalidInput("invalid date format: %v", err)
+	}
+	event.Date = date.Format("2006-01-02")
+
+	actor := event.UserID
+	calendar, err := s.writableCalendar(actor, event.CalendarID)
+	if err != nil {
+		return 0, err
+	}
+	event.CalendarID = calendar.ID
+	event.UserID = calendar.UserID
+	if event.Attendees, err = s.mergeAttendees(event.UserID, nil, event.Attendees); err != nil {
+		return 0, err
+	}
+
+	eventID, err := s.insert(event)
+	if err != nil {
+		return 0, err
+	}
+	s.addRevision(storage.NewRevision(models.ActionCreate, actor, eventID, "", nil, &event))
+
+	if err = s.schedule(eventID); err != nil {
+		return 0, err
+	}
+
+	return eventID, nil
+}
+
+// insert сохраняет новое событие пользователя. Вызывается под s.mu.
+func (s *Storage) insert(event models.Event) (int64, error) {
+	seriesEnd, err := storage.SeriesEnd(event)
+	if err != nil {
+		return 0, err
+	}
+
+	s.lastEventID++
+	event.ID = s.lastEventID
+	event.Version = models.InitialVersion
+	s.events[event.ID] = record{event: event, seriesEnd: seriesEnd}
+
+	return event.ID, nil
+}
+
+// UpdateEvent меняет только переданные поля. Если передана дата, время события заменяется целиком:
+// без StartsAt событие становится событием на весь день. Без Recurrence правило повторения не меняется.
+// Для серии scope ограничивает изменение одним повторением или повторениями начиная с occurrenceDate;
+// возвращаются ID и новая версия события, в котором оказались изменения. Ненулевая event.Version
+// должна совпадать с текущей версией события. Изменение записывается в историю события.
+// Менять событие может владелец или пользователь, которому календарь события открыт на запись.
+func (s *Storage) UpdateEvent(ctx context.Context, event models.Event, scope models.Scope, occurrenceDate string) (int64, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.update(event, scope, occurrenceDate)
+}
+
+// update выполняет UpdateEvent. Вызывается под s.mu.
+func (s *Storage) update(event models.Event, scope models.Scope, occurrenceDate string) (int64, int64, error) {
+	if event.Date != "" {
+		parsed, err := time.Parse("2006-01-02", event.Date)
+		if err != nil {
+			return 0, 0, storage.InvalidInput("invalid date format: %v", err)
+		}
+		event.Date = parsed.Format("2006-01-02")
+	}
+
+	rec, err := s.find(event.UserID, event.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err = storage.CheckVersion(rec.event, event.Version); err != nil {
+		return 0, 0, err
+	}
+	// Событие открытого на запись календаря меняет не владелец: в истории остается он сам.
+	actor := event.UserID
+	event.UserID = rec.event.UserID
+	if event.Attendees != nil {
+		if event.Attendees, err = s.mergeAttendees(event.UserID, rec.event.Attendees, event.Attendees); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	before, occurrence, err := changedPart(rec, scope, occurrenceDate)
+	if err != nil {
+		return 0, 0, err
+	}
+	if event.CalendarID != 0 {
+		if scope == models.ScopeThis && occurrence != "" {
+			return 0, 0, storage.ErrOccurrenceCalendar
+		}
+		calendar, err := s.writableCalendar(actor, event.CalendarID)
+		if err == nil && calendar.UserID != rec.event.UserID {
+			err = storage.ErrCalendarNotFound
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	after := storage.Updated(before, event)
+	revision := storage.NewRevision(models.ActionUpdate, actor, event.ID, occurrence, &before, &after)
+
+	if occurrence != "" {
+		switch scope {
+		case models.ScopeThis:
+			if err = s.setException(rec, occurrenceDate, false, event); err != nil {
+				return 0, 0, err
+			}
+			if err = s.schedule(event.ID); err != nil {
+				return 0, 0, err
+			}
+			s.addRevision(revision)
+			return event.ID, s.bumpVersion(event.ID), nil
+		case models.ScopeFollowing:
+			head, tail, err := storage.SplitSeries(rec.event, occurrenceDate)
+			if err != nil {
+				return 0, 0, err
+			}
+			if event.Recurrence == "" {
+				event.Recurrence = tail
+			}
+			if event.Tags == nil {
+				event.Tags = rec.event.Tags
+			}
+			if event.Attendees == nil {
+				event.Attendees = rec.event.Attendees
+			}
+			if event.Reminders == nil {
+				event.Reminders = rec.event.Reminders
+			}
+			if event.CalendarID == 0 {
+				event.CalendarID = rec.event.CalendarID
+			}
+			if head != "" {
+				if err = s.truncate(rec, head, occurrenceDate); err != nil {
+					return 0, 0, err
+				}
+				eventID, err := s.insert(event)
+				if err != nil {
+					return 0, 0, err
+				}
+				if err = s.schedule(event.ID); err != nil {
+					return 0, 0, err
+				}
+				if err = s.schedule(eventID); err != nil {
+					return 0, 0, err
+				}
+				s.addRevision(revision)
+				s.addRevision(storage.NewRevision(models.ActionCreate, actor, eventID, "", nil, &event))
+				s.bumpVersion(event.ID)
+				return eventID, models.InitialVersion, nil
+			}
+		}
+	}
+
+	if event.Date != "" {
+		if event.Recurrence == "" {
+			event.Recurrence = rec.event.Recurrence
+		}
+		seriesEnd, err := storage.SeriesEnd(event)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		rec.event.Date = event.Date
+		rec.event.StartsAt = event.StartsAt
+		rec.event.EndsAt = event.EndsAt
+		rec.event.TimeZone = event.TimeZone
+		rec.event.Recurrence = event.Recurrence
+		rec.seriesEnd = seriesEnd
+
+		// Исключения, на исходные даты которых больше не приходятся повторения, удаляются.
+		dates := make([]string, 0, len(rec.exceptions))
+		for date := range rec.exceptions {
+			dates = append(dates, date)
+		}
+		stale, err := storage.StaleExceptions(rec.event, dates)
+		if err != nil {
+			return 0, 0, err
+		}
+		exceptions := make(map[string]models.Exception, len(rec.exceptions))
+		for date, x := range rec.exceptions {
+			if !slices.Contains(stale, date) {
+				x.Override.Recurrence = rec.event.Recurrence
+				exceptions[date] = x
+			}
+		}
+		rec.exceptions = exceptions
+	}
+	if event.Text != "" {
+		rec.event.Text = event.Text
+	}
+	if event.Tags != nil {
+		rec.event.Tags = event.Tags
+	}
+	if event.Attendees != nil {
+		rec.event.Attendees = event.Attendees
+	}
+	if event.Reminders != nil {
+		rec.event.Reminders = event.Reminders
+	}
+	if event.CalendarID != 0 {
+		rec.event.CalendarID = event.CalendarID
+	}
+	s.events[event.ID] = rec
+	if err = s.schedule(event.ID); err != nil {
+		return 0, 0, err
+	}
+	s.addRevision(revision)
+
+	return event.ID, s.bumpVersion(event.ID), nil
+}
+
+// DeleteEvent перемещает событие в корзину. Для серии scope ограничивает удаление одним повторением
+// или повторениями начиная с occurrenceDate: такие повторения удаляются сразу, минуя корзину.
+// Ненулевая expectedVersion должна совпадать с текущей версией события. Удаление записывается в историю события.
+// Удалять событие может владелец или пользователь, которому календарь события открыт на запись.
+func (s *Storage) DeleteEvent(ctx context.Context, userID, eventID int64, scope models.Scope, occurrenceDate string, expectedVersion int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.remove(userID, eventID, scope, occurrenceDate, expectedVersion)
+}
+
+// remove выполняет DeleteEvent. Вызывается под s.mu.
+func (s *Storage) remove(userID, eventID int64, scope models.Scope, occurrenceDate string, expectedVersion int64) error {
+	rec, err := s.find(userID, eventID)
+	if err != nil {
+		return err
+	}
+	if err = storage.CheckVersion(rec.event, expectedVersion); err != nil {
+		return err
+	}
+
+	before, occurrence, err := changedPart(rec, scope, occurrenceDate)
+	if err != nil {
+		return err
+	}
+	revision := storage.NewRevision(models.ActionDelete, userID, eventID, occurrence, &before, nil)
+
+	if occurrence != "" {
+		switch scope {
+		case models.ScopeThis:
+			if err = s.setException(rec, occurrenceDate, true, models.Event{}); err != nil {
+				return err
+			}
+			if err = s.schedule(eventID); err != nil {
+				return err
+			}
+			s.addRevision(revision)
+			s.bumpVersion(eventID)
+			return nil
+		case models.ScopeFollowing:
+			head, _, err := storage.SplitSeries(rec.event, occurrenceDate)
+			if err != nil {
+				return err
+			}
+			if head != "" {
+				if err = s.truncate(rec, head, occurrenceDate); err != nil {
+					return err
+				}
+				if err = s.schedule(eventID); err != nil {
+					return err
+				}
+				s.addRevision(revision)
+				s.bumpVersion(eventID)
+				return nil
+			}
+		}
+	}
+
+	deletedAt := time.Now().UTC()
+	rec.event.DeletedAt = &deletedAt
+	s.events[eventID] = rec
+	s.addRevision(revision)
+	s.bumpVersion(eventID)
+
+	return nil
+}
+
+// ApplyBatch выполняет операции пакета атомарно и возвращает результат каждой из них.
+// Если continueOnError, ошибка операции отменяет только ее; иначе первая ошибка отменяет весь пакет,
+// а остальные операции получают ErrBatchAborted.
+func (s *Storage) ApplyBatch(ctx context.Context, ops []models.BatchOperation, continueOnError bool) ([]models.BatchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batchStart := s.snapshot()
+	results := make([]models.BatchResult, len(ops))
+	for i, op := range ops {
+		opStart := s.snapshot()
+		if results[i] = s.apply(op); results[i].Err == nil {
+			continue
+		}
+		if !continueOnError {
+			s.restore(batchStart)
+			return storage.AbortBatch(results), nil
+		}
+		s.restore(opStart)
+	}
+
+	return results, nil
+}
+
+func (s *Storage) GetEventsByDay(ctx context.Context, userID int64, day string, filter models.EventFilter) ([]models.Event, error) {
+	date, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return nil, storage.InvalidInput("invalid date format: %v", err)
+	}
+
+	return s.eventsBetween(ctx, userID, date, date.AddDate(0, 0, 1), filter)
+}
+
+func (s *Storage) GetEventsByWeek(ctx context.Context, userID int64, startOfWeek time.Time, filter models.EventFilter) ([]models.Event, error) {
+	endOfWeek := startOfWeek.AddDate(0, 0, 7)
+
+	return s.eventsBetween(ctx, userID, startOfWeek, endOfWeek, filter)
+}
+
+func (s *Storage) GetEventsByMonth(ctx context.Context, userID int64, year int, month time.Month, filter models.EventFilter) ([]models.Event, error) {
+	startOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	endOfMonth := startOfMonth.AddDate(0, 1, 0)
+
+	return s.eventsBetween(ctx, userID, startOfMonth, endOfMonth, filter)
+}
+
+// ListEvents возвращает страницу событий и повторений, начинающихся в днях [query.From, query.To].
+func (s *Storage) ListEvents(ctx context.Context, userID int64, query models.ListQuery) ([]models.Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	lower := query.From
+	if query.After != nil && query.After.Date > lower {
+		lower = query.After.Date
+	}
+
+	s.mu.RLock()
+	grants := s.grantsOf(userID, query.Owner)
+	if grants != nil {
+		var err error
+		userID = query.Owner
+		if query.Calendars, err = storage.SharedCalendars(query.Calendars, grants); err != nil {
+			s.mu.RUnlock()
+			return nil, err
+		}
+	}
+	calendars := models.EventFilter{Calendars: query.Calendars}
+
+	var events []models.Event
+	var exceptions []models.Exception
+	for _, rec := range s.events {
+		if rec.event.UserID != userID || rec.event.DeletedAt != nil || !calendars.MatchCalendar(rec.event.CalendarID) {
+			continue
+		}
+
+		inRange := rec.event.Date <= query.To && (rec.seriesEnd == "" || rec.seriesEnd >= lower)
+		if inRange {
+			events = append(events, rec.event)
+		}
+
+		for _, x := range rec.exceptions {
+			if inRange || !x.Cancelled && x.Override.Date >= lower && x.Override.Date <= query.To {
+				x.Override.Tags = rec.event.Tags
+				x.Override.Attendees = rec.event.Attendees
+				x.Override.Reminders = rec.event.Reminders
+				x.Override.CalendarID = rec.event.CalendarID
+				x.Override.Version = rec.event.Version
+				exceptions = append(exceptions, x)
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	page, err := storage.ListPage(events, exceptions, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.Redact(page, grants), nil
+}
+
+// RespondToEvent записывает ответ пользователя на приглашение на событие. Если пользователь
+// не приглашен или событие в корзине, возвращается ErrInvitationNotFound.
+func (s *Storage) RespondToEvent(ctx context.Context, userID, eventID int64, status models.AttendeeStatus) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.events[eventID]
+	if !ok || rec.event.DeletedAt != nil || rec.event.AttendeeStatus(userID) == "" {
+		return storage.ErrInvitationNotFound
+	}
+
+	respondedAt := time.Now().UTC()
+	attendees := make([]models.Attendee, len(rec.event.Attendees))
+	for i, a := range rec.event.Attendees {
+		if a.UserID == userID {
+			a.Status = status
+			a.RespondedAt = &respondedAt
+		}
+		attendees[i] = a
+	}
+	rec.event.Attendees = attendees
+	s.events[eventID] = rec
+
+	return nil
+}
+
+// ListTags возвращает теги пользователя с числом событий, начиная с самых используемых.
+func (s *Storage) ListTags(ctx context.Context, userID int64) ([]models.TagCount, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	counts := make(map[string]int)
+	for _, rec := range s.events {
+		if rec.event.UserID != userID || rec.event.DeletedAt != nil {
+			continue
+		}
+		for _, tag := range rec.event.Tags {
+			counts[tag]++
+		}
+	}
+	s.mu.RUnlock()
+
+	tags := make([]models.TagCount, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, models.TagCount{Name: name, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Name < tags[j].Name
+	})
+
+	return tags, nil
+}
+
+// SearchEvents ищет события пользователя, текст которых содержит все слова запроса.
+// Ранг — доля слов текста, совпавших с запросом.
+func (s *Storage) SearchEvents(ctx context.Context, userID int64, query models.SearchQuery) ([]models.SearchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	terms := storage.SearchTerms(query.Text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	var results []models.SearchResult
+	for _, rec := range s.events {
+		if rec.event.UserID != userID || rec.event.DeletedAt != nil ||
+			query.To != "" && rec.event.Date > query.To ||
+			query.From != "" && rec.seriesEnd != "" && rec.seriesEnd < query.From {
+			continue
+		}
+
+		words := storage.SearchTerms(rec.event.Text)
+		has := make(map[string]bool, len(words))
+		for _, word := range words {
+			has[word] = true
+		}
+		found := true
+		for _, term := range terms {
+			found = found && has[term]
+		}
+		if !found {
+			continue
+		}
+
+		snippet, matches := storage.Highlight(rec.event.Text, terms)
+		results = append(results, models.SearchResult{
+			Event:   rec.event,
+			Rank:    float64(matches) / float64(len(words)),
+			Snippet: snippet,
+		})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Rank != b.Rank {
+			return a.Rank > b.Rank
+		}
+		if a.Event.Date != b.Event.Date {
+			return a.Event.Date < b.Event.Date
+		}
+		return a.Event.ID < b.Event.ID
+	})
+
+	if query.Limit > 0 && len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+
+	return results, nil
+}
+
+// ListTrash возвращает события пользователя в корзине, начиная с удаленных последними.
+func (s *Storage) ListTrash(ctx context.Context, userID int64) ([]models.Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	var events []models.Event
+	for _, rec := range s.events {
+		if rec.event.UserID == userID && rec.event.DeletedAt != nil {
+			events = append(events, rec.event)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if !a.DeletedAt.Equal(*b.DeletedAt) {
+			return a.DeletedAt.After(*b.DeletedAt)
+		}
+		return a.ID > b.ID
+	})
+
+	return events, nil
+}
+
+// RestoreEvent возвращает событие пользователя из корзины вместе с его тегами и исключениями
+// и записывает восстановление в историю события.
+func (s *Storage) RestoreEvent(ctx context.Context, userID, eventID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.events[eventID]
+	if !ok {
+		return storage.ErrEventNotFound
+	}
+	if err := storage.CheckOwner(rec.event, userID); err != nil {
+		return err
+	}
+	if rec.event.DeletedAt == nil {
+		return storage.ErrEventNotDeleted
+	}
+
+	rec.event.DeletedAt = nil
+	rec.event.Version++
+	s.events[eventID] = rec
+	s.addRevision(storage.NewRevision(models.ActionRestore, userID, eventID, "", nil, &rec.event))
+
+	return s.schedule(eventID)
+}
+
+// EventHistory возвращает историю события пользователя от старых изменений к новым.
+// История доступна и для событий в корзине.
+func (s *Storage) EventHistory(ctx context.Context, userID, eventID int64) ([]models.Revision, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.events[eventID]
+	if !ok {
+		return nil, storage.ErrEventNotFound
+	}
+	if err := storage.CheckOwner(rec.event, userID); err != nil {
+		return nil, err
+	}
+
+	return append([]models.Revision(nil), s.revisions[eventID]...), nil
+}
+
+// PurgeTrash окончательно удаляет события, попавшие в корзину раньше before, и возвращает их число.
+func (s *Storage) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for id, rec := range s.events {
+		if rec.event.DeletedAt != nil && rec.event.DeletedAt.Before(before) {
+			delete(s.events, id)
+			delete(s.revisions, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// FireReminders забирает до limit напоминаний, которым к моменту now пора сработать, и назначает
+// им следующее срабатывание. Каждое напоминание возвращается ровно один раз.
+// Напоминания событий в корзине ждут их восстановления.
+func (s *Storage) FireReminders(ctx context.Context, now time.Time, limit int) ([]models.Notification, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type dueReminder struct {
+		eventID int64
+		index   int
+		reminder
+	}
+	var due []dueReminder
+	for id, rec := range s.events {
+		if rec.event.DeletedAt != nil {
+			continue
+		}
+		for i, r := range rec.reminders {
+			if !r.fireAt.IsZero() && !r.fireAt.After(now) {
+				due = append(due, dueReminder{id, i, r})
+			}
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].fireAt.Equal(due[j].fireAt) {
+			return due[i].fireAt.Before(due[j].fireAt)
+		}
+		return due[i].id < due[j].id
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	var notifications []models.Notification
+	for _, r := range due {
+		rec := s.events[r.eventID]
+		series, exceptions := s.seriesOf(rec)
+
+		occ, fireAt, ok, err := storage.NextReminder(series, exceptions, r.before, r.fireAt.Add(-time.Nanosecond))
+		if err != nil {
+			return nil, err
+		}
+		if ok && fireAt.Equal(r.fireAt) {
+			notifications = append(notifications, storage.NewNotification(r.id, occ, r.before, r.fireAt))
+		}
+
+		_, next, ok, err := storage.NextReminder(series, exceptions, r.before, r.fireAt)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			next = time.Time{}
+		}
+
+		reminders := append([]reminder(nil), rec.reminders...)
+		reminders[r.index].fireAt = next
+		rec.reminders = reminders
+		s.events[r.eventID] = rec
+	}
+
+	return notifications, nil
+}
+
+// CreateUser создает пользователя с профилем user, его календарь по умолчанию и первый ключ доступа key.
+// Занятый email дает ErrEmailTaken.
+func (s *Storage) CreateUser(ctx context.Context, user models.User, key models.APIKey) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.emailTaken(user.Email, 0) {
+		return 0, storage.ErrEmailTaken
+	}
+
+	s.lastUserID++
+	user.ID = s.lastUserID
+	user.CreatedAt = time.Now().UTC()
+	s.users[user.ID] = user
+	s.addCalendar(models.Calendar{
+		UserID:   user.ID,
+		Name:     models.DefaultCalendarName,
+		Color:    models.DefaultCalendarColor,
+		TimeZone: user.TimeZone,
+		Default:  true,
+	})
+	s.addAPIKey(user.ID, key)
+	if record, err := storage.UserOutboxRecord(user); err == nil {
+		s.addOutbox(record)
+	}
+
+	return user.ID, nil
+}
+
+// GetUser возвращает профиль пользователя или ErrUserNotFound.
+func (s *Storage) GetUser(ctx context.Context, userID int64) (models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return models.User{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return models.User{}, storage.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+// UpdateUser меняет непустые поля профиля пользователя user.ID и возвращает профиль после изменения.
+// Занятый email дает ErrEmailTaken.
+func (s *Storage) UpdateUser(ctx context.Context, user models.User) (models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return models.User{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.users[user.ID]
+	if !ok {
+		return models.User{}, storage.ErrUserNotFound
+	}
+	if s.emailTaken(user.Email, user.ID) {
+		return models.User{}, storage.ErrEmailTaken
+	}
+
+	updated := current.Updated(user)
+	s.users[user.ID] = updated
+
+	return updated, nil
+}
+
+// DeleteUser удаляет пользователя вместе с его событиями, тегами и историей событий. История
+// и вебхуки удаляются вместе с пользователем, поэтому об удалении его событий (кроме тех, что в
+// корзине) и его самого сообщают записи outbox.
+func (s *Storage) DeleteUser(ctx context.Context, userID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return storage.ErrUserNotFound
+	}
+
+	var events []models.Event
+	for _, rec := range s.events {
+		if rec.event.UserID == userID && rec.event.DeletedAt == nil {
+			events = append(events, rec.event)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	records, err := storage.UserDeletedOutboxRecords(userID, events, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		s.addOutbox(record)
+	}
+
+	for id, rec := range s.events {
+		if rec.event.UserID == userID {
+			delete(s.events, id)
+			delete(s.revisions, id)
+			continue
+		}
+		if rec.event.AttendeeStatus(userID) != "" {
+			rec.event.Attendees = withoutAttendee(rec.event.Attendees, userID)
+			s.events[id] = rec
+		}
+	}
+	for id, calendar := range s.calendars {
+		if calendar.UserID == userID {
+			delete(s.calendars, id)
+		}
+	}
+	for key, share := range s.shares {
+		if share.OwnerID == userID || share.UserID == userID {
+			delete(s.shares, key)
+		}
+	}
+	for id, key := range s.apiKeys {
+		if key.UserID == userID {
+			delete(s.apiKeys, id)
+		}
+	}
+	for hash, token := range s.refreshTokens {
+		if token.UserID == userID {
+			delete(s.refreshTokens, hash)
+		}
+	}
+	for id, webhook := range s.webhooks {
+		if webhook.UserID == userID {
+			s.deleteWebhook(id)
+		}
+	}
+	delete(s.users, userID)
+
+	return nil
+}
+
+// CreateCalendar создает календарь пользователя calendar.UserID и возвращает его ID.
+// Пустой TimeZone заменяется часовым поясом пользователя. Занятое имя дает ErrCalendarExists.
+func (s *Storage) CreateCalendar(ctx context.Context, calendar models.Calendar) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[calendar.UserID]
+	if !ok {
+		return 0, storage.ErrUserNotFound
+	}
+	if s.calendarNameTaken(calendar.UserID, calendar.Name, 0) {
+		return 0, storage.ErrCalendarExists
+	}
+	if calendar.TimeZone == "" {
+		calendar.TimeZone = user.TimeZone
+	}
+	calendar.Default = false
+
+	return s.addCalendar(calendar), nil
+}
+
+// GetCalendar возвращает календарь пользователя или открытый ему календарь другого пользователя,
+// нулевой calendarID — календарь по умолчанию. Недоступный или несуществующий календарь дает ErrCalendarNotFound.
+func (s *Storage) GetCalendar(ctx context.Context, userID, calendarID int64) (models.Calendar, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Calendar{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, calendar := range s.calendars {
+		if calendar.UserID == userID && (calendar.ID == calendarID || calendarID == 0 && calendar.Default) {
+			return calendar, nil
+		}
+	}
+	if _, ok := s.shares[shareKey{calendarID, userID}]; ok {
+		return s.calendars[calendarID], nil
+	}
+
+	return models.Calendar{}, storage.ErrCalendarNotFound
+}
+
+// ListCalendars возвращает календари пользователя от старых к новым.
+func (s *Storage) ListCalendars(ctx context.Context, userID int64) ([]models.Calendar, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	var calendars []models.Calendar
+	for _, calendar := range s.calendars {
+		if calendar.UserID == userID {
+			calendars = append(calendars, calendar)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(calendars, func(i, j int) bool {
+		return calendars[i].ID < calendars[j].ID
+	})
+
+	return calendars, nil
+}
+
+// UpdateCalendar меняет непустые поля календаря calendar.ID пользователя calendar.UserID
+// и возвращает календарь после изменения. Занятое имя дает ErrCalendarExists.
+func (s *Storage) UpdateCalendar(ctx context.Context, calendar models.Calendar) (models.Calendar, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Calendar{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.calendars[calendar.ID]
+	if !ok || current.UserID != calendar.UserID {
+		return models.Calendar{}, storage.ErrCalendarNotFound
+	}
+	if s.calendarNameTaken(calendar.UserID, calendar.Name, calendar.ID) {
+		return models.Calendar{}, storage.ErrCalendarExists
+	}
+
+	updated := current.Updated(calendar)
+	s.calendars[calendar.ID] = updated
+
+	return updated, nil
+}
+
+// DeleteCalendar удаляет календарь пользователя. Его события перемещаются в корзину так же, как
+// DeleteEvent (с историей, вебхуками и outbox), и переходят в календарь по умолчанию, откуда их
+// можно восстановить. Календарь по умолчанию удалить нельзя: ErrDefaultCalendar.
+func (s *Storage) DeleteCalendar(ctx context.Context, userID, calendarID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calendar, ok := s.calendars[calendarID]
+	if !ok || calendar.UserID != userID {
+		return storage.ErrCalendarNotFound
+	}
+	if calendar.Default {
+		return storage.ErrDefaultCalendar
+	}
+
+	var defaultID int64
+	for _, c := range s.calendars {
+		if c.UserID == userID && c.Default {
+			defaultID = c.ID
+		}
+	}
+
+	var eventIDs []int64
+	for id, rec := range s.events {
+		if rec.event.CalendarID == calendarID && rec.event.DeletedAt == nil {
+			eventIDs = append(eventIDs, id)
+		}
+	}
+	slices.Sort(eventIDs)
+
+	start := s.snapshot()
+	for _, id := range eventIDs {
+		if err := s.remove(userID, id, models.ScopeAll, "", 0); err != nil {
+			s.restore(start)
+			return err
+		}
+	}
+	for id, rec := range s.events {
+		if rec.event.CalendarID == calendarID {
+			rec.event.CalendarID = defaultID
+			s.events[id] = rec
+		}
+	}
+	for key := range s.shares {
+		if key.calendarID == calendarID {
+			delete(s.shares, key)
+		}
+	}
+	delete(s.calendars, calendarID)
+
+	return nil
+}
+
+// ShareCalendar открывает календарь share.CalendarID владельца share.OwnerID пользователю share.UserID
+// с доступом share.Access. Повторная выдача заменяет доступ.
+func (s *Storage) ShareCalendar(ctx context.Context, share models.Share) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if share.UserID == share.OwnerID {
+		return storage.ErrShareOwner
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calendar, ok := s.calendars[share.CalendarID]
+	if !ok || calendar.UserID != share.OwnerID {
+		return storage.ErrCalendarNotFound
+	}
+	if _, ok = s.users[share.UserID]; !ok {
+		return storage.ErrUserNotFound
+	}
+
+	key := shareKey{share.CalendarID, share.UserID}
+	if current, ok := s.shares[key]; ok {
+		share.CreatedAt = current.CreatedAt
+	} else {
+		share.CreatedAt = time.Now().UTC()
+	}
+	s.shares[key] = share
+
+	return nil
+}
+
+// ListShares возвращает доступы, выданные к календарям владельца ownerID, по календарям и пользователям.
+func (s *Storage) ListShares(ctx context.Context, ownerID int64) ([]models.Share, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	var shares []models.Share
+	for _, share := range s.shares {
+		if share.OwnerID == ownerID {
+			shares = append(shares, share)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].CalendarID != shares[j].CalendarID {
+			return shares[i].CalendarID < shares[j].CalendarID
+		}
+		return shares[i].UserID < shares[j].UserID
+	})
+
+	return shares, nil
+}
+
+// RevokeShare закрывает пользователю userID доступ к календарю calendarID владельца ownerID.
+func (s *Storage) RevokeShare(ctx context.Context, ownerID, calendarID, userID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := shareKey{calendarID, userID}
+	share, ok := s.shares[key]
+	if !ok || share.OwnerID != ownerID {
+		return storage.ErrShareNotFound
+	}
+	delete(s.shares, key)
+
+	return nil
+}
+
+// CreateAPIKey выдает пользователю новый ключ доступа key и возвращает ID ключа.
+func (s *Storage) CreateAPIKey(ctx context.Context, userID int64, key models.APIKey) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return 0, storage.ErrUserNotFound
+	}
+
+	return s.addAPIKey(userID, key), nil
+}
+
+// ListAPIKeys возвращает ключи пользователя, включая отозванные, от старых к новым.
+func (s *Storage) ListAPIKeys(ctx context.Context, userID int64) ([]models.APIKey, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var keys []models.APIKey
+	for _, key := range s.apiKeys {
+		if key.UserID == userID {
+			key.Hash = ""
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает ключ пользователя. Повторный отзыв ничего не меняет.
+func (s *Storage) RevokeAPIKey(ctx context.Context, userID, keyID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.apiKeys[keyID]
+	if !ok || key.UserID != userID {
+		return storage.ErrAPIKeyNotFound
+	}
+	if key.RevokedAt == nil {
+		revokedAt := time.Now().UTC()
+		key.RevokedAt = &revokedAt
+		s.apiKeys[keyID] = key
+	}
+
+	return nil
+}
+
+// UserByAPIKey возвращает владельца действующего ключа с хешем hash.
+// Неизвестный или отозванный ключ дает ErrAPIKeyNotFound.
+func (s *Storage) UserByAPIKey(ctx context.Context, hash string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.apiKeys {
+		if key.Hash == hash && key.RevokedAt == nil {
+			return key.UserID, nil
+		}
+	}
+
+	return 0, storage.ErrAPIKeyNotFound
+}
+
+// CreateRefreshToken сохраняет токен обновления token, выданный в обмен на действующий ключ API
+// с хешем keyHash, и возвращает владельца ключа. Неизвестный или отозванный ключ дает ErrAPIKeyNotFound.
+func (s *Storage) CreateRefreshToken(ctx context.Context, keyHash string, token models.RefreshToken) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range s.apiKeys {
+		if key.Hash == keyHash && key.RevokedAt == nil {
+			s.addRefreshToken(key, token)
+			return key.UserID, nil
+		}
+	}
+
+	return 0, storage.ErrAPIKeyNotFound
+}
+
+// RotateRefreshToken обменивает действующий токен обновления с хешем hash на токен next того же
+// пользователя и ключа API и возвращает ID пользователя. Старый токен удаляется, поэтому повторно
+// его не использовать; заодно удаляются истекшие токены пользователя. Истекший, уже использованный
+// токен или токен отозванного ключа дает ErrRefreshTokenNotFound.
+func (s *Storage) RotateRefreshToken(ctx context.Context, hash string, next models.RefreshToken) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	token, ok := s.refreshTokens[hash]
+	if !ok || !token.ExpiresAt.After(now) {
+		return 0, storage.ErrRefreshTokenNotFound
+	}
+	key, ok := s.apiKeys[token.APIKeyID]
+	if !ok || key.RevokedAt != nil {
+		return 0, storage.ErrRefreshTokenNotFound
+	}
+
+	delete(s.refreshTokens, hash)
+	for h, t := range s.refreshTokens {
+		if t.UserID == token.UserID && !t.ExpiresAt.After(now) {
+			delete(s.refreshTokens, h)
+		}
+	}
+	s.addRefreshToken(key, next)
+
+	return key.UserID, nil
+}
+
+// addRefreshToken сохраняет токен обновления, выданный по ключу key. Вызывается под s.mu.
+func (s *Storage) addRefreshToken(key models.APIKey, token models.RefreshToken) {
+	s.lastRefreshTokenID++
+	token.ID = s.lastRefreshTokenID
+	token.UserID = key.UserID
+	token.APIKeyID = key.ID
+	token.CreatedAt = time.Now().UTC()
+	s.refreshTokens[token.Hash] = token
+}
+
+// addCalendar сохраняет календарь и возвращает его ID. Вызывается под s.mu.
+func (s *Storage) addCalendar(calendar models.Calendar) int64 {
+	s.lastCalendarID++
+	calendar.ID = s.lastCalendarID
+	calendar.CreatedAt = time.Now().UTC()
+	s.calendars[calendar.ID] = calendar
+
+	return calendar.ID
+}
+
+// writableCalendar проверяет, что пользователь может добавлять события в календарь calendarID:
+// свой или открытый ему на запись, и возвращает календарь; нулевой calendarID — календарь пользователя
+// по умолчанию. Недоступный или несуществующий календарь дает ErrCalendarNotFound,
+// несуществующий пользователь — ErrUserNotFound. Вызывается под s.mu.
+func (s *Storage) writableCalendar(userID, calendarID int64) (models.Calendar, error) {
+	if _, ok := s.users[userID]; !ok {
+		return models.Calendar{}, storage.ErrUserNotFound
+	}
+
+	for _, calendar := range s.calendars {
+		if calendar.UserID == userID && (calendar.ID == calendarID || calendarID == 0 && calendar.Default) {
+			return calendar, nil
+		}
+	}
+	if share, ok := s.shares[shareKey{calendarID, userID}]; ok && share.Access == models.AccessWrite {
+		return s.calendars[calendarID], nil
+	}
+
+	return models.Calendar{}, storage.ErrCalendarNotFound
+}
+
+// grantsOf возвращает доступ пользователя к календарям владельца owner. Для своих событий
+// (owner 0 или userID) возвращается nil, для чужих — непустой map или пустой, если доступа нет.
+// Вызывается под s.mu.
+func (s *Storage) grantsOf(userID, owner int64) map[int64]models.Access {
+	if owner == 0 || owner == userID {
+		return nil
+	}
+
+	grants := make(map[int64]models.Access)
+	for key, share := range s.shares {
+		if key.userID == userID && share.OwnerID == owner {
+			grants[key.calendarID] = share.Access
+		}
+	}
+
+	return grants
+}
+
+// mergeAttendees возвращает участников change события владельца ownerID: участники из current
+// сохраняют свои ответы. Владелец участником быть не может. Вызывается под s.mu.
+func (s *Storage) mergeAttendees(ownerID int64, current, change []models.Attendee) ([]models.Attendee, error) {
+	if change == nil {
+		return nil, nil
+	}
+
+	attendees := make([]models.Attendee, 0, len(change))
+	for _, a := range change {
+		if a.UserID == ownerID {
+			return nil, storage.ErrAttendeeOwner
+		}
+		if _, ok := s.users[a.UserID]; !ok {
+			return nil, storage.ErrUserNotFound
+		}
+		for _, c := range current {
+			if c.UserID == a.UserID {
+				a = c
+			}
+		}
+		attendees = append(attendees, a)
+	}
+
+	return attendees, nil
+}
+
+// schedule сверяет расписание напоминаний события eventID с его Reminders и пересчитывает,
+// когда они сработают. Напоминание, которому уже пора сработать, не пропускается: его заберет
+// FireReminders. Вызывается под s.mu.
+func (s *Storage) schedule(eventID int64) error {
+	rec := s.events[eventID]
+	if len(rec.reminders) == 0 && len(rec.event.Reminders) == 0 {
+		return nil
+	}
+
+	series, exceptions := s.seriesOf(rec)
+	now := time.Now().UTC()
+
+	reminders := make([]reminder, 0, len(rec.event.Reminders))
+	for _, before := range rec.event.Reminders {
+		r := reminder{before: before}
+		for _, current := range rec.reminders {
+			if current.before == before {
+				r = current
+			}
+		}
+		if r.id == 0 {
+			s.lastReminderID++
+			r.id = s.lastReminderID
+		}
+
+		after := now
+		if !r.fireAt.IsZero() && !r.fireAt.After(now) {
+			after = r.fireAt.Add(-time.Nanosecond)
+		}
+		_, fireAt, ok, err := storage.NextReminder(series, exceptions, before, after)
+		if err != nil {
+			return err
+		}
+		r.fireAt = time.Time{}
+		if ok {
+			r.fireAt = fireAt
+		}
+
+		reminders = append(reminders, r)
+	}
+	rec.reminders = reminders
+	s.events[eventID] = rec
+
+	return nil
+}
+
+// seriesOf возвращает событие rec и исключения его серии для расчета напоминаний. Вызывается под s.mu.
+func (s *Storage) seriesOf(rec record) (models.Event, []models.Exception) {
+	series := rec.event
+	exceptions := make([]models.Exception, 0, len(rec.exceptions))
+	for _, x := range rec.exceptions {
+		exceptions = append(exceptions, x)
+	}
+	storage.WithCalendarZone(&series, exceptions, s.calendars[series.CalendarID].TimeZone)
+
+	return series, exceptions
+}
+
+// withoutAttendee возвращает копию attendees без пользователя userID.
+func withoutAttendee(attendees []models.Attendee, userID int64) []models.Attendee {
+	res := make([]models.Attendee, 0, len(attendees))
+	for _, a := range attendees {
+		if a.UserID != userID {
+			res = append(res, a)
+		}
+	}
+
+	return res
+}
+
+// calendarNameTaken сообщает, что у пользователя уже есть календарь с непустым именем name,
+// отличный от exceptID. Вызывается под s.mu.
+func (s *Storage) calendarNameTaken(userID int64, name string, exceptID int64) bool {
+	if name == "" {
+		return false
+	}
+
+	for id, calendar := range s.calendars {
+		if id != exceptID && calendar.UserID == userID && calendar.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addAPIKey сохраняет ключ пользователя и возвращает его ID. Вызывается под s.mu.
+func (s *Storage) addAPIKey(userID int64, key models.APIKey) int64 {
+	s.lastAPIKeyID++
+	key.ID = s.lastAPIKeyID
+	key.UserID = userID
+	key.CreatedAt = time.Now().UTC()
+	key.RevokedAt = nil
+	s.apiKeys[key.ID] = key
+
+	return key.ID
+}
+
+// emailTaken сообщает, что непустой email уже принадлежит пользователю, отличному от exceptID.
+// Вызывается под s.mu.
+func (s *Storage) emailTaken(email string, exceptID int64) bool {
+	if email == "" {
+		return false
+	}
+
+	for id, user := range s.users {
+		if id != exceptID && user.Email == email {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreateWebhook создает активный вебхук пользователя webhook.UserID и возвращает его ID.
+func (s *Storage) CreateWebhook(ctx context.Context, webhook models.Webhook) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[webhook.UserID]; !ok {
+		return 0, storage.ErrUserNotFound
+	}
+
+	s.lastWebhookID++
+	webhook.ID = s.lastWebhookID
+	webhook.Active = true
+	webhook.Failures = 0
+	webhook.CreatedAt = time.Now().UTC()
+	webhook.DisabledAt = nil
+	s.webhooks[webhook.ID] = webhook
+
+	return webhook.ID, nil
+}
+
+// ListWebhooks возвращает вебхуки пользователя, включая выключенные, от старых к новым.
+func (s *Storage) ListWebhooks(ctx context.Context, userID int64) ([]models.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var webhooks []models.Webhook
+	for _, webhook := range s.webhooks {
+		if webhook.UserID == userID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+
+	return webhooks, nil
+}
+
+// UpdateWebhook меняет вебхук пользователя по patch и возвращает его после изменения.
+func (s *Storage) UpdateWebhook(ctx context.Context, patch models.WebhookPatch) (models.Webhook, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Webhook{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhook, ok := s.webhooks[patch.ID]
+	if !ok || webhook.UserID != patch.UserID {
+		return models.Webhook{}, storage.ErrWebhookNotFound
+	}
+	webhook = webhook.Updated(patch, time.Now().UTC())
+	s.webhooks[webhook.ID] = webhook
+
+	return webhook, nil
+}
+
+// DeleteWebhook удаляет вебхук пользователя вместе с его доставками.
+func (s *Storage) DeleteWebhook(ctx context.Context, userID, webhookID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	webhook, ok := s.webhooks[webhookID]
+	if !ok || webhook.UserID != userID {
+		return storage.ErrWebhookNotFound
+	}
+	s.deleteWebhook(webhookID)
+
+	return nil
+}
+
+// ListDeliveries возвращает до limit последних доставок вебхука пользователя, от новых к старым,
+// вместе с их попытками.
+func (s *Storage) ListDeliveries(ctx context.Context, userID, webhookID int64, limit int) ([]models.Delivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	webhook, ok := s.webhooks[webhookID]
+	if !ok || webhook.UserID != userID {
+		return nil, storage.ErrWebhookNotFound
+	}
+
+	var deliveries []models.Delivery
+	for _, d := range s.deliveries {
+		if d.WebhookID == webhookID {
+			deliveries = append(deliveries, d)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDeliveries забирает на отправку до limit доставок активных вебхуков, которым к моменту now
+// пора уйти, и откладывает их следующую попытку до now+lease: если отправка не закончится
+// записью попытки, доставки вернутся в очередь.
+func (s *Storage) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.Delivery, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deliveries []models.Delivery
+	for _, d := range s.deliveries {
+		if d.Status == models.DeliveryPending && !d.NextAttemptAt.After(now) && s.webhooks[d.WebhookID].Active {
+			deliveries = append(deliveries, d)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		a, b := deliveries[i], deliveries[j]
+		if !a.NextAttemptAt.Equal(*b.NextAttemptAt) {
+			return a.NextAttemptAt.Before(*b.NextAttemptAt)
+		}
+		return a.ID < b.ID
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	leaseUntil := now.Add(lease).UTC()
+	for i, d := range deliveries {
+		d.NextAttemptAt = &leaseUntil
+		s.deliveries[d.ID] = d
+
+		webhook := s.webhooks[d.WebhookID]
+		d.URL = webhook.URL
+		d.Secret = webhook.Secret
+		d.History = nil
+		deliveries[i] = d
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID < deliveries[j].ID })
+
+	return deliveries, nil
+}
+
+// RecordAttempt записывает попытку доставки и ее исход: retryAt — время следующей попытки
+// неудачной доставки, nil отмечает доставку проваленной. Удачная попытка обнуляет счетчик
+// неудач вебхука, неудачная увеличивает его, и после disableAfter неудач подряд вебхук
+// выключается (нулевой disableAfter не выключает вебхуки). Возвращает true, если эта попытка
+// выключила вебхук. Доставки удаленного вебхука не записываются.
+func (s *Storage) RecordAttempt(ctx context.Context, attempt models.DeliveryAttempt, retryAt *time.Time, disableAfter int) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.deliveries[attempt.DeliveryID]
+	if !ok {
+		return false, nil
+	}
+	d.Status = storage.AttemptOutcome(attempt, retryAt)
+	d.Attempts++
+	d.NextAttemptAt = nil
+	if retryAt != nil {
+		next := retryAt.UTC()
+		d.NextAttemptAt = &next
+	}
+	attempt.AttemptedAt = attempt.AttemptedAt.UTC()
+	d.History = append(slices.Clip(d.History), attempt)
+	s.deliveries[d.ID] = d
+
+	webhook := s.webhooks[d.WebhookID]
+	if attempt.OK() {
+		webhook.Failures = 0
+		s.webhooks[webhook.ID] = webhook
+
+		return false, nil
+	}
+
+	webhook.Failures++
+	disabled := disableAfter > 0 && webhook.Active && webhook.Failures >= disableAfter
+	if disabled {
+		webhook.Active = false
+		webhook.DisabledAt = &attempt.AttemptedAt
+	}
+	s.webhooks[webhook.ID] = webhook
+
+	return disabled, nil
+}
+
+// RelayOutbox передает publish до limit неотправленных записей outbox по порядку id и отмечает
+// отправленными те, что publish принял; на первой ошибке публикация останавливается, чтобы
+// не нарушить порядок, и ошибка возвращается вместе с числом отправленных записей.
+// Записи публикуются без s.mu, поэтому публикация не задерживает запись изменений.
+func (s *Storage) RelayOutbox(ctx context.Context, limit int, publish func(models.OutboxRecord) error) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.relayMu.Lock()
+	defer s.relayMu.Unlock()
+
+	s.mu.RLock()
+	records := slices.Clone(s.outbox[:min(max(limit, 0), len(s.outbox))])
+	s.mu.RUnlock()
+
+	sent := 0
+	var publishErr error
+	for _, record := range records {
+		if publishErr = publish(record); publishErr != nil {
+			break
+		}
+		sent++
+	}
+	if sent == 0 {
+		return 0, publishErr
+	}
+
+	// Пока шла публикация, в outbox могли дописать записи, но отправленные остаются в его начале.
+	s.mu.Lock()
+	s.outbox = slices.Clone(s.outbox[sent:])
+	s.mu.Unlock()
+
+	return sent, publishErr
+}
+
+func (s *Storage) Close() error {
+	return nil
+}
+
+// bumpVersion увеличивает версию события и возвращает новую. Вызывается под s.mu.
+// apply выполняет операцию пакета. Вызывается под s.mu.
+func (s *Storage) apply(op models.BatchOperation) models.BatchResult {
+	var res models.BatchResult
+	switch op.Action {
+	case models.ActionCreate:
+		res.EventID, res.Err = s.save(op.Event)
+		res.Version = models.InitialVersion
+	case models.ActionUpdate:
+		res.EventID, res.Version, res.Err = s.update(op.Event, op.Scope, op.OccurrenceDate)
+	case models.ActionDelete:
+		res.EventID = op.Event.ID
+		res.Err = s.remove(op.Event.UserID, op.Event.ID, op.Scope, op.OccurrenceDate, op.Event.Version)
+	default:
+		res.Err = storage.InvalidInput("unsupported batch action %q", op.Action)
+	}
+
+	return res
+}
+
+// state — копия изменяемого состояния хранилища для отката пакета, включая доставки на вебхуки,
+// поставленные операциями пакета. Записи и срезы истории не копируются: изменения заменяют их,
+// а не правят на месте.
+type state struct {
+	events         map[int64]record
+	revisions      map[int64][]models.Revision
+	deliveries     map[int64]models.Delivery
+	outbox         []models.OutboxRecord
+	lastEventID    int64
+	lastRevisionID int64
+	lastDeliveryID int64
+	lastOutboxID   int64
+}
+
+// snapshot копирует состояние хранилища. Вызывается под s.mu.
+func (s *Storage) snapshot() state {
+	st := state{
+		events:         make(map[int64]record, len(s.events)),
+		revisions:      make(map[int64][]models.Revision, len(s.revisions)),
+		deliveries:     make(map[int64]models.Delivery, len(s.deliveries)),
+		outbox:         slices.Clip(s.outbox),
+		lastEventID:    s.lastEventID,
+		lastRevisionID: s.lastRevisionID,
+		lastDeliveryID: s.lastDeliveryID,
+		lastOutboxID:   s.lastOutboxID,
+	}
+	for id, rec := range s.events {
+		st.events[id] = rec
+	}
+	for id, revs := range s.revisions {
+		st.revisions[id] = revs
+	}
+	for id, d := range s.deliveries {
+		st.deliveries[id] = d
+	}
+
+	return st
+}
+
+// restore возвращает хранилище к состоянию st. Вызывается под s.mu.
+func (s *Storage) restore(st state) {
+	s.events = st.events
+	s.revisions = st.revisions
+	s.deliveries = st.deliveries
+	s.outbox = st.outbox
+	s.lastEventID = st.lastEventID
+	s.lastRevisionID = st.lastRevisionID
+	s.lastDeliveryID = st.lastDeliveryID
+	s.lastOutboxID = st.lastOutboxID
+}
+
+func (s *Storage) bumpVersion(eventID int64) int64 {
+	rec := s.events[eventID]
+	rec.event.Version++
+	s.events[eventID] = rec
+
+	return rec.event.Version
+}
+
+// addRevision записывает изменение в историю события, ставит его в очередь доставки на вебхуки
+// и записывает в outbox. Вызывается под s.mu.
+func (s *Storage) addRevision(rev models.Revision) {
+	s.lastRevisionID++
+	rev.ID = s.lastRevisionID
+	rev.ChangedAt = time.Now().UTC()
+	s.revisions[rev.EventID] = append(s.revisions[rev.EventID], rev)
+	s.enqueue(rev)
+	// Ошибка возможна только у действия без типа изменения, как и в enqueue.
+	if record, err := storage.EventOutboxRecord(rev); err == nil {
+		s.addOutbox(record)
+	}
+}
+
+// addOutbox записывает уведомление record в outbox. Вызывается под s.mu.
+func (s *Storage) addOutbox(record models.OutboxRecord) {
+	s.lastOutboxID++
+	record.ID = s.lastOutboxID
+	s.outbox = append(s.outbox, record)
+}
+
+// enqueue ставит изменение rev в очередь доставки на активные вебхуки владельца события,
+// подписанные на изменения этого типа. Вызывается под s.mu.
+func (s *Storage) enqueue(rev models.Revision) {
+	// Ошибка возможна только у действия без типа изменения: такие изменения вебхукам не отправляются.
+	eventType, payload, err := storage.WebhookPayload(rev)
+	if err != nil {
+		return
+	}
+
+	owner := s.events[rev.EventID].event.UserID
+	for _, webhook := range s.webhooks {
+		if webhook.UserID != owner || !webhook.Accepts(eventType) {
+			continue
+		}
+
+		s.lastDeliveryID++
+		s.deliveries[s.lastDeliveryID] = models.Delivery{
+			ID:            s.lastDeliveryID,
+			WebhookID:     webhook.ID,
+			EventType:     eventType,
+			Payload:       payload,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: &rev.ChangedAt,
+			CreatedAt:     rev.ChangedAt,
+		}
+	}
+}
+
+// deleteWebhook удаляет вебхук вместе с его доставками. Вызывается под s.mu.
+func (s *Storage) deleteWebhook(webhookID int64) {
+	delete(s.webhooks, webhookID)
+	for id, d := range s.deliveries {
+		if d.WebhookID == webhookID {
+			delete(s.deliveries, id)
+		}
+	}
+}
+
+// changedPart возвращает событие или повторение серии rec, которое затрагивает изменение со scope,
+// и исходную дату повторения (пустую, если изменение касается всего события).
+func changedPart(rec record, scope models.Scope, occurrenceDate string) (models.Event, string, error) {
+	if rec.event.Recurrence == "" || scope == models.ScopeAll {
+		return rec.event, "", nil
+	}
+	if err := storage.CheckOccurrence(rec.event, occurrenceDate); err != nil {
+		return models.Event{}, "", err
+	}
+
+	var x *models.Exception
+	if exception, ok := rec.exceptions[occurrenceDate]; ok {
+		x = &exception
+	}
+
+	return storage.Occurrence(rec.event, occurrenceDate, x), occurrenceDate, nil
+}
+
+// find возвращает событие не из корзины, которое пользователь может менять. Если события нет
+// или оно в корзине, возвращается ErrEventNotFound, если оно принадлежит другому пользователю,
+// не открывшему календарь события на запись, — ErrEventForbidden. Вызывается под s.mu.
+func (s *Storage) find(userID, eventID int64) (record, error) {
+	rec, ok := s.events[eventID]
+	if !ok || rec.event.DeletedAt != nil {
+		return record{}, storage.ErrEventNotFound
+	}
+	if rec.event.UserID == userID {
+		return rec, nil
+	}
+	if share, ok := s.shares[shareKey{rec.event.CalendarID, userID}]; !ok || share.Access != models.AccessWrite {
+		return record{}, storage.ErrEventForbidden
+	}
+
+	return rec, nil
+}
+
+// setException заменяет или отменяет повторение серии rec с исходной датой originalDate.
+// Вызывается под s.mu.
+func (s *Storage) setException(rec record, originalDate string, cancelled bool, override models.Event) error {
+	if cancelled {
+		override = models.Event{Date: originalDate}
+	} else {
+		date, err := time.Parse("2006-01-02", override.Date)
+		if err != nil {
+			return storage.InvalidInput("invalid date format: %v", err)
+		}
+		override.Date = date.Format("2006-01-02")
+	}
+	override.UserID = rec.event.UserID
+	override.Recurrence = rec.event.Recurrence
+
+	exceptions := make(map[string]models.Exception, len(rec.exceptions)+1)
+	for date, x := range rec.exceptions {
+		exceptions[date] = x
+	}
+	exceptions[originalDate] = models.Exception{
+		EventID:      rec.event.ID,
+		OriginalDate: originalDate,
+		Cancelled:    cancelled,
+		Override:     override,
+	}
+	rec.exceptions = exceptions
+	s.events[rec.event.ID] = rec
+
+	return nil
+}
+
+// truncate оставляет в серии rec только повторения по правилу head, которое заканчивается
+// до дня at, и удаляет исключения начиная с at. Вызывается под s.mu.
+func (s *Storage) truncate(rec record, head, at string) error {
+	rec.event.Recurrence = head
+	seriesEnd, err := storage.SeriesEnd(rec.event)
+	if err != nil {
+		return err
+	}
+	rec.seriesEnd = seriesEnd
+
+	exceptions := make(map[string]models.Exception, len(rec.exceptions))
+	for date, x := range rec.exceptions {
+		if date < at {
+			x.Override.Recurrence = head
+			exceptions[date] = x
+		}
+	}
+	rec.exceptions = exceptions
+	s.events[rec.event.ID] = rec
+
+	return nil
+}
+
+// eventsBetween возвращает события и повторения событий пользователя и событий, на которые
+// он приглашен, которые занимают хотя бы один день из полуинтервала [from, to) и подходят под filter,
+// в том же порядке, что и postgres. В выборку из календарей другого владельца приглашения не попадают.
+func (s *Storage) eventsBetween(ctx context.Context, userID int64, from, to time.Time, filter models.EventFilter) ([]models.Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	lower := from.Format("2006-01-02")
+	upper := to.Format("2006-01-02")
+
+	s.mu.RLock()
+	grants := s.grantsOf(userID, filter.Owner)
+	if grants != nil {
+		var err error
+		userID = filter.Owner
+		if filter.Calendars, err = storage.SharedCalendars(filter.Calendars, grants); err != nil {
+			s.mu.RUnlock()
+			return nil, err
+		}
+	}
+
+	var events []models.Event
+	var exceptions []models.Exception
+	for _, rec := range s.events {
+		own := rec.event.UserID == userID || grants == nil && rec.event.AttendeeStatus(userID) != ""
+		if !own || rec.event.DeletedAt != nil {
+			continue
+		}
+
+		inWindow := rec.event.Date < upper && (rec.seriesEnd == "" || rec.seriesEnd >= lower)
+		if inWindow {
+			events = append(events, rec.event)
+		}
+
+		// Измененное повторение могло переехать в окно из-за его пределов.
+		for _, x := range rec.exceptions {
+			first, last := x.Override.Days()
+			if inWindow || !x.Cancelled && first < upper && last >= lower {
+				x.Override.Tags = rec.event.Tags
+				x.Override.Attendees = rec.event.Attendees
+				x.Override.Reminders = rec.event.Reminders
+				x.Override.CalendarID = rec.event.CalendarID
+				x.Override.Version = rec.event.Version
+				exceptions = append(exceptions, x)
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	events, exceptions = storage.FilterEvents(events, exceptions, filter, grants)
+
+	expanded, err := storage.ExpandRecurring(events, exceptions, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.Redact(expanded, grants), nil
+}
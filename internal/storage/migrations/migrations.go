@@ -0,0 +1,330 @@
+package migrations
+
+import (
+	"Events-Service/internal/config"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Файлы миграций лежат в каталоге драйвера и называются NNNN_name.up.sql / NNNN_name.down.sql.
+//
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// lockKey — ключ advisory-блокировки postgres, под которой применяются миграции,
+// чтобы несколько реплик, стартующих одновременно, не применили одну миграцию дважды.
+const lockKey = 7_201_105_114
+
+const createTableQuery = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at TIMESTAMP NOT NULL
+)`
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	driver     string
+	migrations []Migration
+}
+
+func New(db *sql.DB, driver string) (*Migrator, error) {
+	migrations, err := load(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		driver:     driver,
+		migrations: migrations,
+	}, nil
+}
+
+// Up применяет все еще не примененные миграции по возрастанию версии.
+// Каждая миграция выполняется в отдельной транзакции вместе с записью в schema_migrations.
+func (m *Migrator) Up() ([]Migration, error) {
+	var applied []Migration
+	for _, migration := range m.migrations {
+		ok, err := m.apply(migration)
+		if err != nil {
+			return applied, fmt.Errorf("migration %04d_%s: %v", migration.Version, migration.Name, err)
+		}
+		if ok {
+			applied = append(applied, migration)
+		}
+	}
+
+	return applied, nil
+}
+
+// Down откатывает последнюю примененную миграцию. Если откатывать нечего, возвращает nil.
+func (m *Migrator) Down() (*Migration, error) {
+	tx, err := m.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var version int64
+	err = tx.QueryRow("SELECT version FROM schema_migrations ORDER BY version DESC LIMIT 1").Scan(&version)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current version: %v", err)
+	}
+
+	migration, ok := m.find(version)
+	if !ok {
+		return nil, fmt.Errorf("migration %d is applied but unknown to this build", version)
+	}
+
+	if _, err = tx.Exec(migration.Down); err != nil {
+		return nil, fmt.Errorf("migration %04d_%s: %v", migration.Version, migration.Name, err)
+	}
+
+	if _, err = tx.Exec(m.bind("DELETE FROM schema_migrations WHERE version = $1"), version); err != nil {
+		return nil, fmt.Errorf("failed to unregister migration: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit migration: %v", err)
+	}
+
+	return &migration, nil
+}
+
+// Status возвращает все известные миграции с признаком применения.
+// Версии, примененные в базе, но отсутствующие в сборке, тоже попадают в список.
+func (m *Migrator) Status() ([]Status, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Query("SELECT version, name, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]Status)
+	for rows.Next() {
+		var st Status
+		if err = rows.Scan(&st.Version, &st.Name, &st.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to read schema_migrations: %v", err)
+		}
+		st.Applied = true
+		applied[st.Version] = st
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %v", err)
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		st, ok := applied[migration.Version]
+		if !ok {
+			st = Status{Version: migration.Version, Name: migration.Name}
+		}
+		delete(applied, migration.Version)
+		statuses = append(statuses, st)
+	}
+	for _, st := range applied {
+		statuses = append(statuses, st)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
+func (m *Migrator) apply(migration Migration) (bool, error) {
+	tx, err := m.begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// Проверяем версию уже под блокировкой: другая реплика могла применить ее, пока мы ждали.
+	var exists bool
+	err = tx.QueryRow(
+		m.bind("SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = $1)"),
+		migration.Version,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check version: %v", err)
+	}
+	if exists {
+		return false, nil
+	}
+
+	if _, err = tx.Exec(migration.Up); err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(
+		m.bind("INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)"),
+		migration.Version, migration.Name, time.Now().UTC(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to register migration: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit migration: %v", err)
+	}
+
+	return true, nil
+}
+
+// begin открывает транзакцию, в которой изменения схемы сериализованы между процессами:
+// в postgres — advisory-блокировкой, в sqlite — блокировкой файла (BEGIN IMMEDIATE, см. DSN).
+func (m *Migrator) begin() (*sql.Tx, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+
+	if m.driver == config.DriverPostgres {
+		if _, err = tx.Exec("SELECT pg_advisory_xact_lock($1)", lockKey); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to acquire migration lock: %v", err)
+		}
+	}
+
+	if _, err = tx.Exec(createTableQuery); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to create schema_migrations: %v", err)
+	}
+
+	return tx, nil
+}
+
+func (m *Migrator) ensureTable() error {
+	if _, err := m.db.Exec(createTableQuery); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %v", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+
+	return Migration{}, false
+}
+
+// bind переводит плейсхолдеры postgres ($1) в нумерованные плейсхолдеры sqlite (?1).
+func (m *Migrator) bind(query string) string {
+	if m.driver == config.DriverSQLite {
+		return strings.ReplaceAll(query, "$", "?")
+	}
+
+	return query
+}
+
+func load(driver string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, driver)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for driver %q", driver)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		version, name, direction, err := parseFileName(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := files.ReadFile(path.Join(driver, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %v", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+		if migration.Name != name {
+			return nil, fmt.Errorf("migration %d has different names: %q and %q", version, migration.Name, name)
+		}
+
+		switch direction {
+		case "up":
+			migration.Up = string(body)
+		case "down":
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// parseFileName разбирает имя вида 0001_init.up.sql на версию, название и направление.
+func parseFileName(fileName string) (int64, string, string, error) {
+	base, ok := strings.CutSuffix(fileName, ".sql")
+	if !ok {
+		return 0, "", "", fmt.Errorf("unexpected migration file %q", fileName)
+	}
+
+	dot := strings.LastIndex(base, ".")
+	if dot < 0 {
+		return 0, "", "", fmt.Errorf("migration file %q has no direction", fileName)
+	}
+	direction := base[dot+1:]
+	if direction != "up" && direction != "down" {
+		return 0, "", "", fmt.Errorf("migration file %q has unknown direction %q", fileName, direction)
+	}
+
+	versionStr, name, ok := strings.Cut(base[:dot], "_")
+	if !ok || name == "" {
+		return 0, "", "", fmt.Errorf("migration file %q has no name", fileName)
+	}
+
+	version, err := strconv.ParseInt(versionStr, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, "", "", fmt.Errorf("migration file %q has invalid version", fileName)
+	}
+
+	return version, name, direction, nil
+}
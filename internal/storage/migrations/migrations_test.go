@@ -0,0 +1,94 @@
+package migrations
+
+import (
+	"Events-Service/internal/config"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestLoad_AllDrivers(t *testing.T) {
+	for _, driver := range []string{config.DriverPostgres, config.DriverSQLite} {
+		migrations, err := load(driver)
+		require.NoError(t, err, driver)
+		require.NotEmpty(t, migrations, driver)
+
+		for i, migration := range migrations {
+			assert.NotEmpty(t, migration.Up, driver)
+			assert.NotEmpty(t, migration.Down, driver)
+			if i > 0 {
+				assert.Greater(t, migration.Version, migrations[i-1].Version, driver)
+			}
+		}
+	}
+}
+
+func TestLoad_SameVersionsForAllDrivers(t *testing.T) {
+	pg, err := load(config.DriverPostgres)
+	require.NoError(t, err)
+	lite, err := load(config.DriverSQLite)
+	require.NoError(t, err)
+
+	require.Equal(t, len(pg), len(lite))
+	for i := range pg {
+		assert.Equal(t, pg[i].Version, lite[i].Version)
+		assert.Equal(t, pg[i].Name, lite[i].Name)
+	}
+}
+
+func TestParseFileName(t *testing.T) {
+	version, name, direction, err := parseFileName("0012_add_tags.down.sql")
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), version)
+	assert.Equal(t, "add_tags", name)
+	assert.Equal(t, "down", direction)
+
+	for _, bad := range []string{"init.up.sql", "0001_init.sql", "0001_init.sideways.sql", "0001.up.sql", "0000_init.up.sql", "0001_init.up.txt"} {
+		_, _, _, err = parseFileName(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestMigrator_UpDownStatus(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "test.db")+"?_txlock=immediate")
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := New(db, config.DriverSQLite)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrator.migrations))
+
+	applied, err = migrator.Up()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err := migrator.Status()
+	require.NoError(t, err)
+	for _, st := range statuses {
+		assert.True(t, st.Applied, st.Name)
+	}
+
+	for range migrator.migrations {
+		rolledBack, err := migrator.Down()
+		require.NoError(t, err)
+		require.NotNil(t, rolledBack)
+	}
+
+	rolledBack, err := migrator.Down()
+	require.NoError(t, err)
+	assert.Nil(t, rolledBack)
+
+	statuses, err = migrator.Status()
+	require.NoError(t, err)
+	for _, st := range statuses {
+		assert.False(t, st.Applied, st.Name)
+	}
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS event;
+DROP TABLE IF EXISTS users;
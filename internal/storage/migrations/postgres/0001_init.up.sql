@@ -0,0 +1,13 @@
+-- IF NOT EXISTS позволяет подхватить базы, созданные вручную по README до появления миграций.
+CREATE TABLE IF NOT EXISTS users (
+    user_id SERIAL PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS event (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    text TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_user_date ON event (user_id, date);
@@ -0,0 +1,5 @@
+ALTER TABLE event
+    DROP COLUMN time_zone,
+    DROP COLUMN ends_at,
+    DROP COLUMN starts_at,
+    DROP COLUMN end_date;
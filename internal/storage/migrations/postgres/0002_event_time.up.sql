@@ -0,0 +1,11 @@
+-- end_date — последний день (включительно), который занимает событие в своем часовом поясе.
+-- Для событий на весь день он совпадает с date.
+ALTER TABLE event
+    ADD COLUMN end_date DATE,
+    ADD COLUMN starts_at TIMESTAMPTZ,
+    ADD COLUMN ends_at TIMESTAMPTZ,
+    ADD COLUMN time_zone TEXT NOT NULL DEFAULT '';
+
+UPDATE event SET end_date = date;
+
+ALTER TABLE event ALTER COLUMN end_date SET NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE event
+    DROP COLUMN series_end,
+    DROP COLUMN rrule;
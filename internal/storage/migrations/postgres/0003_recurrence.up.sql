@@ -0,0 +1,7 @@
+-- series_end — последний день, который может занять событие с учетом повторений.
+-- NULL означает бесконечную серию, у неповторяющихся событий он совпадает с end_date.
+ALTER TABLE event
+    ADD COLUMN rrule TEXT NOT NULL DEFAULT '',
+    ADD COLUMN series_end DATE;
+
+UPDATE event SET series_end = end_date;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS event_tag;
+DROP TABLE IF EXISTS tag;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_event_text_search;
+
+ALTER TABLE event DROP COLUMN IF EXISTS text_search;
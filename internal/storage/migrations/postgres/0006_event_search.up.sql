@@ -0,0 +1,6 @@
+-- Полнотекстовый индекс по тексту события. Конфигурация simple не зависит от языка:
+-- слова только приводятся к нижнему регистру, без стемминга.
+ALTER TABLE event
+    ADD COLUMN IF NOT EXISTS text_search TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', text)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_event_text_search ON event USING GIN (text_search);
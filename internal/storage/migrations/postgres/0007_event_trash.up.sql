@@ -0,0 +1,4 @@
+-- deleted_at — момент перемещения события в корзину, NULL у активных событий.
+ALTER TABLE event ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_event_deleted_at ON event (deleted_at) WHERE deleted_at IS NOT NULL;
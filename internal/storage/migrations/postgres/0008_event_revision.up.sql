@@ -0,0 +1,16 @@
+-- История изменений событий. user_id — автор изменения, occurrence_date задан,
+-- если изменение затронуло одно повторение серии или повторения начиная с него.
+CREATE TABLE IF NOT EXISTS event_revision (
+    id SERIAL PRIMARY KEY,
+    event_id INT NOT NULL REFERENCES event(id) ON DELETE CASCADE,
+    user_id INT NOT NULL,
+    action TEXT NOT NULL,
+    occurrence_date DATE,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    old_date DATE,
+    new_date DATE,
+    old_text TEXT,
+    new_text TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_revision_event ON event_revision (event_id, id);
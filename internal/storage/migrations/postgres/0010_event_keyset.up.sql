@@ -0,0 +1,3 @@
+-- Индекс для постраничной выборки /events по ключу (date, id), удаленные события в него не попадают.
+CREATE INDEX IF NOT EXISTS idx_event_user_date_id ON event (user_id, date, id) WHERE deleted_at IS NULL;
+DROP INDEX IF EXISTS idx_event_user_date;
@@ -0,0 +1,25 @@
+-- Календари пользователя. Каждое событие принадлежит одному календарю и удаляется вместе с ним.
+-- Существующие события переносятся в календарь по умолчанию, который создается каждому пользователю.
+CREATE TABLE IF NOT EXISTS calendar (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    color TEXT NOT NULL,
+    time_zone TEXT NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, name)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_calendar_default ON calendar (user_id) WHERE is_default;
+
+INSERT INTO calendar (user_id, name, color, time_zone, is_default)
+SELECT user_id, 'Default', '#4a90e2', time_zone, TRUE FROM users
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE event ADD COLUMN IF NOT EXISTS calendar_id INT REFERENCES calendar(id) ON DELETE CASCADE;
+UPDATE event SET calendar_id = c.id FROM calendar c
+WHERE c.user_id = event.user_id AND c.is_default AND event.calendar_id IS NULL;
+ALTER TABLE event ALTER COLUMN calendar_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_event_calendar ON event (calendar_id);
@@ -0,0 +1,11 @@
+-- Доступ к календарю, выданный владельцем другому пользователю: freebusy (только занятость),
+-- read (чтение событий) или write (чтение и изменение). Удаляется вместе с календарем и пользователем.
+CREATE TABLE IF NOT EXISTS calendar_share (
+    calendar_id INT NOT NULL REFERENCES calendar(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    access TEXT NOT NULL CHECK (access IN ('freebusy', 'read', 'write')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (calendar_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_share_user ON calendar_share (user_id);
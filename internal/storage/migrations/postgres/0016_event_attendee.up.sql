@@ -0,0 +1,11 @@
+-- Участники события: приглашенные пользователи и их ответы на приглашение.
+-- Удаляются вместе с событием и пользователем.
+CREATE TABLE IF NOT EXISTS event_attendee (
+    event_id INT NOT NULL REFERENCES event(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'needs-action' CHECK (status IN ('needs-action', 'accepted', 'declined', 'tentative')),
+    responded_at TIMESTAMPTZ,
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_attendee_user ON event_attendee (user_id);
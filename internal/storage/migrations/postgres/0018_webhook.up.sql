@@ -0,0 +1,44 @@
+-- Вебхуки пользователя: адреса, на которые отправляются изменения его событий.
+-- event_types — типы изменений, на которые подписан вебхук, пустой массив — все.
+-- failures — неудачные попытки доставки подряд; после порога вебхук выключается (active = false).
+CREATE TABLE IF NOT EXISTS webhook (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    failures INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    disabled_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_user ON webhook (user_id);
+
+-- Доставки изменений на вебхуки. Создаются в одной транзакции с изменением события,
+-- next_attempt_at — когда доставку нужно попытаться отправить, NULL у завершенных.
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+    id SERIAL PRIMARY KEY,
+    webhook_id INT NOT NULL REFERENCES webhook(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_webhook ON webhook_delivery (webhook_id, id);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_next_attempt_at ON webhook_delivery (next_attempt_at) WHERE status = 'pending';
+
+-- Попытки доставки: код ответа (NULL, если ответа нет), ошибка и длительность запроса.
+CREATE TABLE IF NOT EXISTS webhook_attempt (
+    id SERIAL PRIMARY KEY,
+    delivery_id INT NOT NULL REFERENCES webhook_delivery(id) ON DELETE CASCADE,
+    attempted_at TIMESTAMPTZ NOT NULL,
+    status_code INT,
+    error TEXT NOT NULL DEFAULT '',
+    duration_ms INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_attempt_delivery ON webhook_attempt (delivery_id);
@@ -0,0 +1,17 @@
+-- Исходящие уведомления об изменениях (transactional outbox). Записываются в одной транзакции
+-- с изменением, релей публикует их по порядку id и отмечает отправленными (sent_at).
+-- aggregate_type и aggregate_id — измененная сущность (event, user), type — тип изменения.
+-- tx_horizon — xmax снимка транзакции, сделанного после выдачи id: пока не завершены все
+-- транзакции с меньшими txid, запись с меньшим id еще может появиться, и релей ее ждет.
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id BIGINT NOT NULL,
+    type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    tx_horizon BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unsent ON outbox (id) WHERE sent_at IS NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE event DROP COLUMN time_zone;
+ALTER TABLE event DROP COLUMN ends_at;
+ALTER TABLE event DROP COLUMN starts_at;
+ALTER TABLE event DROP COLUMN end_date;
@@ -0,0 +1,8 @@
+-- end_date — последний день (включительно), который занимает событие в своем часовом поясе.
+-- Для событий на весь день он совпадает с date.
+ALTER TABLE event ADD COLUMN end_date TEXT NOT NULL DEFAULT '';
+ALTER TABLE event ADD COLUMN starts_at DATETIME;
+ALTER TABLE event ADD COLUMN ends_at DATETIME;
+ALTER TABLE event ADD COLUMN time_zone TEXT NOT NULL DEFAULT '';
+
+UPDATE event SET end_date = date;
@@ -0,0 +1,2 @@
+ALTER TABLE event DROP COLUMN series_end;
+ALTER TABLE event DROP COLUMN rrule;
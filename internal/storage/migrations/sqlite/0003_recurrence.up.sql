@@ -0,0 +1,6 @@
+-- series_end — последний день, который может занять событие с учетом повторений.
+-- NULL означает бесконечную серию, у неповторяющихся событий он совпадает с end_date.
+ALTER TABLE event ADD COLUMN rrule TEXT NOT NULL DEFAULT '';
+ALTER TABLE event ADD COLUMN series_end TEXT;
+
+UPDATE event SET series_end = end_date;
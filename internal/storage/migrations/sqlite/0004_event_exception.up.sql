@@ -0,0 +1,16 @@
+-- Исключение заменяет или отменяет одно повторение серии, которое по правилу приходится на original_date.
+-- У отмененного повторения date и end_date совпадают с original_date.
+CREATE TABLE IF NOT EXISTS event_exception (
+    event_id INTEGER NOT NULL REFERENCES event(id) ON DELETE CASCADE,
+    original_date TEXT NOT NULL,
+    cancelled BOOLEAN NOT NULL DEFAULT FALSE,
+    date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    text TEXT NOT NULL DEFAULT '',
+    starts_at DATETIME,
+    ends_at DATETIME,
+    time_zone TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (event_id, original_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_exception_date ON event_exception (date, end_date);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS event_fts_update;
+DROP TRIGGER IF EXISTS event_fts_delete;
+DROP TRIGGER IF EXISTS event_fts_insert;
+DROP TABLE IF EXISTS event_fts;
@@ -0,0 +1,17 @@
+-- Полнотекстовый индекс FTS5 по тексту события, синхронизируется с event триггерами.
+CREATE VIRTUAL TABLE IF NOT EXISTS event_fts USING fts5(text, content='event', content_rowid='id');
+
+INSERT INTO event_fts (event_fts) VALUES ('rebuild');
+
+CREATE TRIGGER IF NOT EXISTS event_fts_insert AFTER INSERT ON event BEGIN
+    INSERT INTO event_fts (rowid, text) VALUES (new.id, new.text);
+END;
+
+CREATE TRIGGER IF NOT EXISTS event_fts_delete AFTER DELETE ON event BEGIN
+    INSERT INTO event_fts (event_fts, rowid, text) VALUES ('delete', old.id, old.text);
+END;
+
+CREATE TRIGGER IF NOT EXISTS event_fts_update AFTER UPDATE OF text ON event BEGIN
+    INSERT INTO event_fts (event_fts, rowid, text) VALUES ('delete', old.id, old.text);
+    INSERT INTO event_fts (rowid, text) VALUES (new.id, new.text);
+END;
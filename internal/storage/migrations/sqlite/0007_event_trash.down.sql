@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_event_deleted_at;
+
+DELETE FROM event WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE event DROP COLUMN deleted_at;
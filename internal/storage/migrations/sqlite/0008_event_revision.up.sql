@@ -0,0 +1,16 @@
+-- История изменений событий. user_id — автор изменения, occurrence_date задан,
+-- если изменение затронуло одно повторение серии или повторения начиная с него.
+CREATE TABLE IF NOT EXISTS event_revision (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id INTEGER NOT NULL REFERENCES event(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    occurrence_date TEXT,
+    changed_at DATETIME NOT NULL,
+    old_date TEXT,
+    new_date TEXT,
+    old_text TEXT,
+    new_text TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_revision_event ON event_revision (event_id, id);
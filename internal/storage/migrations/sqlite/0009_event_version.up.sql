@@ -0,0 +1,2 @@
+-- version растет при каждом изменении события и служит ETag для оптимистичных блокировок.
+ALTER TABLE event ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_event_user_date ON event (user_id, date);
+DROP INDEX IF EXISTS idx_event_user_date_id;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_users_email;
+
+ALTER TABLE users DROP COLUMN created_at;
+ALTER TABLE users DROP COLUMN locale;
+ALTER TABLE users DROP COLUMN time_zone;
+ALTER TABLE users DROP COLUMN email;
+ALTER TABLE users DROP COLUMN name;
@@ -0,0 +1,10 @@
+-- Профиль пользователя. time_zone и locale используются клиентами по умолчанию,
+-- email необязателен, но один адрес не может принадлежать двум пользователям.
+-- created_at (UTC) заполняет сервис: SQLite не добавляет колонку с неконстантным значением по умолчанию.
+ALTER TABLE users ADD COLUMN name TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN email TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN time_zone TEXT NOT NULL DEFAULT 'UTC';
+ALTER TABLE users ADD COLUMN locale TEXT NOT NULL DEFAULT 'en';
+ALTER TABLE users ADD COLUMN created_at DATETIME;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email) WHERE email <> '';
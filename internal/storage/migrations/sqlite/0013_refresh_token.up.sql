@@ -0,0 +1,13 @@
+-- Токены обновления для /token. Хранится только SHA-256 токена. Токен выдается в обмен на ключ API
+-- и удаляется вместе с ним; при обновлении старый токен удаляется, а взамен создается новый.
+CREATE TABLE IF NOT EXISTS refresh_token (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    api_key_id INTEGER NOT NULL REFERENCES api_key(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_token_user ON refresh_token (user_id, expires_at);
+CREATE INDEX IF NOT EXISTS idx_refresh_token_api_key ON refresh_token (api_key_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_event_calendar;
+
+ALTER TABLE event DROP COLUMN calendar_id;
+
+DROP TABLE IF EXISTS calendar;
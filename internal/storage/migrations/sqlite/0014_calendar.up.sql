@@ -0,0 +1,25 @@
+-- Календари пользователя. Каждое событие принадлежит одному календарю и удаляется вместе с ним.
+-- Существующие события переносятся в календарь по умолчанию, который создается каждому пользователю.
+CREATE TABLE IF NOT EXISTS calendar (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    color TEXT NOT NULL,
+    time_zone TEXT NOT NULL,
+    is_default INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    UNIQUE (user_id, name)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_calendar_default ON calendar (user_id) WHERE is_default;
+
+INSERT OR IGNORE INTO calendar (user_id, name, color, time_zone, is_default, created_at)
+SELECT user_id, 'Default', '#4a90e2', time_zone, 1, CURRENT_TIMESTAMP FROM users;
+
+-- Без REFERENCES: SQLite не удаляет колонку с внешним ключом при откате, а добавить NOT NULL
+-- к существующей таблице нельзя. События удаляемого календаря удаляет сервис.
+ALTER TABLE event ADD COLUMN calendar_id INTEGER;
+UPDATE event SET calendar_id = (SELECT c.id FROM calendar c WHERE c.user_id = event.user_id AND c.is_default)
+WHERE calendar_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_event_calendar ON event (calendar_id);
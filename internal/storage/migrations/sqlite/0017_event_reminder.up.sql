@@ -0,0 +1,11 @@
+-- Напоминания о событии: за сколько минут до начала каждого повторения напомнить владельцу.
+-- next_fire_at — когда напоминание сработает в следующий раз, NULL, если больше не сработает.
+CREATE TABLE IF NOT EXISTS event_reminder (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id INTEGER NOT NULL REFERENCES event(id) ON DELETE CASCADE,
+    minutes_before INTEGER NOT NULL CHECK (minutes_before >= 0),
+    next_fire_at DATETIME,
+    UNIQUE (event_id, minutes_before)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_reminder_next_fire_at ON event_reminder (next_fire_at) WHERE next_fire_at IS NOT NULL;
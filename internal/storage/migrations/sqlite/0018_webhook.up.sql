@@ -0,0 +1,44 @@
+-- Вебхуки пользователя: адреса, на которые отправляются изменения его событий.
+-- event_types — типы изменений через запятую, на которые подписан вебхук, пустая строка — все.
+-- failures — неудачные попытки доставки подряд; после порога вебхук выключается (active = 0).
+CREATE TABLE IF NOT EXISTS webhook (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    event_types TEXT NOT NULL DEFAULT '',
+    active INTEGER NOT NULL DEFAULT 1,
+    failures INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    disabled_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_user ON webhook (user_id);
+
+-- Доставки изменений на вебхуки. Создаются в одной транзакции с изменением события,
+-- next_attempt_at — когда доставку нужно попытаться отправить, NULL у завершенных.
+CREATE TABLE IF NOT EXISTS webhook_delivery (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER NOT NULL REFERENCES webhook(id) ON DELETE CASCADE,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME,
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_webhook ON webhook_delivery (webhook_id, id);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_next_attempt_at ON webhook_delivery (next_attempt_at) WHERE status = 'pending';
+
+-- Попытки доставки: код ответа (NULL, если ответа нет), ошибка и длительность запроса.
+CREATE TABLE IF NOT EXISTS webhook_attempt (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    delivery_id INTEGER NOT NULL REFERENCES webhook_delivery(id) ON DELETE CASCADE,
+    attempted_at DATETIME NOT NULL,
+    status_code INTEGER,
+    error TEXT NOT NULL DEFAULT '',
+    duration_ms INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_attempt_delivery ON webhook_attempt (delivery_id);
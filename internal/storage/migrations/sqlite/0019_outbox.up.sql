@@ -0,0 +1,14 @@
+-- Исходящие уведомления об изменениях (transactional outbox). Записываются в одной транзакции
+-- с изменением, релей публикует их по порядку id и отмечает отправленными (sent_at).
+-- aggregate_type и aggregate_id — измененная сущность (event, user), type — тип изменения.
+CREATE TABLE IF NOT EXISTS outbox (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id INTEGER NOT NULL,
+    type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    sent_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unsent ON outbox (id) WHERE sent_at IS NULL;
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"Events-Service/internal/models"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// userCreatedPayload — тело записи outbox о новом пользователе.
+type userCreatedPayload struct {
+	Type      string `json:"type"`
+	UserId    int64  `json:"user_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	TimeZone  string `json:"time_zone"`
+	CreatedAt string `json:"created_at"`
+}
+
+// userDeletedPayload — тело записи outbox об удаленном пользователе.
+type userDeletedPayload struct {
+	Type      string `json:"type"`
+	UserId    int64  `json:"user_id"`
+	DeletedAt string `json:"deleted_at"`
+}
+
+// EventOutboxRecord возвращает запись outbox об изменении события rev. Тело совпадает с телом
+// доставки на вебхуки (см. WebhookPayload).
+func EventOutboxRecord(rev models.Revision) (models.OutboxRecord, error) {
+	eventType, payload, err := WebhookPayload(rev)
+	if err != nil {
+		return models.OutboxRecord{}, err
+	}
+
+	return models.OutboxRecord{
+		AggregateType: models.AggregateEvent,
+		AggregateID:   rev.EventID,
+		Type:          eventType,
+		Payload:       payload,
+		CreatedAt:     rev.ChangedAt,
+	}, nil
+}
+
+// UserOutboxRecord возвращает запись outbox о новом пользователе user.
+func UserOutboxRecord(user models.User) (models.OutboxRecord, error) {
+	payload, err := json.Marshal(userCreatedPayload{
+		Type:      models.OutboxUserCreated,
+		UserId:    user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		TimeZone:  user.TimeZone,
+		CreatedAt: user.CreatedAt.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return models.OutboxRecord{}, fmt.Errorf("failed to encode outbox payload: %v", err)
+	}
+
+	return models.OutboxRecord{
+		AggregateType: models.AggregateUser,
+		AggregateID:   user.ID,
+		Type:          models.OutboxUserCreated,
+		Payload:       payload,
+		CreatedAt:     user.CreatedAt,
+	}, nil
+}
+
+// UserDeletedOutboxRecords возвращает записи outbox об удалении пользователя userID в момент
+// deletedAt: об удалении каждого его события из events, которые вместе с пользователем удаляются
+// без истории и вебхуков, и об удалении самого пользователя.
+func UserDeletedOutboxRecords(userID int64, events []models.Event, deletedAt time.Time) ([]models.OutboxRecord, error) {
+	records := make([]models.OutboxRecord, 0, len(events)+1)
+	for _, e := range events {
+		rev := NewRevision(models.ActionDelete, userID, e.ID, "", &e, nil)
+		rev.ChangedAt = deletedAt
+		record, err := EventOutboxRecord(rev)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	payload, err := json.Marshal(userDeletedPayload{
+		Type:      models.OutboxUserDeleted,
+		UserId:    userID,
+		DeletedAt: deletedAt.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode outbox payload: %v", err)
+	}
+
+	return append(records, models.OutboxRecord{
+		AggregateType: models.AggregateUser,
+		AggregateID:   userID,
+		Type:          models.OutboxUserDeleted,
+		Payload:       payload,
+		CreatedAt:     deletedAt,
+	}), nil
+}
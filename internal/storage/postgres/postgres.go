@@ -2,21 +2,30 @@ package postgres
 
 import (
 	"Events-Service/internal/config"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/lib/retry"
 	"Events-Service/internal/models"
+	"Events-Service/internal/storage"
+	"Events-Service/internal/storage/migrations"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"sort"
 	"strings"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type Storage struct {
 	db *sql.DB
 }
 
-func InitDB(cfg *config.Config) (*Storage, error) {
+// InitDB открывает пул соединений и ждет доступности базы по политике cfg.Database.ConnectRetry.
+// Если база так и не ответила или ctx отменен, возвращается ошибка.
+func InitDB(ctx context.Context, cfg *config.Config, log *slog.Logger) (*Storage, error) {
 	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Database.Host,
 		cfg.Database.Port,
@@ -28,197 +37,2823 @@ func InitDB(cfg *config.Config) (*Storage, error) {
 
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
-		log.Fatalf("db connection error: %v", err)
+		return nil, fmt.Errorf("db connection error: %v", err)
+	}
+
+	configurePool(db, cfg.Database.Pool)
+
+	if err = ping(ctx, db, cfg.Database.ConnectRetry, log); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("couldn't connect to the DB: %v", err)
+	}
+
+	storage := &Storage{db: db}
+
+	if cfg.Database.AutoMigrate {
+		if err = storage.migrate(); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return storage, nil
+}
+
+func configurePool(db *sql.DB, pool config.Pool) {
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	if pool.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+}
+
+func ping(ctx context.Context, db *sql.DB, policy config.Retry, log *slog.Logger) error {
+	return retry.Do(ctx, policy, func() error {
+		return db.PingContext(ctx)
+	}, func(attempt int, err error, wait time.Duration) {
+		log.Warn("database is not available, retrying",
+			slog.Int("attempt", attempt),
+			slog.Duration("wait", wait),
+			sl.Err(err),
+		)
+	})
+}
+
+func (s *Storage) Migrator() (*migrations.Migrator, error) {
+	return migrations.New(s.db, config.DriverPostgres)
+}
+
+func (s *Storage) migrate() error {
+	migrator, err := s.Migrator()
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %v", err)
+	}
+
+	if _, err = migrator.Up(); err != nil {
+		return fmt.Errorf("failed to apply migrations: %v", err)
+	}
+
+	return nil
+}
+
+// querier — общий интерфейс *sql.DB и *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func (s *Storage) SaveEvent(ctx context.Context, event models.Event) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	eventID, err := saveEventTx(ctx, tx, event)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to save event: %v", err)
+	}
+
+	return eventID, nil
+}
+
+// UpdateEvent меняет только переданные поля. Если передана дата, время события заменяется целиком:
+// без StartsAt событие становится событием на весь день. Без Recurrence правило повторения не меняется.
+// Для серии scope ограничивает изменение одним повторением или повторениями начиная с occurrenceDate;
+// возвращаются ID и новая версия события, в котором оказались изменения. Ненулевая event.Version
+// должна совпадать с текущей версией события. Изменение записывается в историю события.
+// Менять событие может владелец или пользователь, которому календарь события открыт на запись.
+func (s *Storage) UpdateEvent(ctx context.Context, event models.Event, scope models.Scope, occurrenceDate string) (int64, int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	eventID, version, err := updateEventTx(ctx, tx, event, scope, occurrenceDate)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to update event: %v", err)
+	}
+
+	return eventID, version, nil
+}
+
+// DeleteEvent перемещает событие в корзину. Для серии scope ограничивает удаление одним повторением
+// или повторениями начиная с occurrenceDate: такие повторения удаляются сразу, минуя корзину.
+// Ненулевая expectedVersion должна совпадать с текущей версией события. Удаление записывается в историю события.
+// Удалять событие может владелец или пользователь, которому календарь события открыт на запись.
+func (s *Storage) DeleteEvent(ctx context.Context, userID, eventID int64, scope models.Scope, occurrenceDate string, expectedVersion int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err = deleteEventTx(ctx, tx, userID, eventID, scope, occurrenceDate, expectedVersion); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to delete event: %v", err)
+	}
+
+	return nil
+}
+
+// ApplyBatch выполняет операции пакета в одной транзакции и возвращает результат каждой из них.
+// Если continueOnError, каждая операция выполняется в своей точке сохранения и ее ошибка
+// откатывает только ее; иначе первая ошибка откатывает весь пакет, а остальные операции
+// получают ErrBatchAborted. Ошибка возвращается, только если пакет не удалось выполнить целиком.
+func (s *Storage) ApplyBatch(ctx context.Context, ops []models.BatchOperation, continueOnError bool) ([]models.BatchResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	results := make([]models.BatchResult, len(ops))
+	for i, op := range ops {
+		if !continueOnError {
+			if results[i] = applyOperation(ctx, tx, op); results[i].Err != nil {
+				// Операция могла упасть из-за отмены запроса, а не из-за своих данных.
+				if err = ctx.Err(); err != nil {
+					return nil, fmt.Errorf("failed to apply batch: %v", err)
+				}
+				return storage.AbortBatch(results), nil
+			}
+			continue
+		}
+
+		if _, err = tx.ExecContext(ctx, "SAVEPOINT batch_operation"); err != nil {
+			return nil, fmt.Errorf("failed to create savepoint: %v", err)
+		}
+		results[i] = applyOperation(ctx, tx, op)
+		if results[i].Err != nil {
+			_, err = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_operation")
+		}
+		if err == nil {
+			_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT batch_operation")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %v", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to apply batch: %v", err)
+	}
+
+	return results, nil
+}
+
+func (s *Storage) GetEventsByDay(ctx context.Context, userID int64, day string, filter models.EventFilter) ([]models.Event, error) {
+	date, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return nil, storage.InvalidInput("invalid date format: %v", err)
+	}
+
+	return s.eventsBetween(ctx, userID, date, date.AddDate(0, 0, 1), filter)
+}
+
+func (s *Storage) GetEventsByWeek(ctx context.Context, userID int64, startOfWeek time.Time, filter models.EventFilter) ([]models.Event, error) {
+	endOfWeek := startOfWeek.AddDate(0, 0, 7)
+
+	return s.eventsBetween(ctx, userID, startOfWeek, endOfWeek, filter)
+}
+
+func (s *Storage) GetEventsByMonth(ctx context.Context, userID int64, year int, month time.Month, filter models.EventFilter) ([]models.Event, error) {
+	startOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	endOfMonth := startOfMonth.AddDate(0, 1, 0)
+
+	return s.eventsBetween(ctx, userID, startOfMonth, endOfMonth, filter)
+}
+
+// ListEvents возвращает страницу событий и повторений, начинающихся в днях [query.From, query.To].
+// Неповторяющиеся события выбираются по ключу (date, id) с индексом, серии раскрываются
+// в повторения от ключа query.After.
+func (s *Storage) ListEvents(ctx context.Context, userID int64, query models.ListQuery) ([]models.Event, error) {
+	lower := query.From
+	after := models.EventKey{Date: query.From}
+	if query.After != nil {
+		after = *query.After
+		if after.Date > lower {
+			lower = after.Date
+		}
+	}
+
+	grants, err := grantsOf(ctx, s.db, userID, query.Owner)
+	if err != nil {
+		return nil, err
+	}
+	if grants != nil {
+		userID = query.Owner
+		if query.Calendars, err = storage.SharedCalendars(query.Calendars, grants); err != nil {
+			return nil, err
+		}
+	}
+	calendars := calendarArray(query.Calendars)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+eventColumns+` FROM event
+         WHERE user_id = $1 AND deleted_at IS NULL AND rrule = '' AND date >= $2 AND date <= $3 AND (date, id) > ($4, $5)
+           AND (cardinality($7::int[]) = 0 OR calendar_id = ANY($7))
+         ORDER BY date, id
+         LIMIT $6`,
+		userID, lower, query.To, after.Date, after.ID, query.Limit, calendars,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %v", err)
+	}
+	defer rows.Close()
+
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = s.db.QueryContext(ctx,
+		`SELECT `+eventColumns+` FROM event
+         WHERE user_id = $1 AND deleted_at IS NULL AND rrule <> '' AND date <= $3 AND (series_end IS NULL OR series_end >= $2)
+           AND (cardinality($4::int[]) = 0 OR calendar_id = ANY($4))`,
+		userID, lower, query.To, calendars,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event series: %v", err)
+	}
+	defer rows.Close()
+
+	series, err := scanEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, series...)
+
+	// Кроме исключений найденных серий берем измененные повторения, переехавшие в диапазон.
+	rows, err = s.db.QueryContext(ctx,
+		`SELECT x.event_id, x.original_date, x.cancelled, x.date, x.text, x.starts_at, x.ends_at, x.time_zone, e.rrule, e.version, e.calendar_id, e.user_id
+         FROM event_exception x JOIN event e ON e.id = x.event_id
+         WHERE e.user_id = $1 AND e.deleted_at IS NULL AND (cardinality($4::int[]) = 0 OR e.calendar_id = ANY($4)) AND (
+             (e.date <= $3 AND (e.series_end IS NULL OR e.series_end >= $2))
+             OR (NOT x.cancelled AND x.date >= $2 AND x.date <= $3)
+         )`,
+		userID, lower, query.To, calendars,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event exceptions: %v", err)
+	}
+	defer rows.Close()
+
+	exceptions, err := scanExceptions(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = withTags(ctx, s.db, events, exceptions); err != nil {
+		return nil, err
+	}
+	if err = withAttendees(ctx, s.db, events, exceptions); err != nil {
+		return nil, err
+	}
+	if err = withReminders(ctx, s.db, events, exceptions); err != nil {
+		return nil, err
+	}
+
+	page, err := storage.ListPage(events, exceptions, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.Redact(page, grants), nil
+}
+
+// RespondToEvent записывает ответ пользователя на приглашение на событие. Если пользователь
+// не приглашен или событие в корзине, возвращается ErrInvitationNotFound.
+func (s *Storage) RespondToEvent(ctx context.Context, userID, eventID int64, status models.AttendeeStatus) error {
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE event_attendee SET status = $1, responded_at = now()
+         WHERE event_id = $2 AND user_id = $3 AND event_id IN (SELECT id FROM event WHERE deleted_at IS NULL)`,
+		status, eventID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to respond to event: %v", err)
+	}
+
+	responded, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to respond to event: %v", err)
+	}
+	if responded == 0 {
+		return storage.ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+// ListTags возвращает теги пользователя с числом событий, начиная с самых используемых.
+func (s *Storage) ListTags(ctx context.Context, userID int64) ([]models.TagCount, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT t.name, COUNT(*) FROM tag t
+         JOIN event_tag et ON et.tag_id = t.id JOIN event e ON e.id = et.event_id
+         WHERE t.user_id = $1 AND e.deleted_at IS NULL GROUP BY t.name ORDER BY COUNT(*) DESC, t.name`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %v", err)
+	}
+	defer rows.Close()
+
+	var tags []models.TagCount
+	for rows.Next() {
+		var tag models.TagCount
+		if err = rows.Scan(&tag.Name, &tag.Count); err != nil {
+			return nil, fmt.Errorf("failed to list tags: %v", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tags: %v", err)
+	}
+
+	return tags, nil
+}
+
+// SearchEvents ищет события пользователя, текст которых содержит все слова запроса,
+// через индекс text_search и возвращает их по убыванию ts_rank.
+func (s *Storage) SearchEvents(ctx context.Context, userID int64, query models.SearchQuery) ([]models.SearchResult, error) {
+	terms := storage.SearchTerms(query.Text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+eventColumns+`, ts_rank(text_search, q) AS relevance,
+                ts_headline('simple', text, q, 'StartSel=`+storage.HighlightStart+`, StopSel=`+storage.HighlightEnd+`')
+         FROM event, plainto_tsquery('simple', $2) q
+         WHERE user_id = $1 AND deleted_at IS NULL AND text_search @@ q
+           AND ($3::date IS NULL OR series_end IS NULL OR series_end >= $3::date)
+           AND ($4::date IS NULL OR date <= $4::date)
+         ORDER BY relevance DESC, date, id
+         LIMIT $5`,
+		userID, strings.Join(terms, " "),
+		sql.NullString{String: query.From, Valid: query.From != ""},
+		sql.NullString{String: query.To, Valid: query.To != ""},
+		sql.NullInt64{Int64: int64(query.Limit), Valid: query.Limit > 0},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search events: %v", err)
+	}
+	defer rows.Close()
+
+	var results []models.SearchResult
+	var events []models.Event
+	for rows.Next() {
+		var res models.SearchResult
+		var eventDate time.Time
+		var startsAt, endsAt sql.NullTime
+		e := &res.Event
+		err = rows.Scan(&e.ID, &e.UserID, &e.CalendarID, &eventDate, &e.Text, &startsAt, &endsAt, &e.TimeZone, &e.Recurrence,
+			&e.Version, &res.Rank, &res.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search events: %v", err)
+		}
+		e.Date = eventDate.Format("2006-01-02")
+		if startsAt.Valid && endsAt.Valid {
+			e.StartsAt = &startsAt.Time
+			e.EndsAt = &endsAt.Time
+		}
+		results = append(results, res)
+		events = append(events, res.Event)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search events: %v", err)
+	}
+
+	if err = withTags(ctx, s.db, events, nil); err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Event.Tags = events[i].Tags
+	}
+
+	return results, nil
+}
+
+// ListTrash возвращает события пользователя в корзине, начиная с удаленных последними.
+func (s *Storage) ListTrash(ctx context.Context, userID int64) ([]models.Event, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+eventColumns+`, deleted_at FROM event
+         WHERE user_id = $1 AND deleted_at IS NOT NULL
+         ORDER BY deleted_at DESC, id DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash: %v", err)
+	}
+	defer rows.Close()
+
+	var events []models.Event
+	for rows.Next() {
+		var e models.Event
+		var eventDate, deletedAt time.Time
+		var startsAt, endsAt sql.NullTime
+		err = rows.Scan(&e.ID, &e.UserID, &e.CalendarID, &eventDate, &e.Text, &startsAt, &endsAt, &e.TimeZone, &e.Recurrence,
+			&e.Version, &deletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list trash: %v", err)
+		}
+		e.Date = eventDate.Format("2006-01-02")
+		if startsAt.Valid && endsAt.Valid {
+			e.StartsAt = &startsAt.Time
+			e.EndsAt = &endsAt.Time
+		}
+		e.DeletedAt = &deletedAt
+		events = append(events, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list trash: %v", err)
+	}
+
+	if err = withTags(ctx, s.db, events, nil); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// RestoreEvent возвращает событие пользователя из корзины вместе с его тегами и исключениями
+// и записывает восстановление в историю события.
+func (s *Storage) RestoreEvent(ctx context.Context, userID, eventID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	deleted, err := checkEvent(ctx, tx, userID, eventID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return storage.ErrEventNotDeleted
+	}
+
+	var restored models.Event
+	var eventDate time.Time
+	err = tx.QueryRowContext(ctx,
+		`UPDATE event SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL
+         RETURNING date, text`,
+		eventID, userID,
+	).Scan(&eventDate, &restored.Text)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrEventNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore event: %v", err)
+	}
+	restored.Date = eventDate.Format("2006-01-02")
+
+	err = insertRevision(ctx, tx, storage.NewRevision(models.ActionRestore, userID, eventID, "", nil, &restored))
+	if err != nil {
+		return err
+	}
+
+	if err = scheduleReminders(ctx, tx, eventID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to restore event: %v", err)
+	}
+
+	return nil
+}
+
+// EventHistory возвращает историю события пользователя от старых изменений к новым.
+// История доступна и для событий в корзине.
+func (s *Storage) EventHistory(ctx context.Context, userID, eventID int64) ([]models.Revision, error) {
+	if _, err := checkEvent(ctx, s.db, userID, eventID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, event_id, user_id, action, occurrence_date, changed_at, old_date, new_date, old_text, new_text
+         FROM event_revision WHERE event_id = $1 ORDER BY id`,
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event history: %v", err)
+	}
+	defer rows.Close()
+
+	var revisions []models.Revision
+	for rows.Next() {
+		var rev models.Revision
+		var occurrenceDate, oldDate, newDate sql.NullTime
+		var oldText, newText sql.NullString
+		err = rows.Scan(&rev.ID, &rev.EventID, &rev.UserID, &rev.Action, &occurrenceDate, &rev.ChangedAt,
+			&oldDate, &newDate, &oldText, &newText)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get event history: %v", err)
+		}
+		rev.OccurrenceDate = formatDate(occurrenceDate)
+		rev.OldDate = formatDate(oldDate)
+		rev.NewDate = formatDate(newDate)
+		rev.OldText = oldText.String
+		rev.NewText = newText.String
+		revisions = append(revisions, rev)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get event history: %v", err)
+	}
+
+	return revisions, nil
+}
+
+// PurgeTrash окончательно удаляет события, попавшие в корзину раньше before, и возвращает их число.
+func (s *Storage) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM event WHERE deleted_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %v", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %v", err)
+	}
+
+	return purged, nil
+}
+
+// FireReminders забирает до limit напоминаний, которым к моменту now пора сработать, и назначает
+// им следующее срабатывание. Каждое напоминание возвращается ровно один раз: строки, которые уже
+// забрала другая реплика сервиса, пропускаются. Напоминания событий в корзине ждут их восстановления.
+func (s *Storage) FireReminders(ctx context.Context, now time.Time, limit int) ([]models.Notification, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT r.id, r.event_id, r.minutes_before, r.next_fire_at
+         FROM event_reminder r JOIN event e ON e.id = r.event_id
+         WHERE r.next_fire_at <= $1 AND e.deleted_at IS NULL
+         ORDER BY r.next_fire_at, r.id
+         LIMIT $2
+         FOR UPDATE OF r SKIP LOCKED`,
+		now, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due reminders: %v", err)
+	}
+	defer rows.Close()
+
+	type dueReminder struct {
+		id      int64
+		eventID int64
+		before  time.Duration
+		fireAt  time.Time
+	}
+	var due []dueReminder
+	for rows.Next() {
+		var r dueReminder
+		var minutes int64
+		if err = rows.Scan(&r.id, &r.eventID, &minutes, &r.fireAt); err != nil {
+			return nil, fmt.Errorf("failed to get due reminders: %v", err)
+		}
+		r.before = time.Duration(minutes) * time.Minute
+		due = append(due, r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get due reminders: %v", err)
+	}
+
+	var notifications []models.Notification
+	for _, r := range due {
+		series, exceptions, err := loadSeries(ctx, tx, r.eventID)
+		if err != nil {
+			return nil, err
+		}
+
+		occ, fireAt, ok, err := storage.NextReminder(series, exceptions, r.before, r.fireAt.Add(-time.Nanosecond))
+		if err != nil {
+			return nil, err
+		}
+		if ok && fireAt.Equal(r.fireAt) {
+			notifications = append(notifications, storage.NewNotification(r.id, occ, r.before, r.fireAt))
+		}
+
+		_, next, ok, err := storage.NextReminder(series, exceptions, r.before, r.fireAt)
+		if err != nil {
+			return nil, err
+		}
+		_, err = tx.ExecContext(ctx,
+			"UPDATE event_reminder SET next_fire_at = $1 WHERE id = $2", sql.NullTime{Time: next, Valid: ok}, r.id,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to schedule reminder: %v", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to fire reminders: %v", err)
+	}
+
+	return notifications, nil
+}
+
+// CreateUser создает пользователя с профилем user, его календарь по умолчанию и первый ключ доступа key.
+// Занятый email дает ErrEmailTaken.
+func (s *Storage) CreateUser(ctx context.Context, user models.User, key models.APIKey) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO users (name, email, time_zone, locale) VALUES ($1, $2, $3, $4) RETURNING user_id, created_at",
+		user.Name, user.Email, user.TimeZone, user.Locale,
+	).Scan(&user.ID, &user.CreatedAt)
+	if isUniqueViolation(err) {
+		return 0, storage.ErrEmailTaken
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to create user: %v", err)
+	}
+	userID := user.ID
+
+	_, err = insertCalendar(ctx, tx, models.Calendar{
+		UserID:  userID,
+		Name:    models.DefaultCalendarName,
+		Color:   models.DefaultCalendarColor,
+		Default: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err = insertAPIKey(ctx, tx, userID, key); err != nil {
+		return 0, err
+	}
+
+	record, err := storage.UserOutboxRecord(user)
+	if err != nil {
+		return 0, err
+	}
+	if err = insertOutbox(ctx, tx, record); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to create user: %v", err)
+	}
+
+	return userID, nil
+}
+
+// GetUser возвращает профиль пользователя или ErrUserNotFound.
+func (s *Storage) GetUser(ctx context.Context, userID int64) (models.User, error) {
+	user, err := scanUser(s.db.QueryRowContext(ctx,
+		"SELECT "+userColumns+" FROM users WHERE user_id = $1", userID,
+	))
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// UpdateUser меняет непустые поля профиля пользователя user.ID и возвращает профиль после изменения.
+// Занятый email дает ErrEmailTaken.
+func (s *Storage) UpdateUser(ctx context.Context, user models.User) (models.User, error) {
+	updated, err := scanUser(s.db.QueryRowContext(ctx,
+		`UPDATE users SET name = COALESCE(NULLIF($1, ''), name), email = COALESCE(NULLIF($2, ''), email),
+             time_zone = COALESCE(NULLIF($3, ''), time_zone), locale = COALESCE(NULLIF($4, ''), locale)
+         WHERE user_id = $5 RETURNING `+userColumns,
+		user.Name, user.Email, user.TimeZone, user.Locale, user.ID,
+	))
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteUser удаляет пользователя вместе с его событиями, тегами и историей событий. История
+// и вебхуки удаляются вместе с пользователем, поэтому об удалении его событий (кроме тех, что в
+// корзине) и его самого в той же транзакции сообщают записи outbox.
+func (s *Storage) DeleteUser(ctx context.Context, userID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT `+eventColumns+` FROM event WHERE user_id = $1 AND deleted_at IS NULL ORDER BY id`, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get user events: %v", err)
+	}
+	events, err := scanEvents(rows)
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("failed to get user events: %v", err)
+	}
+
+	var deletedAt time.Time
+	if err = tx.QueryRowContext(ctx, "SELECT now()").Scan(&deletedAt); err != nil {
+		return fmt.Errorf("failed to delete user: %v", err)
+	}
+	records, err := storage.UserDeletedOutboxRecords(userID, events, deletedAt)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err = insertOutbox(ctx, tx, record); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM users WHERE user_id = $1", userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %v", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %v", err)
+	}
+	if deleted == 0 {
+		return storage.ErrUserNotFound
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to delete user: %v", err)
+	}
+
+	return nil
+}
+
+// CreateCalendar создает календарь пользователя calendar.UserID и возвращает его ID.
+// Пустой TimeZone заменяется часовым поясом пользователя. Занятое имя дает ErrCalendarExists.
+func (s *Storage) CreateCalendar(ctx context.Context, calendar models.Calendar) (int64, error) {
+	calendar.Default = false
+
+	return insertCalendar(ctx, s.db, calendar)
+}
+
+// GetCalendar возвращает календарь пользователя или открытый ему календарь другого пользователя,
+// нулевой calendarID — календарь по умолчанию. Недоступный или несуществующий календарь дает ErrCalendarNotFound.
+func (s *Storage) GetCalendar(ctx context.Context, userID, calendarID int64) (models.Calendar, error) {
+	return scanCalendar(s.db.QueryRowContext(ctx,
+		`SELECT `+calendarColumns+` FROM calendar
+         WHERE (user_id = $1 AND (id = $2 OR ($2 = 0 AND is_default)))
+            OR (id = $2 AND id IN (SELECT calendar_id FROM calendar_share WHERE user_id = $1))`,
+		userID, calendarID,
+	))
+}
+
+// ListCalendars возвращает календари пользователя от старых к новым.
+func (s *Storage) ListCalendars(ctx context.Context, userID int64) ([]models.Calendar, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+calendarColumns+" FROM calendar WHERE user_id = $1 ORDER BY id", userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendars: %v", err)
+	}
+	defer rows.Close()
+
+	var calendars []models.Calendar
+	for rows.Next() {
+		var c models.Calendar
+		if err = rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Color, &c.TimeZone, &c.Default, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to list calendars: %v", err)
+		}
+		calendars = append(calendars, c)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list calendars: %v", err)
+	}
+
+	return calendars, nil
+}
+
+// UpdateCalendar меняет непустые поля календаря calendar.ID пользователя calendar.UserID
+// и возвращает календарь после изменения. Занятое имя дает ErrCalendarExists.
+func (s *Storage) UpdateCalendar(ctx context.Context, calendar models.Calendar) (models.Calendar, error) {
+	return scanCalendar(s.db.QueryRowContext(ctx,
+		`UPDATE calendar SET name = COALESCE(NULLIF($1, ''), name), color = COALESCE(NULLIF($2, ''), color),
+             time_zone = COALESCE(NULLIF($3, ''), time_zone)
+         WHERE id = $4 AND user_id = $5 RETURNING `+calendarColumns,
+		calendar.Name, calendar.Color, calendar.TimeZone, calendar.ID, calendar.UserID,
+	))
+}
+
+// DeleteCalendar удаляет календарь пользователя. Его события перемещаются в корзину так же, как
+// DeleteEvent (с историей, вебхуками и outbox), и переходят в календарь по умолчанию, откуда их
+// можно восстановить. Календарь по умолчанию удалить нельзя: ErrDefaultCalendar.
+func (s *Storage) DeleteCalendar(ctx context.Context, userID, calendarID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var isDefault bool
+	err = tx.QueryRowContext(ctx,
+		"SELECT is_default FROM calendar WHERE id = $1 AND user_id = $2 FOR UPDATE", calendarID, userID,
+	).Scan(&isDefault)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrCalendarNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar: %v", err)
+	}
+	if isDefault {
+		return storage.ErrDefaultCalendar
+	}
+
+	eventIDs, err := calendarEventIDs(ctx, tx, calendarID)
+	if err != nil {
+		return err
+	}
+	for _, eventID := range eventIDs {
+		if err = deleteEventTx(ctx, tx, userID, eventID, models.ScopeAll, "", 0); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE event SET calendar_id = (SELECT id FROM calendar WHERE user_id = $1 AND is_default) WHERE calendar_id = $2",
+		userID, calendarID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar: %v", err)
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM calendar WHERE id = $1", calendarID); err != nil {
+		return fmt.Errorf("failed to delete calendar: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to delete calendar: %v", err)
+	}
+
+	return nil
+}
+
+// calendarEventIDs возвращает события календаря calendarID, которых нет в корзине.
+func calendarEventIDs(ctx context.Context, q querier, calendarID int64) ([]int64, error) {
+	rows, err := q.QueryContext(ctx,
+		"SELECT id FROM event WHERE calendar_id = $1 AND deleted_at IS NULL ORDER BY id", calendarID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar events: %v", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan event id: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get calendar events: %v", err)
+	}
+
+	return ids, nil
+}
+
+// ShareCalendar открывает календарь share.CalendarID владельца share.OwnerID пользователю share.UserID
+// с доступом share.Access. Повторная выдача заменяет доступ.
+func (s *Storage) ShareCalendar(ctx context.Context, share models.Share) error {
+	if share.UserID == share.OwnerID {
+		return storage.ErrShareOwner
+	}
+
+	var exists bool
+	err := s.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM calendar WHERE id = $1 AND user_id = $2)", share.CalendarID, share.OwnerID,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check calendar: %v", err)
+	}
+	if !exists {
+		return storage.ErrCalendarNotFound
+	}
+
+	err = s.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)", share.UserID,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check user: %v", err)
+	}
+	if !exists {
+		return storage.ErrUserNotFound
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO calendar_share (calendar_id, user_id, access) VALUES ($1, $2, $3)
+         ON CONFLICT (calendar_id, user_id) DO UPDATE SET access = EXCLUDED.access`,
+		share.CalendarID, share.UserID, share.Access,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to share calendar: %v", err)
+	}
+
+	return nil
+}
+
+// ListShares возвращает доступы, выданные к календарям владельца ownerID, по календарям и пользователям.
+func (s *Storage) ListShares(ctx context.Context, ownerID int64) ([]models.Share, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT s.calendar_id, c.user_id, s.user_id, s.access, s.created_at
+         FROM calendar_share s JOIN calendar c ON c.id = s.calendar_id
+         WHERE c.user_id = $1 ORDER BY s.calendar_id, s.user_id`,
+		ownerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shares: %v", err)
+	}
+	defer rows.Close()
+
+	var shares []models.Share
+	for rows.Next() {
+		var share models.Share
+		err = rows.Scan(&share.CalendarID, &share.OwnerID, &share.UserID, &share.Access, &share.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list shares: %v", err)
+		}
+		shares = append(shares, share)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list shares: %v", err)
+	}
+
+	return shares, nil
+}
+
+// RevokeShare закрывает пользователю userID доступ к календарю calendarID владельца ownerID.
+func (s *Storage) RevokeShare(ctx context.Context, ownerID, calendarID, userID int64) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM calendar_share
+         WHERE calendar_id = $1 AND user_id = $2 AND calendar_id IN (SELECT id FROM calendar WHERE user_id = $3)`,
+		calendarID, userID, ownerID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke share: %v", err)
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke share: %v", err)
+	}
+	if revoked == 0 {
+		return storage.ErrShareNotFound
+	}
+
+	return nil
+}
+
+// CreateAPIKey выдает пользователю новый ключ доступа key и возвращает ID ключа.
+func (s *Storage) CreateAPIKey(ctx context.Context, userID int64, key models.APIKey) (int64, error) {
+	return insertAPIKey(ctx, s.db, userID, key)
+}
+
+// ListAPIKeys возвращает ключи пользователя, включая отозванные, от старых к новым.
+func (s *Storage) ListAPIKeys(ctx context.Context, userID int64) ([]models.APIKey, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, user_id, prefix, created_at, revoked_at FROM api_key WHERE user_id = $1 ORDER BY id",
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %v", err)
+	}
+	defer rows.Close()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		var key models.APIKey
+		var revokedAt sql.NullTime
+		if err = rows.Scan(&key.ID, &key.UserID, &key.Prefix, &key.CreatedAt, &revokedAt); err != nil {
+			return nil, fmt.Errorf("failed to list api keys: %v", err)
+		}
+		if revokedAt.Valid {
+			key.RevokedAt = &revokedAt.Time
+		}
+		keys = append(keys, key)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %v", err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey отзывает ключ пользователя. Повторный отзыв ничего не меняет.
+func (s *Storage) RevokeAPIKey(ctx context.Context, userID, keyID int64) error {
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE api_key SET revoked_at = COALESCE(revoked_at, now()) WHERE id = $1 AND user_id = $2",
+		keyID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %v", err)
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %v", err)
+	}
+	if revoked == 0 {
+		return storage.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// UserByAPIKey возвращает владельца действующего ключа с хешем hash.
+// Неизвестный или отозванный ключ дает ErrAPIKeyNotFound.
+func (s *Storage) UserByAPIKey(ctx context.Context, hash string) (int64, error) {
+	var userID int64
+	err := s.db.QueryRowContext(ctx,
+		"SELECT user_id FROM api_key WHERE key_hash = $1 AND revoked_at IS NULL", hash,
+	).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to check api key: %v", err)
+	}
+
+	return userID, nil
+}
+
+// CreateRefreshToken сохраняет токен обновления token, выданный в обмен на действующий ключ API
+// с хешем keyHash, и возвращает владельца ключа. Неизвестный или отозванный ключ дает ErrAPIKeyNotFound.
+func (s *Storage) CreateRefreshToken(ctx context.Context, keyHash string, token models.RefreshToken) (int64, error) {
+	var userID int64
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO refresh_token (user_id, api_key_id, token_hash, expires_at)
+         SELECT user_id, id, $2, $3 FROM api_key WHERE key_hash = $1 AND revoked_at IS NULL
+         RETURNING user_id`,
+		keyHash, token.Hash, token.ExpiresAt.UTC(),
+	).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to save refresh token: %v", err)
+	}
+
+	return userID, nil
+}
+
+// RotateRefreshToken обменивает действующий токен обновления с хешем hash на токен next того же
+// пользователя и ключа API и возвращает ID пользователя. Старый токен удаляется, поэтому повторно
+// его не использовать; заодно удаляются истекшие токены пользователя. Истекший, уже использованный
+// токен или токен отозванного ключа дает ErrRefreshTokenNotFound.
+func (s *Storage) RotateRefreshToken(ctx context.Context, hash string, next models.RefreshToken) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+
+	var userID, keyID int64
+	err = tx.QueryRowContext(ctx,
+		`DELETE FROM refresh_token WHERE token_hash = $1 AND expires_at > $2
+             AND api_key_id IN (SELECT id FROM api_key WHERE revoked_at IS NULL)
+         RETURNING user_id, api_key_id`,
+		hash, now,
+	).Scan(&userID, &keyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to rotate refresh token: %v", err)
+	}
+
+	if _, err = tx.ExecContext(ctx,
+		"DELETE FROM refresh_token WHERE user_id = $1 AND expires_at <= $2", userID, now,
+	); err != nil {
+		return 0, fmt.Errorf("failed to rotate refresh token: %v", err)
+	}
+
+	if _, err = tx.ExecContext(ctx,
+		"INSERT INTO refresh_token (user_id, api_key_id, token_hash, expires_at) VALUES ($1, $2, $3, $4)",
+		userID, keyID, next.Hash, next.ExpiresAt.UTC(),
+	); err != nil {
+		return 0, fmt.Errorf("failed to rotate refresh token: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to rotate refresh token: %v", err)
+	}
+
+	return userID, nil
+}
+
+// CreateWebhook создает активный вебхук пользователя webhook.UserID и возвращает его ID.
+func (s *Storage) CreateWebhook(ctx context.Context, webhook models.Webhook) (int64, error) {
+	var webhookID int64
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO webhook (user_id, url, secret, event_types)
+         SELECT user_id, $1, $2, $3 FROM users WHERE user_id = $4
+         RETURNING id`,
+		webhook.URL, webhook.Secret, eventTypeArray(webhook.EventTypes), webhook.UserID,
+	).Scan(&webhookID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrUserNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to save webhook: %v", err)
+	}
+
+	return webhookID, nil
+}
+
+// ListWebhooks возвращает вебхуки пользователя, включая выключенные, от старых к новым.
+func (s *Storage) ListWebhooks(ctx context.Context, userID int64) ([]models.Webhook, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+webhookColumns+" FROM webhook WHERE user_id = $1 ORDER BY id", userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %v", err)
+	}
+	defer rows.Close()
+
+	var webhooks []models.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %v", err)
+	}
+
+	return webhooks, nil
+}
+
+// UpdateWebhook меняет вебхук пользователя по patch и возвращает его после изменения.
+func (s *Storage) UpdateWebhook(ctx context.Context, patch models.WebhookPatch) (models.Webhook, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Webhook{}, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	webhook, err := scanWebhook(tx.QueryRowContext(ctx,
+		"SELECT "+webhookColumns+" FROM webhook WHERE id = $1 AND user_id = $2 FOR UPDATE", patch.ID, patch.UserID,
+	).Scan)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	webhook = webhook.Updated(patch, time.Now().UTC())
+	_, err = tx.ExecContext(ctx,
+		`UPDATE webhook SET url = $1, secret = $2, event_types = $3, active = $4, failures = $5, disabled_at = $6
+         WHERE id = $7`,
+		webhook.URL, webhook.Secret, eventTypeArray(webhook.EventTypes), webhook.Active, webhook.Failures,
+		webhook.DisabledAt, webhook.ID,
+	)
+	if err != nil {
+		return models.Webhook{}, fmt.Errorf("failed to update webhook: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return models.Webhook{}, fmt.Errorf("failed to update webhook: %v", err)
+	}
+
+	return webhook, nil
+}
+
+// DeleteWebhook удаляет вебхук пользователя вместе с его доставками.
+func (s *Storage) DeleteWebhook(ctx context.Context, userID, webhookID int64) error {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM webhook WHERE id = $1 AND user_id = $2", webhookID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %v", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %v", err)
+	}
+	if deleted == 0 {
+		return storage.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// ListDeliveries возвращает до limit последних доставок вебхука пользователя, от новых к старым,
+// вместе с их попытками.
+func (s *Storage) ListDeliveries(ctx context.Context, userID, webhookID int64, limit int) ([]models.Delivery, error) {
+	var exists bool
+	err := s.db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM webhook WHERE id = $1 AND user_id = $2)", webhookID, userID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %v", err)
+	}
+	if !exists {
+		return nil, storage.ErrWebhookNotFound
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, webhook_id, event_type, payload, status, attempts, next_attempt_at, created_at
+         FROM webhook_delivery WHERE webhook_id = $1 ORDER BY id DESC LIMIT $2`,
+		webhookID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %v", err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.Delivery
+	for rows.Next() {
+		var d models.Delivery
+		var payload, status string
+		var nextAttemptAt sql.NullTime
+		err = rows.Scan(&d.ID, &d.WebhookID, &d.EventType, &payload, &status, &d.Attempts, &nextAttemptAt, &d.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deliveries: %v", err)
+		}
+		d.Payload = []byte(payload)
+		d.Status = models.DeliveryStatus(status)
+		if nextAttemptAt.Valid {
+			d.NextAttemptAt = &nextAttemptAt.Time
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %v", err)
+	}
+
+	return withAttempts(ctx, s.db, deliveries)
+}
+
+// ClaimDeliveries забирает на отправку до limit доставок активных вебхуков, которым к моменту now
+// пора уйти, и откладывает их следующую попытку до now+lease: если отправивший их процесс упадет,
+// не записав попытку, доставки вернутся в очередь. Доставки, забранные другой репликой, пропускаются.
+func (s *Storage) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.Delivery, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`UPDATE webhook_delivery d SET next_attempt_at = $2
+         FROM webhook w
+         WHERE w.id = d.webhook_id AND d.id IN (
+             SELECT d.id FROM webhook_delivery d JOIN webhook w ON w.id = d.webhook_id
+             WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND w.active
+             ORDER BY d.next_attempt_at, d.id
+             LIMIT $3
+             FOR UPDATE OF d SKIP LOCKED
+         )
+         RETURNING d.id, d.webhook_id, d.event_type, d.payload, d.attempts, d.created_at, w.url, w.secret`,
+		now, now.Add(lease), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim deliveries: %v", err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.Delivery
+	for rows.Next() {
+		d := models.Delivery{Status: models.DeliveryPending}
+		var payload string
+		err = rows.Scan(&d.ID, &d.WebhookID, &d.EventType, &payload, &d.Attempts, &d.CreatedAt, &d.URL, &d.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim deliveries: %v", err)
+		}
+		d.Payload = []byte(payload)
+		deliveries = append(deliveries, d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to claim deliveries: %v", err)
+	}
+
+	// RETURNING не сохраняет порядок подзапроса.
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID < deliveries[j].ID })
+
+	return deliveries, nil
+}
+
+// RecordAttempt записывает попытку доставки и ее исход: retryAt — время следующей попытки
+// неудачной доставки, nil отмечает доставку проваленной. Удачная попытка обнуляет счетчик
+// неудач вебхука, неудачная увеличивает его, и после disableAfter неудач подряд вебхук
+// выключается (нулевой disableAfter не выключает вебхуки). Возвращает true, если эта попытка
+// выключила вебхук. Доставки удаленного вебхука не записываются.
+func (s *Storage) RecordAttempt(ctx context.Context, attempt models.DeliveryAttempt, retryAt *time.Time, disableAfter int) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var webhookID int64
+	err = tx.QueryRowContext(ctx,
+		`UPDATE webhook_delivery SET status = $1, attempts = attempts + 1, next_attempt_at = $2
+         WHERE id = $3 RETURNING webhook_id`,
+		string(storage.AttemptOutcome(attempt, retryAt)), retryAt, attempt.DeliveryID,
+	).Scan(&webhookID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to record delivery attempt: %v", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO webhook_attempt (delivery_id, attempted_at, status_code, error, duration_ms)
+         VALUES ($1, $2, $3, $4, $5)`,
+		attempt.DeliveryID, attempt.AttemptedAt.UTC(), sql.NullInt64{Int64: int64(attempt.StatusCode), Valid: attempt.StatusCode != 0},
+		attempt.Error, attempt.Duration.Milliseconds(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to record delivery attempt: %v", err)
+	}
+
+	var disabled bool
+	if attempt.OK() {
+		_, err = tx.ExecContext(ctx, "UPDATE webhook SET failures = 0 WHERE id = $1", webhookID)
+	} else {
+		err = tx.QueryRowContext(ctx,
+			`UPDATE webhook SET failures = failures + 1,
+                 active = active AND NOT ($2 > 0 AND failures + 1 >= $2),
+                 disabled_at = CASE WHEN active AND $2 > 0 AND failures + 1 >= $2 THEN $3 ELSE disabled_at END
+             WHERE id = $1
+             RETURNING $2 > 0 AND NOT active AND failures = $2`,
+			webhookID, disableAfter, attempt.AttemptedAt.UTC(),
+		).Scan(&disabled)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to record delivery attempt: %v", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to record delivery attempt: %v", err)
+	}
+
+	return disabled, nil
+}
+
+// RelayOutbox передает publish до limit неотправленных записей outbox по порядку id и отмечает
+// отправленными те, что publish принял; на первой ошибке публикация останавливается, чтобы
+// не нарушить порядок, и ошибка возвращается вместе с числом отправленных записей. Запись,
+// опубликованная перед сбоем отметки, будет опубликована повторно. Релей работает в одной
+// реплике за раз: если outbox уже разбирает другая, RelayOutbox ничего не делает.
+//
+// Id выдаются до фиксации, поэтому запись с меньшим id может появиться позже записи с большим.
+// Релей не ждет пишущие транзакции и не блокирует их: он останавливается на первой записи, чей
+// tx_horizon (см. insertOutbox) не меньше xmin текущего снимка, — пока такие транзакции не
+// завершены, перед ней еще может появиться запись с меньшим id.
+func (s *Storage) RelayOutbox(ctx context.Context, limit int, publish func(models.OutboxRecord) error) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err = tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", outboxRelayLockKey).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to acquire outbox relay lock: %v", err)
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, aggregate_type, aggregate_id, type, payload, created_at,
+             tx_horizon <= txid_snapshot_xmin(txid_current_snapshot())
+         FROM outbox WHERE sent_at IS NULL ORDER BY id LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get outbox records: %v", err)
+	}
+	defer rows.Close()
+
+	var records []models.OutboxRecord
+	for rows.Next() {
+		var record models.OutboxRecord
+		var payload string
+		var settled bool
+		err = rows.Scan(&record.ID, &record.AggregateType, &record.AggregateID, &record.Type, &payload, &record.CreatedAt, &settled)
+		if err != nil {
+			return 0, fmt.Errorf("failed to scan outbox record: %v", err)
+		}
+		if !settled {
+			break
+		}
+		record.Payload = []byte(payload)
+		records = append(records, record)
+	}
+	if err = rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to get outbox records: %v", err)
+	}
+	rows.Close()
+
+	sent := make([]int64, 0, len(records))
+	var publishErr error
+	for _, record := range records {
+		if publishErr = publish(record); publishErr != nil {
+			break
+		}
+		sent = append(sent, record.ID)
+	}
+	if len(sent) == 0 {
+		return 0, publishErr
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE outbox SET sent_at = now() WHERE id = ANY($1)", pq.Array(sent)); err != nil {
+		return 0, fmt.Errorf("failed to mark outbox records sent: %v", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to mark outbox records sent: %v", err)
+	}
+
+	return len(sent), publishErr
+}
+
+func (s *Storage) Close() error {
+	err := s.db.Close()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// saveEventTx выполняет SaveEvent в транзакции tx.
+func saveEventTx(ctx context.Context, tx *sql.Tx, event models.Event) (int64, error) {
+	actor := event.UserID
+	calendarID, owner, err := writableCalendar(ctx, tx, actor, event.CalendarID)
+	if err != nil {
+		return 0, err
+	}
+	event.CalendarID = calendarID
+	event.UserID = owner
+
+	eventID, err := insertEvent(ctx, tx, event)
+	if err != nil {
+		return 0, err
+	}
+
+	err = insertRevision(ctx, tx, storage.NewRevision(models.ActionCreate, actor, eventID, "", nil, &event))
+	if err != nil {
+		return 0, err
+	}
+
+	if err = scheduleReminders(ctx, tx, eventID); err != nil {
+		return 0, err
+	}
+
+	return eventID, nil
+}
+
+// updateEventTx выполняет UpdateEvent в транзакции tx.
+func updateEventTx(ctx context.Context, tx *sql.Tx, event models.Event, scope models.Scope, occurrenceDate string) (int64, int64, error) {
+	series, err := getEventForUpdate(ctx, tx, event.UserID, event.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err = storage.CheckVersion(*series, event.Version); err != nil {
+		return 0, 0, err
+	}
+	// Событие открытого на запись календаря меняет не владелец: в истории остается он сам.
+	actor := event.UserID
+	event.UserID = series.UserID
+
+	before, occurrence, err := changedPart(ctx, tx, *series, scope, occurrenceDate)
+	if err != nil {
+		return 0, 0, err
+	}
+	if event.CalendarID != 0 {
+		if scope == models.ScopeThis && occurrence != "" {
+			return 0, 0, storage.ErrOccurrenceCalendar
+		}
+		_, owner, err := writableCalendar(ctx, tx, actor, event.CalendarID)
+		if err == nil && owner != series.UserID {
+			err = storage.ErrCalendarNotFound
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	eventID := event.ID
+	switch {
+	case occurrence == "":
+		if event.Recurrence == "" {
+			event.Recurrence = series.Recurrence
+		}
+		if err = updateEvent(ctx, tx, event); err == nil {
+			err = dropStaleExceptions(ctx, tx, event.ID)
+		}
+	case scope == models.ScopeThis:
+		err = setException(ctx, tx, event.ID, occurrenceDate, false, event)
+	case scope == models.ScopeFollowing:
+		var head, tail string
+		head, tail, err = storage.SplitSeries(*series, occurrenceDate)
+		if err != nil {
+			break
+		}
+		if event.Recurrence == "" {
+			event.Recurrence = tail
+		}
+		if event.Tags == nil {
+			event.Tags = series.Tags
+		}
+		if event.Attendees == nil {
+			event.Attendees = series.Attendees
+		}
+		if event.Reminders == nil {
+			event.Reminders = series.Reminders
+		}
+		if event.CalendarID == 0 {
+			event.CalendarID = series.CalendarID
+		}
+		if head == "" {
+			if err = updateEvent(ctx, tx, event); err == nil {
+				err = dropStaleExceptions(ctx, tx, event.ID)
+			}
+			break
+		}
+		if err = truncateSeries(ctx, tx, *series, head, occurrenceDate); err == nil {
+			eventID, err = insertEvent(ctx, tx, event)
+		}
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	after := storage.Updated(before, event)
+	err = insertRevision(ctx, tx, storage.NewRevision(models.ActionUpdate, actor, event.ID, occurrence, &before, &after))
+	if err == nil && eventID != event.ID {
+		err = insertRevision(ctx, tx, storage.NewRevision(models.ActionCreate, actor, eventID, "", nil, &event))
+	}
+	if err == nil {
+		err = scheduleReminders(ctx, tx, event.ID)
+	}
+	if err == nil && eventID != event.ID {
+		err = scheduleReminders(ctx, tx, eventID)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	version, err := bumpVersion(ctx, tx, event.ID)
+	if err != nil {
+		return 0, 0, err
+	}
+	if eventID != event.ID {
+		version = models.InitialVersion
+	}
+
+	return eventID, version, nil
+}
+
+// deleteEventTx выполняет DeleteEvent в транзакции tx.
+func deleteEventTx(ctx context.Context, tx *sql.Tx, userID, eventID int64, scope models.Scope, occurrenceDate string, expectedVersion int64) error {
+	series, err := getEventForUpdate(ctx, tx, userID, eventID)
+	if err != nil {
+		return err
+	}
+	if err = storage.CheckVersion(*series, expectedVersion); err != nil {
+		return err
+	}
+
+	before, occurrence, err := changedPart(ctx, tx, *series, scope, occurrenceDate)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case occurrence == "":
+		err = trashEvent(ctx, tx, series.UserID, eventID)
+	case scope == models.ScopeThis:
+		err = setException(ctx, tx, eventID, occurrenceDate, true, models.Event{})
+	case scope == models.ScopeFollowing:
+		var head string
+		head, _, err = storage.SplitSeries(*series, occurrenceDate)
+		if err != nil {
+			break
+		}
+		if head == "" {
+			err = trashEvent(ctx, tx, series.UserID, eventID)
+			break
+		}
+		err = truncateSeries(ctx, tx, *series, head, occurrenceDate)
+	}
+	if err == nil {
+		err = insertRevision(ctx, tx, storage.NewRevision(models.ActionDelete, userID, eventID, occurrence, &before, nil))
+	}
+	if err == nil {
+		err = scheduleReminders(ctx, tx, eventID)
+	}
+	if err == nil {
+		_, err = bumpVersion(ctx, tx, eventID)
+	}
+
+	return err
+}
+
+// applyOperation выполняет операцию пакета в транзакции tx.
+func applyOperation(ctx context.Context, tx *sql.Tx, op models.BatchOperation) models.BatchResult {
+	var res models.BatchResult
+	switch op.Action {
+	case models.ActionCreate:
+		res.EventID, res.Err = saveEventTx(ctx, tx, op.Event)
+		res.Version = models.InitialVersion
+	case models.ActionUpdate:
+		res.EventID, res.Version, res.Err = updateEventTx(ctx, tx, op.Event, op.Scope, op.OccurrenceDate)
+	case models.ActionDelete:
+		res.EventID = op.Event.ID
+		res.Err = deleteEventTx(ctx, tx, op.Event.UserID, op.Event.ID, op.Scope, op.OccurrenceDate, op.Event.Version)
+	default:
+		res.Err = storage.InvalidInput("unsupported batch action %q", op.Action)
+	}
+
+	return res
+}
+
+func insertEvent(ctx context.Context, q querier, event models.Event) (int64, error) {
+	date, err := time.Parse("2006-01-02", event.Date)
+	if err != nil {
+		return 0, storage.InvalidInput("invalid date format: %v", err)
+	}
+	_, endDate := event.Days()
+	seriesEnd, err := storage.SeriesEnd(event)
+	if err != nil {
+		return 0, err
+	}
+
+	var eventID int64
+	err = q.QueryRowContext(ctx,
+		`INSERT INTO event (user_id, calendar_id, date, end_date, text, starts_at, ends_at, time_zone, rrule, series_end) 
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`,
+		event.UserID, event.CalendarID, date, endDate, event.Text, event.StartsAt, event.EndsAt, event.TimeZone,
+		event.Recurrence, sql.NullString{String: seriesEnd, Valid: seriesEnd != ""},
+	).Scan(&eventID)
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to save event: %v", err)
+	}
+
+	if len(event.Tags) > 0 {
+		if err = setTags(ctx, q, event.UserID, eventID, event.Tags); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(event.Attendees) > 0 {
+		if err = setAttendees(ctx, q, event.UserID, eventID, event.Attendees); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(event.Reminders) > 0 {
+		if err = setReminders(ctx, q, eventID, event.Reminders); err != nil {
+			return 0, err
+		}
+	}
+
+	return eventID, nil
+}
+
+func updateEvent(ctx context.Context, q querier, event models.Event) error {
+	query := "UPDATE event SET"
+	args := []interface{}{}
+	argPos := 1
+
+	if event.Date != "" {
+		date, err := time.Parse("2006-01-02", event.Date)
+		if err != nil {
+			return storage.InvalidInput("invalid date format: %v", err)
+		}
+		_, endDate := event.Days()
+		seriesEnd, err := storage.SeriesEnd(event)
+		if err != nil {
+			return err
+		}
+		query += fmt.Sprintf(
+			" date = $%d, end_date = $%d, starts_at = $%d, ends_at = $%d, time_zone = $%d, rrule = $%d, series_end = $%d,",
+			argPos, argPos+1, argPos+2, argPos+3, argPos+4, argPos+5, argPos+6)
+		args = append(args, date, endDate, event.StartsAt, event.EndsAt, event.TimeZone,
+			event.Recurrence, sql.NullString{String: seriesEnd, Valid: seriesEnd != ""})
+		argPos += 7
+	}
+
+	if event.Text != "" {
+		query += fmt.Sprintf(" text = $%d,", argPos)
+		args = append(args, event.Text)
+		argPos++
+	}
+
+	if event.CalendarID != 0 {
+		query += fmt.Sprintf(" calendar_id = $%d,", argPos)
+		args = append(args, event.CalendarID)
+		argPos++
+	}
+
+	if event.Tags != nil {
+		if err := setTags(ctx, q, event.UserID, event.ID, event.Tags); err != nil {
+			return err
+		}
+	}
+
+	if event.Attendees != nil {
+		if err := setAttendees(ctx, q, event.UserID, event.ID, event.Attendees); err != nil {
+			return err
+		}
+	}
+
+	if event.Reminders != nil {
+		if err := setReminders(ctx, q, event.ID, event.Reminders); err != nil {
+			return err
+		}
+	}
+
+	if len(args) == 0 {
+		return nil
+	}
+
+	query = strings.TrimSuffix(query, ",")
+
+	query += fmt.Sprintf(" WHERE id = $%d AND user_id = $%d", argPos, argPos+1)
+	args = append(args, event.ID, event.UserID)
+
+	result, err := q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update event: %v", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return storage.ErrEventNotFound
+	}
+
+	return nil
+}
+
+// trashEvent перемещает событие пользователя в корзину.
+func trashEvent(ctx context.Context, q querier, userID, eventID int64) error {
+	_, err := q.ExecContext(ctx,
+		"UPDATE event SET deleted_at = now() WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL",
+		eventID,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete event: %v", err)
+	}
+
+	return nil
+}
+
+// getEventForUpdate блокирует событие, которое пользователь может менять, до конца транзакции
+// и возвращает его. Если события нет или оно в корзине, возвращается ErrEventNotFound, если оно
+// принадлежит другому пользователю, не открывшему календарь события на запись, — ErrEventForbidden.
+func getEventForUpdate(ctx context.Context, tx *sql.Tx, userID, eventID int64) (*models.Event, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT `+eventColumns+` FROM event WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`,
+		eventID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %v", err)
+	}
+	defer rows.Close()
+
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %v", err)
+	}
+	if len(events) == 0 {
+		return nil, storage.ErrEventNotFound
+	}
+	if err = checkWrite(ctx, tx, events[0], userID); err != nil {
+		return nil, err
+	}
+
+	tags, err := loadTags(ctx, tx, []int64{eventID})
+	if err != nil {
+		return nil, err
+	}
+	events[0].Tags = tags[eventID]
+
+	attendees, err := loadAttendees(ctx, tx, []int64{eventID})
+	if err != nil {
+		return nil, err
+	}
+	events[0].Attendees = attendees[eventID]
+
+	reminders, err := loadReminders(ctx, tx, []int64{eventID})
+	if err != nil {
 		return nil, err
 	}
+	events[0].Reminders = reminders[eventID]
+
+	return &events[0], nil
+}
+
+// checkEvent проверяет, что событие есть и принадлежит пользователю, и сообщает, в корзине ли оно.
+// Возвращает ErrEventNotFound или ErrEventForbidden.
+func checkEvent(ctx context.Context, q querier, userID, eventID int64) (bool, error) {
+	var owner int64
+	var deleted bool
+	err := q.QueryRowContext(ctx,
+		"SELECT user_id, deleted_at IS NOT NULL FROM event WHERE id = $1", eventID,
+	).Scan(&owner, &deleted)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, storage.ErrEventNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check event: %v", err)
+	}
+	if owner != userID {
+		return false, storage.ErrEventForbidden
+	}
+
+	return deleted, nil
+}
+
+// insertAPIKey сохраняет ключ пользователя и возвращает его ID.
+func insertAPIKey(ctx context.Context, q querier, userID int64, key models.APIKey) (int64, error) {
+	var keyID int64
+	err := q.QueryRowContext(ctx,
+		"INSERT INTO api_key (user_id, key_hash, prefix) VALUES ($1, $2, $3) RETURNING id",
+		userID, key.Hash, key.Prefix,
+	).Scan(&keyID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save api key: %v", err)
+	}
+
+	return keyID, nil
+}
+
+// insertCalendar сохраняет календарь пользователя и возвращает его ID. Пустой TimeZone заменяется
+// часовым поясом пользователя.
+func insertCalendar(ctx context.Context, q querier, calendar models.Calendar) (int64, error) {
+	var calendarID int64
+	err := q.QueryRowContext(ctx,
+		`INSERT INTO calendar (user_id, name, color, time_zone, is_default)
+         SELECT user_id, $2, $3, COALESCE(NULLIF($4, ''), time_zone), $5 FROM users WHERE user_id = $1
+         RETURNING id`,
+		calendar.UserID, calendar.Name, calendar.Color, calendar.TimeZone, calendar.Default,
+	).Scan(&calendarID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrUserNotFound
+	}
+	if isUniqueViolation(err) {
+		return 0, storage.ErrCalendarExists
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to save calendar: %v", err)
+	}
+
+	return calendarID, nil
+}
+
+// writableCalendar проверяет, что пользователь может добавлять события в календарь calendarID:
+// свой или открытый ему на запись, и возвращает ID календаря и его владельца; нулевой calendarID —
+// календарь пользователя по умолчанию. Недоступный или несуществующий календарь дает ErrCalendarNotFound,
+// несуществующий пользователь — ErrUserNotFound.
+func writableCalendar(ctx context.Context, q querier, userID, calendarID int64) (int64, int64, error) {
+	var id, owner int64
+	err := q.QueryRowContext(ctx,
+		`SELECT c.id, c.user_id FROM calendar c
+         WHERE (c.user_id = $1 AND (c.id = $2 OR ($2 = 0 AND c.is_default)))
+            OR (c.id = $2 AND EXISTS (
+                SELECT 1 FROM calendar_share s WHERE s.calendar_id = c.id AND s.user_id = $1 AND s.access = $3
+            ))`,
+		userID, calendarID, models.AccessWrite,
+	).Scan(&id, &owner)
+	if errors.Is(err, sql.ErrNoRows) && calendarID == 0 {
+		return 0, 0, storage.ErrUserNotFound
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, storage.ErrCalendarNotFound
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to check calendar: %v", err)
+	}
+
+	return id, owner, nil
+}
+
+// checkWrite возвращает ErrEventForbidden, если событие e принадлежит другому пользователю
+// и он не открыл пользователю userID календарь события на запись.
+func checkWrite(ctx context.Context, q querier, e models.Event, userID int64) error {
+	if e.UserID == userID {
+		return nil
+	}
+
+	var access string
+	err := q.QueryRowContext(ctx,
+		"SELECT access FROM calendar_share WHERE calendar_id = $1 AND user_id = $2", e.CalendarID, userID,
+	).Scan(&access)
+	if errors.Is(err, sql.ErrNoRows) {
+		return storage.ErrEventForbidden
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check access: %v", err)
+	}
+	if !models.Access(access).Allows(models.AccessWrite) {
+		return storage.ErrEventForbidden
+	}
+
+	return nil
+}
+
+// grantsOf возвращает доступ пользователя к календарям владельца owner. Для своих событий
+// (owner 0 или userID) возвращается nil, для чужих — непустой map или пустой, если доступа нет.
+func grantsOf(ctx context.Context, q querier, userID, owner int64) (map[int64]models.Access, error) {
+	if owner == 0 || owner == userID {
+		return nil, nil
+	}
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT s.calendar_id, s.access FROM calendar_share s JOIN calendar c ON c.id = s.calendar_id
+         WHERE c.user_id = $1 AND s.user_id = $2`,
+		owner, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar shares: %v", err)
+	}
+	defer rows.Close()
+
+	grants := make(map[int64]models.Access)
+	for rows.Next() {
+		var calendarID int64
+		var access string
+		if err = rows.Scan(&calendarID, &access); err != nil {
+			return nil, fmt.Errorf("failed to get calendar shares: %v", err)
+		}
+		grants[calendarID] = models.Access(access)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get calendar shares: %v", err)
+	}
+
+	return grants, nil
+}
+
+const webhookColumns = "id, user_id, url, secret, event_types, active, failures, created_at, disabled_at"
+
+// scanWebhook читает вебхук из строки с колонками webhookColumns. Если строки нет,
+// возвращается ErrWebhookNotFound.
+func scanWebhook(scan func(dest ...interface{}) error) (models.Webhook, error) {
+	var w models.Webhook
+	var eventTypes []string
+	var disabledAt sql.NullTime
+	err := scan(&w.ID, &w.UserID, &w.URL, &w.Secret, pq.Array(&eventTypes), &w.Active, &w.Failures, &w.CreatedAt, &disabledAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Webhook{}, storage.ErrWebhookNotFound
+	}
+	if err != nil {
+		return models.Webhook{}, fmt.Errorf("failed to get webhook: %v", err)
+	}
+	if len(eventTypes) > 0 {
+		w.EventTypes = eventTypes
+	}
+	if disabledAt.Valid {
+		w.DisabledAt = &disabledAt.Time
+	}
+
+	return w, nil
+}
+
+// eventTypeArray передает типы изменений вебхука в запрос: пустой массив означает все типы.
+func eventTypeArray(types []string) interface{} {
+	if types == nil {
+		types = []string{}
+	}
+
+	return pq.Array(types)
+}
+
+// enqueueDeliveries ставит изменение rev в очередь доставки на активные вебхуки владельца события,
+// подписанные на изменения этого типа.
+func enqueueDeliveries(ctx context.Context, q querier, rev models.Revision) error {
+	eventType, payload, err := storage.WebhookPayload(rev)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.ExecContext(ctx,
+		`INSERT INTO webhook_delivery (webhook_id, event_type, payload, next_attempt_at)
+         SELECT w.id, $2, $3, now() FROM webhook w JOIN event e ON e.user_id = w.user_id
+         WHERE e.id = $1 AND w.active AND (cardinality(w.event_types) = 0 OR $2 = ANY(w.event_types))`,
+		rev.EventID, eventType, string(payload),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue webhook deliveries: %v", err)
+	}
+
+	return nil
+}
+
+// outboxRelayLockKey — ключ advisory-блокировки, которая оставляет релей outbox одной реплике.
+const outboxRelayLockKey = 7_201_105_115
+
+// insertOutbox записывает уведомление record в outbox. Id берется отдельным запросом до снимка,
+// по которому считается tx_horizon: транзакции, получившие меньшие id, к этому моменту уже
+// получили txid и попадают под горизонт. txid_current() выдает txid и самой транзакции, если
+// она еще ничего не записала.
+func insertOutbox(ctx context.Context, q querier, record models.OutboxRecord) error {
+	var txid, id int64
+	if err := q.QueryRowContext(ctx, "SELECT txid_current(), nextval('outbox_id_seq')").Scan(&txid, &id); err != nil {
+		return fmt.Errorf("failed to save outbox record: %v", err)
+	}
+
+	_, err := q.ExecContext(ctx,
+		`INSERT INTO outbox (id, aggregate_type, aggregate_id, type, payload, created_at, tx_horizon)
+         VALUES ($1, $2, $3, $4, $5, $6, txid_snapshot_xmax(txid_current_snapshot()))`,
+		id, record.AggregateType, record.AggregateID, record.Type, string(record.Payload), record.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save outbox record: %v", err)
+	}
+
+	return nil
+}
+
+// withAttempts дополняет доставки их попытками, от первой к последней.
+func withAttempts(ctx context.Context, q querier, deliveries []models.Delivery) ([]models.Delivery, error) {
+	if len(deliveries) == 0 {
+		return deliveries, nil
+	}
+
+	ids := make([]int64, len(deliveries))
+	index := make(map[int64]int, len(deliveries))
+	for i, d := range deliveries {
+		ids[i] = d.ID
+		index[d.ID] = i
+	}
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT delivery_id, attempted_at, status_code, error, duration_ms
+         FROM webhook_attempt WHERE delivery_id = ANY($1) ORDER BY id`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery attempts: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a models.DeliveryAttempt
+		var statusCode sql.NullInt64
+		var durationMs int64
+		if err = rows.Scan(&a.DeliveryID, &a.AttemptedAt, &statusCode, &a.Error, &durationMs); err != nil {
+			return nil, fmt.Errorf("failed to get delivery attempts: %v", err)
+		}
+		a.StatusCode = int(statusCode.Int64)
+		a.Duration = time.Duration(durationMs) * time.Millisecond
+		i := index[a.DeliveryID]
+		deliveries[i].History = append(deliveries[i].History, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get delivery attempts: %v", err)
+	}
+
+	return deliveries, nil
+}
+
+const calendarColumns = "id, user_id, name, color, time_zone, is_default, created_at"
+
+// scanCalendar читает календарь из строки с колонками calendarColumns. Если строки нет,
+// возвращается ErrCalendarNotFound, если изменение заняло имя другого календаря — ErrCalendarExists.
+func scanCalendar(row *sql.Row) (models.Calendar, error) {
+	var c models.Calendar
+	err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.Color, &c.TimeZone, &c.Default, &c.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Calendar{}, storage.ErrCalendarNotFound
+	}
+	if isUniqueViolation(err) {
+		return models.Calendar{}, storage.ErrCalendarExists
+	}
+	if err != nil {
+		return models.Calendar{}, fmt.Errorf("failed to get calendar: %v", err)
+	}
+
+	return c, nil
+}
+
+// calendarArray передает фильтр по календарям в запрос: пустой массив означает все календари.
+func calendarArray(ids []int64) interface{} {
+	if ids == nil {
+		ids = []int64{}
+	}
+
+	return pq.Array(ids)
+}
+
+const userColumns = "user_id, name, email, time_zone, locale, created_at"
+
+// scanUser читает профиль пользователя из строки с колонками userColumns. Если строки нет,
+// возвращается ErrUserNotFound, если изменение профиля заняло чужой email — ErrEmailTaken.
+func scanUser(row *sql.Row) (models.User, error) {
+	var user models.User
+	var createdAt sql.NullTime
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.TimeZone, &user.Locale, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, storage.ErrUserNotFound
+	}
+	if isUniqueViolation(err) {
+		return models.User{}, storage.ErrEmailTaken
+	}
+	if err != nil {
+		return models.User{}, fmt.Errorf("failed to get user: %v", err)
+	}
+	user.CreatedAt = createdAt.Time
+
+	return user, nil
+}
+
+// isUniqueViolation сообщает, что запрос нарушил уникальный индекс.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// changedPart возвращает событие или повторение, которое затрагивает изменение серии со scope,
+// и исходную дату повторения (пустую, если изменение касается всего события).
+func changedPart(ctx context.Context, q querier, series models.Event, scope models.Scope, occurrenceDate string) (models.Event, string, error) {
+	if series.Recurrence == "" || scope == models.ScopeAll {
+		return series, "", nil
+	}
+	if err := storage.CheckOccurrence(series, occurrenceDate); err != nil {
+		return models.Event{}, "", err
+	}
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT x.event_id, x.original_date, x.cancelled, x.date, x.text, x.starts_at, x.ends_at, x.time_zone, e.rrule, e.version, e.calendar_id, e.user_id
+         FROM event_exception x JOIN event e ON e.id = x.event_id
+         WHERE x.event_id = $1 AND x.original_date = $2`,
+		series.ID, occurrenceDate,
+	)
+	if err != nil {
+		return models.Event{}, "", fmt.Errorf("failed to get occurrence: %v", err)
+	}
+	defer rows.Close()
+
+	exceptions, err := scanExceptions(rows)
+	if err != nil {
+		return models.Event{}, "", fmt.Errorf("failed to get occurrence: %v", err)
+	}
+	var x *models.Exception
+	if len(exceptions) > 0 {
+		x = &exceptions[0]
+	}
+
+	return storage.Occurrence(series, occurrenceDate, x), occurrenceDate, nil
+}
+
+// insertRevision записывает изменение в историю события, ставит его в очередь доставки на вебхуки
+// и записывает в outbox.
+func insertRevision(ctx context.Context, q querier, rev models.Revision) error {
+	err := q.QueryRowContext(ctx,
+		`INSERT INTO event_revision (event_id, user_id, action, occurrence_date, old_date, new_date, old_text, new_text)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+         RETURNING changed_at`,
+		rev.EventID, rev.UserID, string(rev.Action), nullString(rev.OccurrenceDate), nullString(rev.OldDate),
+		nullString(rev.NewDate), nullString(rev.OldText), nullString(rev.NewText),
+	).Scan(&rev.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save event revision: %v", err)
+	}
+
+	if err = enqueueDeliveries(ctx, q, rev); err != nil {
+		return err
+	}
+
+	record, err := storage.EventOutboxRecord(rev)
+	if err != nil {
+		return err
+	}
+
+	return insertOutbox(ctx, q, record)
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func formatDate(t sql.NullTime) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.Format("2006-01-02")
+}
+
+// bumpVersion увеличивает версию события и возвращает новую.
+func bumpVersion(ctx context.Context, q querier, eventID int64) (int64, error) {
+	var version int64
+	err := q.QueryRowContext(ctx, "UPDATE event SET version = version + 1 WHERE id = $1 RETURNING version", eventID).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update event version: %v", err)
+	}
+
+	return version, nil
+}
+
+// setTags заменяет теги события, создавая недостающие теги пользователя.
+func setTags(ctx context.Context, q querier, userID, eventID int64, tags []string) error {
+	if _, err := q.ExecContext(ctx, "DELETE FROM event_tag WHERE event_id = $1", eventID); err != nil {
+		return fmt.Errorf("failed to set tags: %v", err)
+	}
 
-	if err = db.Ping(); err != nil {
-		log.Fatalf("couldn't connect to the DB: %v", err)
-		return nil, err
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := q.ExecContext(ctx,
+		"INSERT INTO tag (user_id, name) SELECT $1::int, unnest($2::text[]) ON CONFLICT (user_id, name) DO NOTHING",
+		userID, pq.Array(tags),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set tags: %v", err)
+	}
+
+	_, err = q.ExecContext(ctx,
+		`INSERT INTO event_tag (event_id, tag_id)
+         SELECT $1::int, id FROM tag WHERE user_id = $2 AND name = ANY($3)
+         ON CONFLICT DO NOTHING`,
+		eventID, userID, pq.Array(tags),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set tags: %v", err)
 	}
 
-	return &Storage{db: db}, nil
+	return nil
 }
 
-func (s *Storage) SaveEvent(userID int64, dateStr, text string) (int64, error) {
-	var exists bool
-	err := s.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)", userID).Scan(&exists)
+// loadTags возвращает отсортированные теги событий по их ID.
+func loadTags(ctx context.Context, q querier, eventIDs []int64) (map[int64][]string, error) {
+	if len(eventIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT et.event_id, t.name FROM event_tag et JOIN tag t ON t.id = et.tag_id
+         WHERE et.event_id = ANY($1) ORDER BY t.name`,
+		pq.Array(eventIDs),
+	)
 	if err != nil {
-		return 0, fmt.Errorf("failed to check user: %v", err)
+		return nil, fmt.Errorf("failed to get tags: %v", err)
 	}
-	if !exists {
-		return 0, fmt.Errorf("user with ID %d not found", userID)
+	defer rows.Close()
+
+	tags := make(map[int64][]string)
+	for rows.Next() {
+		var eventID int64
+		var name string
+		if err = rows.Scan(&eventID, &name); err != nil {
+			return nil, fmt.Errorf("failed to get tags: %v", err)
+		}
+		tags[eventID] = append(tags[eventID], name)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get tags: %v", err)
+	}
+
+	return tags, nil
+}
+
+// setAttendees заменяет участников события владельца ownerID: новые участники получают
+// переданные статусы, оставшиеся сохраняют свои ответы. Владелец участником быть не может.
+func setAttendees(ctx context.Context, q querier, ownerID, eventID int64, attendees []models.Attendee) error {
+	ids := make([]int64, 0, len(attendees))
+	statuses := make([]string, 0, len(attendees))
+	for _, a := range attendees {
+		if a.UserID == ownerID {
+			return storage.ErrAttendeeOwner
+		}
+		ids = append(ids, a.UserID)
+		statuses = append(statuses, string(a.Status))
 	}
 
-	date, err := time.Parse("2006-01-02", dateStr)
+	_, err := q.ExecContext(ctx,
+		"DELETE FROM event_attendee WHERE event_id = $1 AND NOT (user_id = ANY($2))", eventID, pq.Array(ids),
+	)
 	if err != nil {
-		return 0, fmt.Errorf("invalid date format: %v", err)
+		return fmt.Errorf("failed to set attendees: %v", err)
 	}
 
-	var eventID int64
-	err = s.db.QueryRow(
-		`INSERT INTO event (user_id, date, text) 
-         VALUES ($1, $2, $3) RETURNING id`,
-		userID, date, text,
-	).Scan(&eventID)
+	if len(ids) == 0 {
+		return nil
+	}
 
+	var found int
+	err = q.QueryRowContext(ctx, "SELECT count(*) FROM users WHERE user_id = ANY($1)", pq.Array(ids)).Scan(&found)
 	if err != nil {
-		return 0, fmt.Errorf("failed to save event: %v", err)
+		return fmt.Errorf("failed to set attendees: %v", err)
+	}
+	if found != len(ids) {
+		return storage.ErrUserNotFound
 	}
 
-	return eventID, nil
+	_, err = q.ExecContext(ctx,
+		`INSERT INTO event_attendee (event_id, user_id, status)
+         SELECT $1::int, a.user_id, a.status FROM unnest($2::int[], $3::text[]) AS a(user_id, status)
+         ON CONFLICT (event_id, user_id) DO NOTHING`,
+		eventID, pq.Array(ids), pq.Array(statuses),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set attendees: %v", err)
+	}
+
+	return nil
 }
 
-func (s *Storage) UpdateEvent(userID, eventID int64, dateStr, text string) error {
-	query := "UPDATE event SET"
-	args := []interface{}{}
-	argPos := 1
+// loadAttendees возвращает участников событий по их ID в порядке возрастания ID пользователей.
+func loadAttendees(ctx context.Context, q querier, eventIDs []int64) (map[int64][]models.Attendee, error) {
+	if len(eventIDs) == 0 {
+		return nil, nil
+	}
 
-	if dateStr != "" {
-		date, err := time.Parse("2006-01-02", dateStr)
-		if err != nil {
-			return fmt.Errorf("invalid date format: %v", err)
+	rows, err := q.QueryContext(ctx,
+		`SELECT event_id, user_id, status, responded_at FROM event_attendee
+         WHERE event_id = ANY($1) ORDER BY user_id`,
+		pq.Array(eventIDs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendees: %v", err)
+	}
+	defer rows.Close()
+
+	attendees := make(map[int64][]models.Attendee)
+	for rows.Next() {
+		var eventID int64
+		var a models.Attendee
+		var respondedAt sql.NullTime
+		if err = rows.Scan(&eventID, &a.UserID, &a.Status, &respondedAt); err != nil {
+			return nil, fmt.Errorf("failed to get attendees: %v", err)
 		}
-		query += fmt.Sprintf(" date = $%d,", argPos)
-		args = append(args, date)
-		argPos++
+		if respondedAt.Valid {
+			a.RespondedAt = &respondedAt.Time
+		}
+		attendees[eventID] = append(attendees[eventID], a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get attendees: %v", err)
 	}
 
-	if text != "" {
-		query += fmt.Sprintf(" text = $%d,", argPos)
-		args = append(args, text)
-		argPos++
+	return attendees, nil
+}
+
+// setReminders заменяет напоминания события. Оставшиеся напоминания сохраняют свое расписание,
+// пересчитать его должен scheduleReminders.
+func setReminders(ctx context.Context, q querier, eventID int64, reminders []time.Duration) error {
+	minutes := make([]int64, 0, len(reminders))
+	for _, before := range reminders {
+		minutes = append(minutes, int64(before/time.Minute))
 	}
 
-	if len(args) == 0 {
+	_, err := q.ExecContext(ctx,
+		"DELETE FROM event_reminder WHERE event_id = $1 AND NOT (minutes_before = ANY($2))", eventID, pq.Array(minutes),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set reminders: %v", err)
+	}
+
+	if len(minutes) == 0 {
 		return nil
 	}
 
-	query = strings.TrimSuffix(query, ",")
+	_, err = q.ExecContext(ctx,
+		`INSERT INTO event_reminder (event_id, minutes_before)
+         SELECT $1::int, unnest($2::int[])
+         ON CONFLICT (event_id, minutes_before) DO NOTHING`,
+		eventID, pq.Array(minutes),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set reminders: %v", err)
+	}
 
-	query += fmt.Sprintf(" WHERE id = $%d AND user_id = $%d", argPos, argPos+1)
-	args = append(args, eventID, userID)
+	return nil
+}
+
+// loadReminders возвращает напоминания событий по их ID от ближайших к началу события к дальним.
+func loadReminders(ctx context.Context, q querier, eventIDs []int64) (map[int64][]time.Duration, error) {
+	if len(eventIDs) == 0 {
+		return nil, nil
+	}
 
-	result, err := s.db.Exec(query, args...)
+	rows, err := q.QueryContext(ctx,
+		"SELECT event_id, minutes_before FROM event_reminder WHERE event_id = ANY($1) ORDER BY minutes_before",
+		pq.Array(eventIDs),
+	)
 	if err != nil {
-		return fmt.Errorf("failed to update event: %v", err)
+		return nil, fmt.Errorf("failed to get reminders: %v", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("event not found or access denied")
+	reminders := make(map[int64][]time.Duration)
+	for rows.Next() {
+		var eventID, minutes int64
+		if err = rows.Scan(&eventID, &minutes); err != nil {
+			return nil, fmt.Errorf("failed to get reminders: %v", err)
+		}
+		reminders[eventID] = append(reminders[eventID], time.Duration(minutes)*time.Minute)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get reminders: %v", err)
 	}
 
-	return nil
+	return reminders, nil
 }
 
-func (s *Storage) DeleteEvent(userID, eventID int64) error {
-	err := s.db.QueryRow(
-		"DELETE FROM event WHERE id = $1 AND user_id = $2",
-		eventID,
-		userID,
+// scheduleReminders пересчитывает, когда сработают напоминания события eventID после его изменения.
+// Напоминание, которому уже пора сработать, не пропускается: его заберет FireReminders.
+func scheduleReminders(ctx context.Context, q querier, eventID int64) error {
+	rows, err := q.QueryContext(ctx,
+		"SELECT id, minutes_before, next_fire_at FROM event_reminder WHERE event_id = $1", eventID,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to delete event: %v", err)
+		return fmt.Errorf("failed to schedule reminders: %v", err)
+	}
+	defer rows.Close()
+
+	type reminder struct {
+		id         int64
+		before     time.Duration
+		nextFireAt sql.NullTime
+	}
+	var reminders []reminder
+	for rows.Next() {
+		var r reminder
+		var minutes int64
+		if err = rows.Scan(&r.id, &minutes, &r.nextFireAt); err != nil {
+			return fmt.Errorf("failed to schedule reminders: %v", err)
+		}
+		r.before = time.Duration(minutes) * time.Minute
+		reminders = append(reminders, r)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to schedule reminders: %v", err)
+	}
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	series, exceptions, err := loadSeries(ctx, q, eventID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, r := range reminders {
+		after := now
+		if r.nextFireAt.Valid && !r.nextFireAt.Time.After(now) {
+			after = r.nextFireAt.Time.Add(-time.Nanosecond)
+		}
+
+		_, fireAt, ok, err := storage.NextReminder(series, exceptions, r.before, after)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.ExecContext(ctx,
+			"UPDATE event_reminder SET next_fire_at = $1 WHERE id = $2", sql.NullTime{Time: fireAt, Valid: ok}, r.id,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to schedule reminders: %v", err)
+		}
 	}
 
 	return nil
 }
 
-func (s *Storage) GetEventsByDay(userID int64, day string) ([]models.Event, error) {
-	date, err := time.Parse("2006-01-02", day)
-	rows, err := s.db.Query(
-		`SELECT id, date, text FROM event 
-         WHERE user_id = $1 AND date = $2 
-         ORDER BY date`,
-		userID, date,
+// loadSeries возвращает событие eventID, в том числе из корзины, и все исключения его серии
+// для расчета напоминаний.
+func loadSeries(ctx context.Context, q querier, eventID int64) (models.Event, []models.Exception, error) {
+	rows, err := q.QueryContext(ctx, `SELECT `+eventColumns+` FROM event WHERE id = $1`, eventID)
+	if err != nil {
+		return models.Event{}, nil, fmt.Errorf("failed to get event: %v", err)
+	}
+	defer rows.Close()
+
+	events, err := scanEvents(rows)
+	if err != nil {
+		return models.Event{}, nil, fmt.Errorf("failed to get event: %v", err)
+	}
+	if len(events) == 0 {
+		return models.Event{}, nil, storage.ErrEventNotFound
+	}
+
+	rows, err = q.QueryContext(ctx,
+		`SELECT x.event_id, x.original_date, x.cancelled, x.date, x.text, x.starts_at, x.ends_at, x.time_zone, e.rrule, e.version, e.calendar_id, e.user_id
+         FROM event_exception x JOIN event e ON e.id = x.event_id
+         WHERE x.event_id = $1`,
+		eventID,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get daily events: %v", err)
+		return models.Event{}, nil, fmt.Errorf("failed to get event exceptions: %v", err)
 	}
 	defer rows.Close()
 
-	return scanEvents(rows)
+	exceptions, err := scanExceptions(rows)
+	if err != nil {
+		return models.Event{}, nil, fmt.Errorf("failed to get event exceptions: %v", err)
+	}
+
+	var timeZone string
+	err = q.QueryRowContext(ctx, "SELECT time_zone FROM calendar WHERE id = $1", events[0].CalendarID).Scan(&timeZone)
+	if err != nil {
+		return models.Event{}, nil, fmt.Errorf("failed to get calendar: %v", err)
+	}
+
+	storage.WithCalendarZone(&events[0], exceptions, timeZone)
+
+	return events[0], exceptions, nil
 }
 
-func (s *Storage) GetEventsByWeek(userID int64, startOfWeek time.Time) ([]models.Event, error) {
-	endOfWeek := startOfWeek.AddDate(0, 0, 7)
-	rows, err := s.db.Query(
-		`SELECT id, date, text FROM event 
-         WHERE user_id = $1 AND date >= $2 AND date < $3 
-         ORDER BY date`,
-		userID,
-		startOfWeek.Format("2006-01-02"),
-		endOfWeek.Format("2006-01-02"),
+// setException заменяет или отменяет повторение серии eventID с исходной датой originalDate.
+func setException(ctx context.Context, q querier, eventID int64, originalDate string, cancelled bool, override models.Event) error {
+	if cancelled {
+		override = models.Event{Date: originalDate}
+	}
+	date, err := time.Parse("2006-01-02", override.Date)
+	if err != nil {
+		return storage.InvalidInput("invalid date format: %v", err)
+	}
+	_, endDate := override.Days()
+
+	_, err = q.ExecContext(ctx,
+		`INSERT INTO event_exception (event_id, original_date, cancelled, date, end_date, text, starts_at, ends_at, time_zone)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+         ON CONFLICT (event_id, original_date) DO UPDATE SET
+             cancelled = EXCLUDED.cancelled, date = EXCLUDED.date, end_date = EXCLUDED.end_date, text = EXCLUDED.text,
+             starts_at = EXCLUDED.starts_at, ends_at = EXCLUDED.ends_at, time_zone = EXCLUDED.time_zone`,
+		eventID, originalDate, cancelled, date, endDate, override.Text,
+		override.StartsAt, override.EndsAt, override.TimeZone,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get weekly events: %v", err)
+		return fmt.Errorf("failed to save occurrence: %v", err)
 	}
-	defer func(rows *sql.Rows) {
-		err = rows.Close()
-		if err != nil {
-			return
-		}
-	}(rows)
 
-	return scanEvents(rows)
+	return nil
 }
 
-func (s *Storage) GetEventsByMonth(userID int64, year int, month time.Month) ([]models.Event, error) {
-	startOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
-	endOfMonth := startOfMonth.AddDate(0, 1, 0)
-	rows, err := s.db.Query(
-		`SELECT id, date, text FROM event 
-         WHERE user_id = $1 AND date >= $2 AND date < $3 
-         ORDER BY date`,
-		userID,
-		startOfMonth.Format("2006-01-02"),
-		endOfMonth.Format("2006-01-02"),
+// truncateSeries оставляет в серии только повторения по правилу head, которое заканчивается
+// до дня at, и удаляет исключения начиная с at.
+func truncateSeries(ctx context.Context, q querier, series models.Event, head, at string) error {
+	series.Recurrence = head
+	seriesEnd, err := storage.SeriesEnd(series)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.ExecContext(ctx,
+		"UPDATE event SET rrule = $1, series_end = $2 WHERE id = $3",
+		head, sql.NullString{String: seriesEnd, Valid: seriesEnd != ""}, series.ID,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get monthly events: %v", err)
+		return fmt.Errorf("failed to split series: %v", err)
 	}
-	defer func(rows *sql.Rows) {
-		err = rows.Close()
-		if err != nil {
-			return
+
+	_, err = q.ExecContext(ctx, "DELETE FROM event_exception WHERE event_id = $1 AND original_date >= $2", series.ID, at)
+	if err != nil {
+		return fmt.Errorf("failed to split series: %v", err)
+	}
+
+	return nil
+}
+
+// dropStaleExceptions удаляет исключения серии eventID, на исходные даты которых после изменения
+// расписания серии не приходится ни одно ее повторение.
+func dropStaleExceptions(ctx context.Context, q querier, eventID int64) error {
+	rows, err := q.QueryContext(ctx, `SELECT `+eventColumns+` FROM event WHERE id = $1`, eventID)
+	if err != nil {
+		return fmt.Errorf("failed to get event: %v", err)
+	}
+	events, err := scanEvents(rows)
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("failed to get event: %v", err)
+	}
+	if len(events) == 0 {
+		return storage.ErrEventNotFound
+	}
+
+	rows, err = q.QueryContext(ctx, "SELECT original_date FROM event_exception WHERE event_id = $1", eventID)
+	if err != nil {
+		return fmt.Errorf("failed to get event exceptions: %v", err)
+	}
+	defer rows.Close()
+
+	var dates []string
+	for rows.Next() {
+		var date time.Time
+		if err = rows.Scan(&date); err != nil {
+			return fmt.Errorf("failed to scan event exception: %v", err)
 		}
-	}(rows)
+		dates = append(dates, date.Format("2006-01-02"))
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to get event exceptions: %v", err)
+	}
+	rows.Close()
+
+	stale, err := storage.StaleExceptions(events[0], dates)
+	if err != nil || len(stale) == 0 {
+		return err
+	}
+
+	_, err = q.ExecContext(ctx,
+		"DELETE FROM event_exception WHERE event_id = $1 AND original_date = ANY($2::date[])", eventID, pq.Array(stale),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete event exceptions: %v", err)
+	}
 
-	return scanEvents(rows)
+	return nil
 }
 
-func (s *Storage) CreateUser() (int64, error) {
-	var userID int64
-	err := s.db.QueryRow(
-		"INSERT INTO users DEFAULT VALUES RETURNING user_id",
-	).Scan(&userID)
+// eventsBetween возвращает события и повторения событий пользователя и событий, на которые
+// он приглашен, которые занимают хотя бы один день из полуинтервала [from, to) и подходят под filter.
+// В выборку из календарей другого владельца приглашения не попадают.
+func (s *Storage) eventsBetween(ctx context.Context, userID int64, from, to time.Time, filter models.EventFilter) ([]models.Event, error) {
+	lower := from.Format("2006-01-02")
+	upper := to.Format("2006-01-02")
 
+	grants, err := grantsOf(ctx, s.db, userID, filter.Owner)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create user: %v", err)
+		return nil, err
 	}
-	return userID, nil
+	if grants != nil {
+		userID = filter.Owner
+		if filter.Calendars, err = storage.SharedCalendars(filter.Calendars, grants); err != nil {
+			return nil, err
+		}
+	}
+
+	invited := grants == nil
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+eventColumns+` FROM event 
+         WHERE (user_id = $1 OR ($4 AND id IN (SELECT event_id FROM event_attendee WHERE user_id = $1)))
+           AND deleted_at IS NULL AND date < $3 AND (series_end IS NULL OR series_end >= $2)
+         ORDER BY date, starts_at NULLS FIRST, id`,
+		userID, lower, upper, invited,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events: %v", err)
+	}
+	defer rows.Close()
+
+	events, err := scanEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Кроме исключений найденных серий берем измененные повторения, переехавшие в окно.
+	rows, err = s.db.QueryContext(ctx,
+		`SELECT x.event_id, x.original_date, x.cancelled, x.date, x.text, x.starts_at, x.ends_at, x.time_zone, e.rrule, e.version, e.calendar_id, e.user_id
+         FROM event_exception x JOIN event e ON e.id = x.event_id
+         WHERE (e.user_id = $1 OR ($4 AND e.id IN (SELECT event_id FROM event_attendee WHERE user_id = $1)))
+           AND e.deleted_at IS NULL AND (
+             (e.date < $3 AND (e.series_end IS NULL OR e.series_end >= $2))
+             OR (NOT x.cancelled AND x.date < $3 AND x.end_date >= $2)
+         )`,
+		userID, lower, upper, invited,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event exceptions: %v", err)
+	}
+	defer rows.Close()
+
+	exceptions, err := scanExceptions(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = withTags(ctx, s.db, events, exceptions); err != nil {
+		return nil, err
+	}
+	if err = withAttendees(ctx, s.db, events, exceptions); err != nil {
+		return nil, err
+	}
+	if err = withReminders(ctx, s.db, events, exceptions); err != nil {
+		return nil, err
+	}
+	events, exceptions = storage.FilterEvents(events, exceptions, filter, grants)
+
+	expanded, err := storage.ExpandRecurring(events, exceptions, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return storage.Redact(expanded, grants), nil
 }
 
-func (s *Storage) Close() error {
-	err := s.db.Close()
+// withTags заполняет теги событий и исключений, исключения получают теги своих серий.
+func withTags(ctx context.Context, q querier, events []models.Event, exceptions []models.Exception) error {
+	ids := make([]int64, 0, len(events)+len(exceptions))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	for _, x := range exceptions {
+		ids = append(ids, x.EventID)
+	}
+
+	tags, err := loadTags(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		events[i].Tags = tags[events[i].ID]
+	}
+	for i := range exceptions {
+		exceptions[i].Override.Tags = tags[exceptions[i].EventID]
+	}
+
+	return nil
+}
+
+// withAttendees заполняет участников событий и исключений, исключения получают участников своих серий.
+func withAttendees(ctx context.Context, q querier, events []models.Event, exceptions []models.Exception) error {
+	ids := make([]int64, 0, len(events)+len(exceptions))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	for _, x := range exceptions {
+		ids = append(ids, x.EventID)
+	}
+
+	attendees, err := loadAttendees(ctx, q, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		events[i].Attendees = attendees[events[i].ID]
+	}
+	for i := range exceptions {
+		exceptions[i].Override.Attendees = attendees[exceptions[i].EventID]
+	}
+
+	return nil
+}
+
+// withReminders заполняет напоминания событий и исключений, исключения получают напоминания своих серий.
+func withReminders(ctx context.Context, q querier, events []models.Event, exceptions []models.Exception) error {
+	ids := make([]int64, 0, len(events)+len(exceptions))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	for _, x := range exceptions {
+		ids = append(ids, x.EventID)
+	}
+
+	reminders, err := loadReminders(ctx, q, ids)
 	if err != nil {
 		return err
 	}
 
+	for i := range events {
+		events[i].Reminders = reminders[events[i].ID]
+	}
+	for i := range exceptions {
+		exceptions[i].Override.Reminders = reminders[exceptions[i].EventID]
+	}
+
 	return nil
 }
 
+const eventColumns = "id, user_id, calendar_id, date, text, starts_at, ends_at, time_zone, rrule, version"
+
 func scanEvents(rows *sql.Rows) ([]models.Event, error) {
 	var events []models.Event
 	for rows.Next() {
 		var e models.Event
-		var eventID int64
 		var eventDate time.Time
-		if err := rows.Scan(&eventID, &eventDate, &e.Text); err != nil {
+		var startsAt, endsAt sql.NullTime
+		if err := rows.Scan(&e.ID, &e.UserID, &e.CalendarID, &eventDate, &e.Text, &startsAt, &endsAt, &e.TimeZone, &e.Recurrence, &e.Version); err != nil {
 			return nil, err
 		}
 		e.Date = eventDate.Format("2006-01-02")
+		if startsAt.Valid && endsAt.Valid {
+			e.StartsAt = &startsAt.Time
+			e.EndsAt = &endsAt.Time
+		}
 		events = append(events, e)
 	}
 
@@ -227,3 +2862,29 @@ func scanEvents(rows *sql.Rows) ([]models.Event, error) {
 	}
 	return events, nil
 }
+
+func scanExceptions(rows *sql.Rows) ([]models.Exception, error) {
+	var exceptions []models.Exception
+	for rows.Next() {
+		var x models.Exception
+		var originalDate, date time.Time
+		var startsAt, endsAt sql.NullTime
+		err := rows.Scan(&x.EventID, &originalDate, &x.Cancelled, &date, &x.Override.Text,
+			&startsAt, &endsAt, &x.Override.TimeZone, &x.Override.Recurrence, &x.Override.Version, &x.Override.CalendarID, &x.Override.UserID)
+		if err != nil {
+			return nil, err
+		}
+		x.OriginalDate = originalDate.Format("2006-01-02")
+		x.Override.Date = date.Format("2006-01-02")
+		if startsAt.Valid && endsAt.Valid {
+			x.Override.StartsAt = &startsAt.Time
+			x.Override.EndsAt = &endsAt.Time
+		}
+		exceptions = append(exceptions, x)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return exceptions, nil
+}
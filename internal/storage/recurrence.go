@@ -0,0 +1,265 @@
+package storage
+
+import (
+	"Events-Service/internal/lib/rrule"
+	"Events-Service/internal/models"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ExpandRecurring заменяет повторяющиеся события их повторениями, которые занимают
+// хотя бы один день из [from, to), и сортирует результат так же, как выборки из postgres.
+// Повторения, для которых есть исключение, пропускаются, а измененные повторения из exceptions
+// добавляются по своим новым датам, даже если их серии нет в events.
+func ExpandRecurring(events []models.Event, exceptions []models.Exception, from, to time.Time) ([]models.Event, error) {
+	lower := from.Format("2006-01-02")
+	upper := to.Format("2006-01-02")
+
+	type occurrenceKey struct {
+		eventID int64
+		date    string
+	}
+	changed := make(map[occurrenceKey]bool, len(exceptions))
+	for _, x := range exceptions {
+		changed[occurrenceKey{x.EventID, x.OriginalDate}] = true
+	}
+
+	var res []models.Event
+	for _, e := range events {
+		if e.Recurrence == "" {
+			res = append(res, e)
+			continue
+		}
+
+		occurrences, err := occurrences(e, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, occ := range occurrences {
+			if changed[occurrenceKey{e.ID, occ.OccurrenceDate}] {
+				continue
+			}
+			first, last := occ.Days()
+			if first < upper && last >= lower {
+				res = append(res, occ)
+			}
+		}
+	}
+
+	for _, x := range exceptions {
+		if x.Cancelled {
+			continue
+		}
+		occ := x.Override
+		occ.ID = x.EventID
+		occ.OccurrenceDate = x.OriginalDate
+		first, last := occ.Days()
+		if first < upper && last >= lower {
+			res = append(res, occ)
+		}
+	}
+
+	SortEvents(res)
+
+	return res, nil
+}
+
+// CheckOccurrence проверяет, что у серии e есть повторение, которое по правилу приходится на date.
+func CheckOccurrence(e models.Event, date string) error {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return InvalidInput("invalid occurrence date: %v", err)
+	}
+
+	occurrences, err := occurrences(e, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+	for _, occ := range occurrences {
+		if occ.OccurrenceDate == date {
+			return nil
+		}
+	}
+
+	return ErrOccurrenceNotFound
+}
+
+// StaleExceptions возвращает те из исходных дат исключений originalDates, на которые не приходится
+// ни одно повторение серии e, например после переноса серии или смены ее правила.
+func StaleExceptions(e models.Event, originalDates []string) ([]string, error) {
+	var stale []string
+	for _, date := range originalDates {
+		err := ErrOccurrenceNotFound
+		if e.Recurrence != "" {
+			err = CheckOccurrence(e, date)
+		}
+		switch {
+		case errors.Is(err, ErrOccurrenceNotFound):
+			stale = append(stale, date)
+		case err != nil:
+			return nil, err
+		}
+	}
+
+	return stale, nil
+}
+
+// SplitSeries делит серию e на повторения до дня at и повторения начиная с него.
+// Возвращает правило первой части (пустое, если до at повторений нет)
+// и правило второй части, в котором COUNT уменьшен на число повторений первой.
+func SplitSeries(e models.Event, at string) (string, string, error) {
+	rule, err := rrule.Parse(e.Recurrence)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid recurrence rule of event %d: %v", e.ID, err)
+	}
+
+	atDate, err := time.Parse("2006-01-02", at)
+	if err != nil {
+		return "", "", InvalidInput("invalid occurrence date: %v", err)
+	}
+
+	start, loc, err := seriesStart(e)
+	if err != nil {
+		return "", "", err
+	}
+
+	before := len(rule.Between(start, time.Time{}, time.Date(atDate.Year(), atDate.Month(), atDate.Day(), 0, 0, 0, 0, loc)))
+
+	tail := *rule
+	if tail.Count > 0 {
+		tail.Count -= before
+	}
+	if before == 0 {
+		return "", tail.String(), nil
+	}
+
+	head := *rule
+	head.Count = 0
+	head.Until = atDate.AddDate(0, 0, -1)
+	head.UntilDate = true
+
+	return head.String(), tail.String(), nil
+}
+
+// SeriesEnd возвращает последний день, который может занять повторяющееся событие,
+// или пустую строку, если серия бесконечна. Для обычного события это последний день события.
+func SeriesEnd(e models.Event) (string, error) {
+	_, lastDay := e.Days()
+	if e.Recurrence == "" {
+		return lastDay, nil
+	}
+
+	rule, err := rrule.Parse(e.Recurrence)
+	if err != nil {
+		return "", InvalidInput("invalid recurrence rule: %v", err)
+	}
+
+	start, loc, err := seriesStart(e)
+	if err != nil {
+		return "", err
+	}
+
+	last, ok := rule.Last(start)
+	if !ok {
+		if rule.Until.IsZero() {
+			return "", nil
+		}
+		last = rule.Until.In(loc)
+		if rule.UntilDate {
+			last = rule.Until
+		}
+	}
+
+	spanDays := daysBetween(e.Date, lastDay)
+
+	return last.AddDate(0, 0, spanDays).Format("2006-01-02"), nil
+}
+
+// SortEvents упорядочивает события по дате, затем события на весь день раньше событий
+// со временем, затем по времени начала и ID.
+func SortEvents(events []models.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.AllDay() != b.AllDay() {
+			return a.AllDay()
+		}
+		if !a.AllDay() && !a.StartsAt.Equal(*b.StartsAt) {
+			return a.StartsAt.Before(*b.StartsAt)
+		}
+		return a.ID < b.ID
+	})
+}
+
+func occurrences(e models.Event, from, to time.Time) ([]models.Event, error) {
+	rule, err := rrule.Parse(e.Recurrence)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule of event %d: %v", e.ID, err)
+	}
+
+	start, loc, err := seriesStart(e)
+	if err != nil {
+		return nil, err
+	}
+
+	// Повторение, начавшееся до from, может еще длиться в первые дни окна.
+	_, lastDay := e.Days()
+	spanDays := daysBetween(e.Date, lastDay)
+	windowStart := time.Date(from.Year(), from.Month(), from.Day()-spanDays, 0, 0, 0, 0, loc)
+	windowEnd := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc)
+
+	var duration time.Duration
+	if !e.AllDay() {
+		duration = e.EndsAt.Sub(*e.StartsAt)
+	}
+
+	var res []models.Event
+	for _, t := range rule.Between(start, windowStart, windowEnd) {
+		occ := e
+		occ.Date = t.Format("2006-01-02")
+		occ.OccurrenceDate = occ.Date
+		if !e.AllDay() {
+			startsAt := t.UTC()
+			endsAt := startsAt.Add(duration)
+			occ.StartsAt = &startsAt
+			occ.EndsAt = &endsAt
+		}
+		res = append(res, occ)
+	}
+
+	return res, nil
+}
+
+// seriesStart возвращает начало первого вхождения серии в часовом поясе события.
+// События на весь день повторяются по датам, поэтому для них используется UTC.
+func seriesStart(e models.Event) (time.Time, *time.Location, error) {
+	if e.AllDay() {
+		date, err := time.Parse("2006-01-02", e.Date)
+		if err != nil {
+			return time.Time{}, nil, InvalidInput("invalid date format: %v", err)
+		}
+		return date, time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return e.StartsAt.In(loc), loc, nil
+}
+
+func daysBetween(from, to string) int {
+	a, errA := time.Parse("2006-01-02", from)
+	b, errB := time.Parse("2006-01-02", to)
+	if errA != nil || errB != nil {
+		return 0
+	}
+
+	return int(b.Sub(a).Hours() / 24)
+}
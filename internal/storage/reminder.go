@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"Events-Service/internal/models"
+	"time"
+)
+
+const (
+	// reminderWindow — на сколько дней вперед за раз разворачивается серия при поиске повторения.
+	reminderWindow = 31
+	// reminderHorizon — дальше скольких лет бесконечная серия без подходящих повторений не просматривается.
+	reminderHorizon = 10
+)
+
+// NextReminder ищет событие или повторение серии series, напоминание за before до начала которого
+// срабатывает позже after. exceptions — исключения серии. Возвращает найденное повторение и момент
+// срабатывания; ok false, если напоминание больше не сработает.
+func NextReminder(series models.Event, exceptions []models.Exception, before time.Duration, after time.Time) (models.Event, time.Time, bool, error) {
+	if series.Recurrence == "" {
+		start, err := series.StartTime()
+		if err != nil {
+			return models.Event{}, time.Time{}, false, err
+		}
+		fireAt := start.Add(-before)
+		return series, fireAt, fireAt.After(after), nil
+	}
+
+	end, err := SeriesEnd(series)
+	if err != nil {
+		return models.Event{}, time.Time{}, false, err
+	}
+	for _, x := range exceptions {
+		if !x.Cancelled && end != "" && x.Override.Date > end {
+			end = x.Override.Date
+		}
+	}
+
+	// День назад — запас на разницу часовых поясов между датами повторений и after.
+	from := after.Add(before).UTC().AddDate(0, 0, -1).Truncate(24 * time.Hour)
+	horizon := from.AddDate(reminderHorizon, 0, 0)
+	for end == "" && from.Before(horizon) || end != "" && from.Format("2006-01-02") <= end {
+		to := from.AddDate(0, 0, reminderWindow)
+
+		occurrences, err := ExpandRecurring([]models.Event{series}, exceptions, from, to)
+		if err != nil {
+			return models.Event{}, time.Time{}, false, err
+		}
+
+		var next models.Event
+		var nextFireAt time.Time
+		for _, occ := range occurrences {
+			start, err := occ.StartTime()
+			if err != nil {
+				return models.Event{}, time.Time{}, false, err
+			}
+			fireAt := start.Add(-before)
+			if fireAt.After(after) && (nextFireAt.IsZero() || fireAt.Before(nextFireAt)) {
+				next, nextFireAt = occ, fireAt
+			}
+		}
+		if !nextFireAt.IsZero() {
+			return next, nextFireAt, true, nil
+		}
+
+		from = to
+	}
+
+	return models.Event{}, time.Time{}, false, nil
+}
+
+// WithCalendarZone задает событию на весь день series и его измененным повторениям на весь день
+// часовой пояс их календаря timeZone: напоминания о них считаются от полуночи в этом поясе.
+func WithCalendarZone(series *models.Event, exceptions []models.Exception, timeZone string) {
+	if series.AllDay() {
+		series.TimeZone = timeZone
+	}
+	for i := range exceptions {
+		if exceptions[i].Override.AllDay() {
+			exceptions[i].Override.TimeZone = timeZone
+		}
+	}
+}
+
+// NewNotification возвращает напоминание reminderID о повторении occ, сработавшее в fireAt.
+func NewNotification(reminderID int64, occ models.Event, before time.Duration, fireAt time.Time) models.Notification {
+	start, _ := occ.StartTime()
+
+	return models.Notification{
+		ReminderID:     reminderID,
+		EventID:        occ.ID,
+		UserID:         occ.UserID,
+		Text:           occ.Text,
+		OccurrenceDate: occ.OccurrenceDate,
+		StartsAt:       start,
+		Before:         before,
+		FireAt:         fireAt,
+	}
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Границы найденных слов в сниппетах, такие же, как по умолчанию у ts_headline в postgres.
+const (
+	HighlightStart = "<b>"
+	HighlightEnd   = "</b>"
+)
+
+// SearchTerms разбивает поисковый запрос на слова в нижнем регистре.
+// Знаки препинания и операторы запроса отбрасываются: событие должно содержать все слова.
+func SearchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Highlight выделяет в text слова из terms и возвращает текст вместе с числом выделенных слов.
+func Highlight(text string, terms []string) (string, int) {
+	wanted := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		wanted[term] = true
+	}
+
+	isWord := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+
+	var b strings.Builder
+	matches := 0
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !isWord(runes[i]) {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(runes) && isWord(runes[j]) {
+			j++
+		}
+		word := string(runes[i:j])
+		if wanted[strings.ToLower(word)] {
+			b.WriteString(HighlightStart + word + HighlightEnd)
+			matches++
+		} else {
+			b.WriteString(word)
+		}
+		i = j
+	}
+
+	return b.String(), matches
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"Events-Service/internal/models"
+	"sort"
+)
+
+// SharedCalendars возвращает календари владельца, события которых попадают в выборку пользователя:
+// calendars, а если он пуст — все календари из grants (доступ пользователя к календарям владельца).
+// Календарь, не открытый пользователю, дает ErrCalendarForbidden.
+func SharedCalendars(calendars []int64, grants map[int64]models.Access) ([]int64, error) {
+	if len(calendars) == 0 {
+		if len(grants) == 0 {
+			return nil, ErrCalendarForbidden
+		}
+
+		for id := range grants {
+			calendars = append(calendars, id)
+		}
+		sort.Slice(calendars, func(i, j int) bool {
+			return calendars[i] < calendars[j]
+		})
+
+		return calendars, nil
+	}
+
+	for _, id := range calendars {
+		if _, ok := grants[id]; !ok {
+			return nil, ErrCalendarForbidden
+		}
+	}
+
+	return calendars, nil
+}
+
+// Redact скрывает текст, теги, участников и напоминания событий из календарей, открытых пользователю только для просмотра
+// занятости. Nil grants — выборка событий самого пользователя, она не меняется.
+func Redact(events []models.Event, grants map[int64]models.Access) []models.Event {
+	if grants == nil {
+		return events
+	}
+
+	for i, e := range events {
+		if redacted(e.CalendarID, grants) {
+			events[i].Text = ""
+			events[i].Tags = nil
+			events[i].Attendees = nil
+			events[i].Reminders = nil
+		}
+	}
+
+	return events
+}
+
+// visibleTags возвращает теги события календаря calendarID, которые видит пользователь с доступом grants.
+func visibleTags(tags []string, calendarID int64, grants map[int64]models.Access) []string {
+	if redacted(calendarID, grants) {
+		return nil
+	}
+
+	return tags
+}
+
+// redacted сообщает, что события календаря calendarID открыты пользователю с доступом grants только
+// для просмотра занятости.
+func redacted(calendarID int64, grants map[int64]models.Access) bool {
+	return grants != nil && !grants[calendarID].Allows(models.AccessRead)
+}
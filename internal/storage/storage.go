@@ -1,8 +1,128 @@
 package storage
 
-import "errors"
+import (
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Виды ошибок хранилища. Каждая ошибка ниже и каждая ошибка Error относится к одному из видов
+// (errors.Is(err, ErrNotFound) и т.д.), по виду обработчики выбирают код ответа.
+// Ошибки без вида — внутренние.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrForbidden    = errors.New("access denied")
+	ErrConflict     = errors.New("conflict")
+	ErrInvalidInput = errors.New("invalid input")
+)
 
 var (
-	ErrEventNotFound = errors.New("event not found")
-	ErrEventExists   = errors.New("event already exists")
+	ErrUserNotFound       = newError(ErrNotFound, "user not found")
+	ErrEventNotFound      = newError(ErrNotFound, "event not found")
+	ErrOccurrenceNotFound = newError(ErrNotFound, "occurrence not found")
+	ErrAPIKeyNotFound     = newError(ErrNotFound, "api key not found")
+	ErrCalendarNotFound   = newError(ErrNotFound, "calendar not found")
+	ErrShareNotFound      = newError(ErrNotFound, "share not found")
+	ErrWebhookNotFound    = newError(ErrNotFound, "webhook not found")
+	// ErrInvitationNotFound — пользователь не приглашен на событие или оно в корзине.
+	ErrInvitationNotFound = newError(ErrNotFound, "invitation not found")
+
+	// ErrRefreshTokenNotFound — токена обновления нет, он истек, уже использован или отозван его ключ API.
+	ErrRefreshTokenNotFound = newError(ErrNotFound, "refresh token not found")
+
+	// ErrEventForbidden — событие принадлежит другому пользователю.
+	ErrEventForbidden = newError(ErrForbidden, "event belongs to another user")
+	// ErrCalendarForbidden — владелец не открыл пользователю календарь, события которого тот запросил.
+	ErrCalendarForbidden = newError(ErrForbidden, "calendar is not shared with user")
+
+	ErrVersionMismatch = newError(ErrConflict, "event version mismatch")
+	ErrEventNotDeleted = newError(ErrConflict, "event is not in trash")
+	ErrEmailTaken      = newError(ErrConflict, "email is already in use")
+	ErrCalendarExists  = newError(ErrConflict, "calendar with this name already exists")
+	ErrDefaultCalendar = newError(ErrConflict, "default calendar cannot be deleted")
+
+	// ErrOccurrenceCalendar — календарь меняется только у всей серии или повторений начиная с одного из них.
+	ErrOccurrenceCalendar = newError(ErrInvalidInput, "calendar cannot be changed for a single occurrence")
+	ErrShareOwner         = newError(ErrInvalidInput, "calendar cannot be shared with its owner")
+	ErrAttendeeOwner      = newError(ErrInvalidInput, "event owner cannot be an attendee")
+
+	// ErrBatchAborted — результат операции пакета, не примененной из-за ошибки другой операции.
+	ErrBatchAborted = errors.New("batch aborted")
+
+	// ErrCanceled и ErrTimeout — операция прервана, потому что клиент отменил запрос
+	// или истек срок, отведенный запросу на работу с хранилищем.
+	ErrCanceled = errors.New("request canceled")
+	ErrTimeout  = errors.New("storage deadline exceeded")
 )
+
+// Error — ошибка хранилища вида Kind с текстом для клиента.
+type Error struct {
+	Kind error
+	Msg  string
+}
+
+func newError(kind error, msg string) error {
+	return &Error{Kind: kind, Msg: msg}
+}
+
+func (e *Error) Error() string {
+	return e.Msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// InvalidInput возвращает ошибку вида ErrInvalidInput с текстом по формату.
+func InvalidInput(format string, args ...interface{}) error {
+	return newError(ErrInvalidInput, fmt.Sprintf(format, args...))
+}
+
+// CheckOwner возвращает ErrEventForbidden, если событие e принадлежит не пользователю userID.
+func CheckOwner(e models.Event, userID int64) error {
+	if e.UserID != userID {
+		return ErrEventForbidden
+	}
+
+	return nil
+}
+
+// CheckVersion возвращает ErrVersionMismatch, если клиент ожидает версию expected, а у события e другая.
+// Нулевая expected не проверяется.
+func CheckVersion(e models.Event, expected int64) error {
+	if expected != 0 && e.Version != expected {
+		return ErrVersionMismatch
+	}
+
+	return nil
+}
+
+// AbortBatch заменяет результаты пакета, откаченного из-за ошибки операции, на ErrBatchAborted.
+// Ошибки операций, из-за которых пакет откачен, сохраняются.
+func AbortBatch(results []models.BatchResult) []models.BatchResult {
+	for i := range results {
+		if results[i].Err == nil {
+			results[i] = models.BatchResult{Err: ErrBatchAborted}
+		}
+	}
+
+	return results
+}
+
+// ContextError заменяет ошибку операции, выполненной с контекстом ctx, на ErrCanceled или ErrTimeout,
+// если к моменту ошибки ctx уже отменен. Остальные ошибки возвращаются без изменений.
+func ContextError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch ctx.Err() {
+	case context.Canceled:
+		return ErrCanceled
+	case context.DeadlineExceeded:
+		return ErrTimeout
+	}
+
+	return err
+}
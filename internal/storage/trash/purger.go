@@ -0,0 +1,49 @@
+package trash
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/lib/logger/sl"
+	"context"
+	"log/slog"
+	"time"
+)
+
+type Purger interface {
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Run раз в cfg.PurgeInterval окончательно удаляет события, пролежавшие в корзине дольше cfg.Retention,
+// пока не отменен ctx. Первая очистка выполняется сразу. При нулевом Retention очистка отключена.
+func Run(ctx context.Context, log *slog.Logger, purger Purger, cfg config.Trash) {
+	const op = "storage.trash.Run"
+
+	log = log.With(
+		slog.String("op", op),
+	)
+
+	if cfg.Retention <= 0 || cfg.PurgeInterval <= 0 {
+		log.Info("trash purge disabled")
+		return
+	}
+
+	ticker := time.NewTicker(cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := purger.PurgeTrash(ctx, time.Now().Add(-cfg.Retention))
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Error("failed to purge trash", sl.Err(err))
+		} else if purged > 0 {
+			log.Info("trash purged", slog.Int64("count", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
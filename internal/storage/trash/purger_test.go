@@ -0,0 +1,71 @@
+package trash
+
+import (
+	"Events-Service/internal/config"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type purgerFunc func(before time.Time) (int64, error)
+
+func (f purgerFunc) PurgeTrash(_ context.Context, before time.Time) (int64, error) {
+	return f(before)
+}
+
+func TestRun_PurgesUntilCancelled(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	var calls []time.Time
+	purger := purgerFunc(func(before time.Time) (int64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, before)
+		if len(calls) == 3 {
+			cancel()
+		}
+		if len(calls) == 2 {
+			return 0, errors.New("db is down")
+		}
+		return 1, nil
+	})
+
+	start := time.Now()
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, log, purger, config.Trash{Retention: time.Hour, PurgeInterval: time.Millisecond})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("purge did not stop after cancel")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, calls, 3)
+	for _, before := range calls {
+		assert.WithinDuration(t, start.Add(-time.Hour), before, time.Second)
+	}
+}
+
+func TestRun_Disabled(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	purger := purgerFunc(func(before time.Time) (int64, error) {
+		t.Fatal("purge must not run when retention is zero")
+		return 0, nil
+	})
+
+	Run(context.Background(), log, purger, config.Trash{PurgeInterval: time.Millisecond})
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"Events-Service/internal/models"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// webhookPayload — тело доставки: изменение события из его истории.
+type webhookPayload struct {
+	Type           string          `json:"type"`
+	EventId        int64           `json:"event_id"`
+	UserId         int64           `json:"user_id"`
+	OccurrenceDate string          `json:"occurrence_date,omitempty"`
+	ChangedAt      string          `json:"changed_at"`
+	Changes        []webhookChange `json:"changes"`
+}
+
+type webhookChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// WebhookPayload возвращает тип изменения rev для вебхуков и тело его доставки.
+// UserId в теле — автор изменения, ChangedAt — время в UTC (RFC 3339).
+func WebhookPayload(rev models.Revision) (string, []byte, error) {
+	eventType := models.WebhookEventType(rev.Action)
+	if eventType == "" {
+		return "", nil, fmt.Errorf("no webhook event type for action %q", rev.Action)
+	}
+
+	changes := make([]webhookChange, 0, 2)
+	for _, change := range rev.Changes() {
+		changes = append(changes, webhookChange{Field: change.Field, Old: change.Old, New: change.New})
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		Type:           eventType,
+		EventId:        rev.EventID,
+		UserId:         rev.UserID,
+		OccurrenceDate: rev.OccurrenceDate,
+		ChangedAt:      rev.ChangedAt.UTC().Format(time.RFC3339),
+		Changes:        changes,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode webhook payload: %v", err)
+	}
+
+	return eventType, payload, nil
+}
+
+// AttemptOutcome возвращает состояние доставки после попытки attempt: retryAt — время следующей
+// попытки (nil, если повторов больше не будет).
+func AttemptOutcome(attempt models.DeliveryAttempt, retryAt *time.Time) models.DeliveryStatus {
+	switch {
+	case attempt.OK():
+		return models.DeliveryDelivered
+	case retryAt != nil:
+		return models.DeliveryPending
+	default:
+		return models.DeliveryFailed
+	}
+}
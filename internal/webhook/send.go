@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"Events-Service/internal/lib/netguard"
+	"Events-Service/internal/models"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Заголовки запроса доставки. Получатель проверяет подпись: HMAC-SHA256 секретом вебхука
+// от строки "<timestamp>.<тело запроса>", где timestamp — значение HeaderTimestamp.
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+	userAgent       = "events-service-webhooks"
+)
+
+// DefaultTimeout — сколько доставка ждет ответа, если таймаут не задан. Без ограничения один
+// зависший вебхук задерживал бы все остальные доставки.
+const DefaultTimeout = 10 * time.Second
+
+// Sign возвращает значение заголовка HeaderSignature для тела body, отправленного в момент timestamp
+// (секунды Unix).
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify сообщает, что signature — подпись тела body, отправленного в момент timestamp, секретом secret.
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// NewClient возвращает клиент для отправки доставок: запрос ждет ответа не дольше timeout
+// (DefaultTimeout, если timeout не положительный), перенаправления не выполняются и считаются неудачей.
+// Подключение к адресу, который не пропускает guard, не выполняется; прокси из окружения
+// не используется, иначе проверялся бы адрес прокси, а не вебхука.
+func NewClient(timeout time.Duration, guard *netguard.Guard) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   guard.Control,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   requestTimeout(timeout),
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Send отправляет доставку d на адрес ее вебхука и возвращает попытку. Вебхук принимает изменение
+// ответом 2xx, любой другой ответ или ошибка запроса записываются в попытку как неудача.
+func Send(ctx context.Context, client *http.Client, d models.Delivery) models.DeliveryAttempt {
+	start := time.Now()
+	attempt := models.DeliveryAttempt{DeliveryID: d.ID, AttemptedAt: start.UTC()}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		attempt.Error = fmt.Sprintf("invalid request: %v", err)
+		return attempt
+	}
+
+	timestamp := strconv.FormatInt(start.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderID, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(d.Secret, timestamp, d.Payload))
+
+	resp, err := client.Do(req)
+	attempt.Duration = time.Since(start)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	// Тело ответа не нужно, но дочитанный ответ оставляет соединение для следующих доставок.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("unexpected response status %s", resp.Status)
+	}
+
+	return attempt
+}
+
+// requestTimeout возвращает таймаут запроса доставки: timeout или DefaultTimeout, если он не задан.
+func requestTimeout(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return DefaultTimeout
+	}
+
+	return timeout
+}
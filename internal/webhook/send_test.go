@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"Events-Service/internal/lib/netguard"
+	"Events-Service/internal/models"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	// Ожидаемое значение: printf '1700000000.{"a":1}' | openssl dgst -sha256 -hmac secret
+	signature := Sign("secret", "1700000000", []byte(`{"a":1}`))
+	assert.Equal(t, "sha256=49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686", signature)
+
+	assert.True(t, Verify("secret", "1700000000", []byte(`{"a":1}`), signature))
+	assert.False(t, Verify("other", "1700000000", []byte(`{"a":1}`), signature))
+	assert.False(t, Verify("secret", "1700000001", []byte(`{"a":1}`), signature))
+	assert.False(t, Verify("secret", "1700000000", []byte(`{"a":2}`), signature))
+}
+
+func TestSend(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	d := models.Delivery{
+		ID:        42,
+		EventType: models.WebhookEventCreated,
+		Payload:   []byte(`{"type":"event.created"}`),
+		URL:       server.URL,
+		Secret:    "s3cret",
+	}
+	attempt := Send(context.Background(), NewClient(time.Second, netguard.New(true)), d)
+
+	assert.True(t, attempt.OK())
+	assert.Equal(t, int64(42), attempt.DeliveryID)
+	assert.Equal(t, http.StatusNoContent, attempt.StatusCode)
+	assert.Empty(t, attempt.Error)
+
+	require.NotNil(t, got)
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	assert.Equal(t, "42", got.Header.Get(HeaderID))
+	assert.Equal(t, models.WebhookEventCreated, got.Header.Get(HeaderEvent))
+	assert.Equal(t, d.Payload, body)
+	assert.True(t, Verify("s3cret", got.Header.Get(HeaderTimestamp), body, got.Header.Get(HeaderSignature)))
+}
+
+func TestSend_Failures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		case "/ok":
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(50*time.Millisecond, netguard.New(true))
+
+	attempt := Send(context.Background(), client, models.Delivery{URL: server.URL + "/fail"})
+	assert.False(t, attempt.OK())
+	assert.Equal(t, http.StatusInternalServerError, attempt.StatusCode)
+	assert.Contains(t, attempt.Error, "500")
+
+	// Перенаправление не выполняется: вебхук должен ответить сам.
+	attempt = Send(context.Background(), client, models.Delivery{URL: server.URL + "/redirect"})
+	assert.False(t, attempt.OK())
+	assert.Equal(t, http.StatusFound, attempt.StatusCode)
+
+	attempt = Send(context.Background(), client, models.Delivery{URL: server.URL + "/slow"})
+	assert.False(t, attempt.OK())
+	assert.Zero(t, attempt.StatusCode)
+	assert.NotEmpty(t, attempt.Error)
+
+	attempt = Send(context.Background(), client, models.Delivery{URL: "://bad"})
+	assert.False(t, attempt.OK())
+	assert.Contains(t, attempt.Error, "invalid request")
+}
+
+func TestSend_ForbiddenAddress(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// Адрес проверяется при подключении, поэтому вебхук на loopback не получает запрос.
+	attempt := Send(context.Background(), NewClient(time.Second, netguard.New(false)), models.Delivery{URL: server.URL})
+	assert.False(t, attempt.OK())
+	assert.Zero(t, attempt.StatusCode)
+	assert.Contains(t, attempt.Error, netguard.ErrForbidden.Error())
+	assert.False(t, called)
+}
+
+func TestNewClient_DefaultTimeout(t *testing.T) {
+	assert.Equal(t, DefaultTimeout, NewClient(0, netguard.New(true)).Timeout)
+	assert.Equal(t, time.Second, NewClient(time.Second, netguard.New(true)).Timeout)
+}
@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/lib/netguard"
+	"Events-Service/internal/lib/retry"
+	"Events-Service/internal/models"
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+type Store interface {
+	ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.Delivery, error)
+	RecordAttempt(ctx context.Context, attempt models.DeliveryAttempt, retryAt *time.Time, disableAfter int) (bool, error)
+}
+
+// Run раз в cfg.PollInterval забирает доставки, которым пора уйти, и отправляет их на вебхуки,
+// пока не отменен ctx. Первая проверка выполняется сразу, за одну проверку отправляются все
+// накопившиеся доставки. Забранные доставки отложены на время отправки всей пачки: если процесс
+// остановится, не записав попытку, доставка уйдет повторно, поэтому получатель должен быть готов
+// к повторам с тем же HeaderID.
+func Run(ctx context.Context, log *slog.Logger, store Store, cfg config.Webhooks) {
+	const op = "webhook.Run"
+
+	log = log.With(
+		slog.String("op", op),
+	)
+
+	if cfg.PollInterval <= 0 || cfg.BatchSize <= 0 {
+		log.Info("webhooks disabled")
+		return
+	}
+
+	timeout := requestTimeout(cfg.Timeout)
+	if timeout != cfg.Timeout {
+		log.Warn("webhook timeout is not set, using default", slog.Duration("timeout", timeout))
+	}
+	client := NewClient(timeout, netguard.New(cfg.AllowPrivateNetworks))
+	lease := time.Duration(cfg.BatchSize)*timeout + time.Minute
+
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for {
+			deliveries, err := store.ClaimDeliveries(ctx, time.Now(), lease, cfg.BatchSize)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				log.Error("failed to claim webhook deliveries", sl.Err(err))
+				break
+			}
+
+			for _, d := range deliveries {
+				deliver(ctx, log, store, client, d, cfg)
+				if ctx.Err() != nil {
+					return
+				}
+			}
+
+			if len(deliveries) < cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver отправляет доставку d и записывает попытку. Неудачная доставка повторяется с экспоненциальной
+// задержкой, пока у нее остаются попытки из cfg.MaxAttempts.
+func deliver(ctx context.Context, log *slog.Logger, store Store, client *http.Client, d models.Delivery, cfg config.Webhooks) {
+	log = log.With(
+		slog.Int64("delivery_id", d.ID),
+		slog.Int64("webhook_id", d.WebhookID),
+	)
+
+	attempt := Send(ctx, client, d)
+	if ctx.Err() != nil {
+		// Прерванная остановкой попытка не записывается: доставка вернется в очередь.
+		return
+	}
+
+	var retryAt *time.Time
+	if n := d.Attempts + 1; !attempt.OK() && n < max(cfg.MaxAttempts, 1) {
+		policy := config.Retry{
+			InitialInterval: cfg.RetryInterval,
+			MaxInterval:     cfg.MaxRetryInterval,
+			Multiplier:      2,
+			Jitter:          0.2,
+		}
+		next := attempt.AttemptedAt.Add(retry.Backoff(policy, n, rand.Float64()))
+		retryAt = &next
+	}
+
+	disabled, err := store.RecordAttempt(ctx, attempt, retryAt, cfg.DisableAfter)
+	if err != nil {
+		log.Error("failed to record webhook delivery attempt", sl.Err(err))
+		return
+	}
+
+	switch {
+	case attempt.OK():
+		log.Info("webhook delivered", slog.Int("status", attempt.StatusCode))
+	case retryAt != nil:
+		log.Warn("webhook delivery failed, will retry", slog.String("error", attempt.Error), slog.Time("retry_at", *retryAt))
+	default:
+		log.Error("webhook delivery failed", slog.String("error", attempt.Error), slog.Int("attempts", d.Attempts+1))
+	}
+	if disabled {
+		log.Warn("webhook disabled after repeated failures", slog.Int("disable_after", cfg.DisableAfter))
+	}
+}
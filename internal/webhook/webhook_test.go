@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"Events-Service/internal/config"
+	"Events-Service/internal/models"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// storeFunc — хранилище доставок из двух функций.
+type storeFunc struct {
+	claim  func(now time.Time, lease time.Duration, limit int) ([]models.Delivery, error)
+	record func(attempt models.DeliveryAttempt, retryAt *time.Time, disableAfter int) (bool, error)
+}
+
+func (s storeFunc) ClaimDeliveries(_ context.Context, now time.Time, lease time.Duration, limit int) ([]models.Delivery, error) {
+	return s.claim(now, lease, limit)
+}
+
+func (s storeFunc) RecordAttempt(_ context.Context, attempt models.DeliveryAttempt, retryAt *time.Time, disableAfter int) (bool, error) {
+	return s.record(attempt, retryAt, disableAfter)
+}
+
+type recorded struct {
+	attempt models.DeliveryAttempt
+	retryAt *time.Time
+}
+
+func TestRun_RetriesFailedDeliveries(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(HeaderID) == "2" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.Webhooks{
+		PollInterval:         time.Millisecond,
+		BatchSize:            2,
+		Timeout:              time.Second,
+		MaxAttempts:          3,
+		RetryInterval:        time.Minute,
+		MaxRetryInterval:     time.Hour,
+		DisableAfter:         5,
+		AllowPrivateNetworks: true,
+	}
+
+	var mu sync.Mutex
+	var claims int
+	var attempts []recorded
+	store := storeFunc{
+		claim: func(now time.Time, lease time.Duration, limit int) ([]models.Delivery, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			claims++
+			assert.Equal(t, 2, limit)
+			assert.Greater(t, lease, 2*cfg.Timeout)
+			switch claims {
+			case 1:
+				// Полная пачка: следующая забирается без ожидания тика.
+				return []models.Delivery{
+					{ID: 1, URL: server.URL, Payload: []byte(`{}`)},
+					{ID: 2, URL: server.URL, Payload: []byte(`{}`)},
+				}, nil
+			case 2:
+				// Последняя попытка доставки: повторов больше не будет.
+				return []models.Delivery{{ID: 2, URL: server.URL, Payload: []byte(`{}`), Attempts: 2}}, nil
+			case 3:
+				return nil, errors.New("db is down")
+			default:
+				cancel()
+				return nil, nil
+			}
+		},
+		record: func(attempt models.DeliveryAttempt, retryAt *time.Time, disableAfter int) (bool, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, 5, disableAfter)
+			attempts = append(attempts, recorded{attempt: attempt, retryAt: retryAt})
+			return false, nil
+		},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, log, store, cfg)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("sender did not stop after cancel")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 4, claims)
+	if assert.Len(t, attempts, 3) {
+		assert.True(t, attempts[0].attempt.OK())
+		assert.Nil(t, attempts[0].retryAt)
+
+		assert.False(t, attempts[1].attempt.OK())
+		assert.Equal(t, http.StatusServiceUnavailable, attempts[1].attempt.StatusCode)
+		if assert.NotNil(t, attempts[1].retryAt) {
+			// Первый повтор — через RetryInterval с отклонением до 20%.
+			wait := attempts[1].retryAt.Sub(attempts[1].attempt.AttemptedAt)
+			assert.InDelta(t, float64(time.Minute), float64(wait), float64(12*time.Second))
+		}
+
+		assert.False(t, attempts[2].attempt.OK())
+		assert.Nil(t, attempts[2].retryAt)
+	}
+}
+
+func TestRun_Disabled(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	store := storeFunc{
+		claim: func(now time.Time, lease time.Duration, limit int) ([]models.Delivery, error) {
+			t.Fatal("sender must not run when poll interval is zero")
+			return nil, nil
+		},
+	}
+
+	Run(context.Background(), log, store, config.Webhooks{BatchSize: 10})
+}
+
+func TestRun_DefaultTimeout(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Без таймаута аренда пачки считается по DefaultTimeout, а не сжимается до минуты.
+	var lease time.Duration
+	store := storeFunc{
+		claim: func(now time.Time, l time.Duration, limit int) ([]models.Delivery, error) {
+			lease = l
+			cancel()
+			return nil, nil
+		},
+	}
+
+	Run(ctx, log, store, config.Webhooks{PollInterval: time.Millisecond, BatchSize: 3})
+
+	assert.Equal(t, 3*DefaultTimeout+time.Minute, lease)
+}
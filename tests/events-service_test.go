@@ -3,28 +3,154 @@ package main_test
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"log/slog"
+	"math"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"Events-Service/internal/config"
+	"Events-Service/internal/http-server/handlers/apiKey"
+	"Events-Service/internal/http-server/handlers/calendar"
+	"Events-Service/internal/http-server/handlers/event/batch"
 	"Events-Service/internal/http-server/handlers/event/createEvent"
+	"Events-Service/internal/http-server/handlers/event/deleteEvent"
+	"Events-Service/internal/http-server/handlers/event/eventHistory"
+	"Events-Service/internal/http-server/handlers/event/getEvents"
+	"Events-Service/internal/http-server/handlers/event/listTags"
+	"Events-Service/internal/http-server/handlers/event/listTrash"
+	"Events-Service/internal/http-server/handlers/event/respondEvent"
+	"Events-Service/internal/http-server/handlers/event/restoreEvent"
+	"Events-Service/internal/http-server/handlers/event/searchEvents"
+	"Events-Service/internal/http-server/handlers/event/updateEvent"
+	"Events-Service/internal/http-server/handlers/share"
+	"Events-Service/internal/http-server/handlers/token"
 	"Events-Service/internal/http-server/handlers/user"
+	"Events-Service/internal/http-server/handlers/webhook"
+	"Events-Service/internal/http-server/middleware/auth"
 	"Events-Service/internal/http-server/middleware/mwlogger"
+	"Events-Service/internal/lib/apikey"
+	"Events-Service/internal/lib/jwtauth"
 	"Events-Service/internal/lib/logger/sl"
+	"Events-Service/internal/lib/netguard"
+	"Events-Service/internal/models"
+	"Events-Service/internal/outbox"
+	"Events-Service/internal/reminder"
+	"Events-Service/internal/storage"
+	"Events-Service/internal/storage/memory"
 	"Events-Service/internal/storage/postgres"
+	"Events-Service/internal/storage/sqlite"
+	"Events-Service/internal/storage/trash"
+	webhooksender "Events-Service/internal/webhook"
 )
 
+type testStorage interface {
+	user.UserCreator
+	user.UserGetter
+	user.UserUpdater
+	user.UserDeleter
+	apiKey.KeyCreator
+	apiKey.KeyLister
+	apiKey.KeyRevoker
+	calendar.CalendarCreator
+	calendar.CalendarLister
+	calendar.CalendarUpdater
+	calendar.CalendarDeleter
+	share.CalendarSharer
+	share.ShareLister
+	share.ShareRevoker
+	webhook.WebhookCreator
+	webhook.WebhookLister
+	webhook.WebhookUpdater
+	webhook.WebhookDeleter
+	webhook.DeliveryLister
+	auth.Authenticator
+	token.Sessions
+	createEvent.CreateEvent
+	updateEvent.UpdateEvent
+	deleteEvent.DeleteEvent
+	respondEvent.RespondEvent
+	getEvents.GetEvents
+	getEvents.ListEvents
+	listTags.ListTags
+	searchEvents.SearchEvents
+	listTrash.ListTrash
+	restoreEvent.RestoreEvent
+	eventHistory.EventHistory
+	batch.Batch
+	trash.Purger
+	reminder.Firer
+	webhooksender.Store
+	outbox.Relayer
+	Close() error
+}
+
+// testStorageType - тип хранилища для тестов. По умолчанию тесты не требуют Postgres,
+// для прогона на базе данных нужно выставить TEST_STORAGE_TYPE=database.
+func testStorageType() string {
+	if storageType := os.Getenv("TEST_STORAGE_TYPE"); storageType != "" {
+		return storageType
+	}
+
+	return config.StorageMemory
+}
+
+// testDatabaseDriver - драйвер базы данных для TEST_STORAGE_TYPE=database, по умолчанию postgres.
+func testDatabaseDriver() string {
+	if driver := os.Getenv("TEST_DATABASE_DRIVER"); driver != "" {
+		return driver
+	}
+
+	return config.DriverPostgres
+}
+
+// setupTestStorage - создает хранилище, выбранное в конфигурации.
+func setupTestStorage(cfg *config.Config, log *slog.Logger) (testStorage, error) {
+	switch cfg.Storage.Type {
+	case config.StorageDatabase:
+		return setupTestDatabase(cfg, log)
+	case config.StorageMemory:
+		return memory.New(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage type: %q", cfg.Storage.Type)
+	}
+}
+
+func setupTestDatabase(cfg *config.Config, log *slog.Logger) (testStorage, error) {
+	switch cfg.Database.Driver {
+	case config.DriverPostgres:
+		storage, err := postgres.InitDB(context.Background(), cfg, log)
+		if err != nil {
+			return nil, err
+		}
+		return storage, nil
+	case config.DriverSQLite:
+		storage, err := sqlite.InitDB(context.Background(), cfg, log)
+		if err != nil {
+			return nil, err
+		}
+		return storage, nil
+	default:
+		return nil, fmt.Errorf("unknown database driver: %q", cfg.Database.Driver)
+	}
+}
+
 // setupTestServer - запускает сервер на случайном порту и возвращает его адрес и функцию для остановки.
 func setupTestServer() (string, *sync.WaitGroup, func(), error) {
 	// Создаем тестовую конфигурацию
@@ -34,24 +160,47 @@ func setupTestServer() (string, *sync.WaitGroup, func(), error) {
 			Timeout:     10 * time.Second,
 			IdleTimeout: 60 * time.Second,
 		},
+		Storage: config.Storage{
+			Type: testStorageType(),
+		},
 		Database: config.Database{
+			Driver:   testDatabaseDriver(),
+			Path:     filepath.Join(os.TempDir(), "events-service-test.db"),
 			Host:     "localhost",
 			Port:     5432,
 			User:     "postgres",
 			Password: "3356",           // Вставь пароль для своей тестовой БД
 			DBName:   "events_service", // Используй отдельную БД для тестов
 			SSLMode:  "disable",
+
+			AutoMigrate: true,
+		},
+		JWT: config.JWT{
+			Issuer:     "events-service",
+			Audience:   "events-service",
+			AccessTTL:  15 * time.Minute,
+			RefreshTTL: time.Hour,
+			ActiveKey:  "test",
+			SigningKeys: []config.SigningKey{
+				{ID: "test", Secret: "test-signing-secret-at-least-32-bytes"},
+			},
 		},
 	}
 
 	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 
-	// Инициализируем базу данных, используя новую структуру конфига
-	db, err := postgres.InitDB(cfg)
+	// Инициализируем хранилище, используя новую структуру конфига
+	db, err := setupTestStorage(cfg, log)
 	if err != nil {
 		return "", nil, nil, fmt.Errorf("failed to init test storage: %w", err)
 	}
 
+	tokens, err := jwtauth.New(cfg.JWT)
+	if err != nil {
+		db.Close()
+		return "", nil, nil, fmt.Errorf("failed to init jwt: %w", err)
+	}
+
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
@@ -60,13 +209,44 @@ func setupTestServer() (string, *sync.WaitGroup, func(), error) {
 	router.Use(middleware.URLFormat)
 
 	router.Post("/create_user", user.New(log, db))
-	router.Post("/create_event", createEvent.New(log, db))
-	// Добавь другие хендлеры, если нужно
-	// router.Post("/delete_event", deleteEvent.New(log, db))
-	// router.Post("/update_event", updateEvent.New(log, db))
-	// router.Get("/events_for_day", getEvents.ByDay(log, db))
-	// router.Get("/events_for_week", getEvents.ByWeek(log, db))
-	// router.Get("/events_for_month", getEvents.ByMonth(log, db))
+	router.Post("/token", token.New(log, db, tokens))
+
+	router.Group(func(r chi.Router) {
+		r.Use(auth.New(log, db, tokens))
+
+		r.Get("/get_user", user.Get(log, db))
+		r.Post("/update_user", user.Update(log, db))
+		r.Post("/delete_user", user.Delete(log, db))
+		r.Post("/create_api_key", apiKey.New(log, db))
+		r.Get("/api_keys", apiKey.List(log, db))
+		r.Post("/revoke_api_key", apiKey.Revoke(log, db))
+		r.Post("/create_calendar", calendar.New(log, db))
+		r.Get("/calendars", calendar.List(log, db))
+		r.Post("/update_calendar", calendar.Update(log, db))
+		r.Post("/delete_calendar", calendar.Delete(log, db))
+		r.Post("/share_calendar", share.New(log, db))
+		r.Get("/shares", share.List(log, db))
+		r.Post("/revoke_share", share.Revoke(log, db))
+		r.Post("/create_webhook", webhook.New(log, db, netguard.New(true)))
+		r.Get("/webhooks", webhook.List(log, db))
+		r.Post("/update_webhook", webhook.Update(log, db, netguard.New(true)))
+		r.Post("/delete_webhook", webhook.Delete(log, db))
+		r.Get("/webhook_deliveries", webhook.Deliveries(log, db))
+		r.Post("/create_event", createEvent.New(log, db))
+		r.Post("/delete_event", deleteEvent.New(log, db))
+		r.Post("/rsvp", respondEvent.New(log, db))
+		r.Post("/update_event", updateEvent.New(log, db))
+		r.Get("/events_for_day", getEvents.ByDay(log, db))
+		r.Get("/events_for_week", getEvents.ByWeek(log, db))
+		r.Get("/events_for_month", getEvents.ByMonth(log, db))
+		r.Get("/events", getEvents.List(log, db))
+		r.Get("/tags", listTags.New(log, db))
+		r.Get("/search_events", searchEvents.New(log, db))
+		r.Get("/trash", listTrash.New(log, db))
+		r.Post("/restore_event", restoreEvent.New(log, db))
+		r.Get("/event_history", eventHistory.New(log, db))
+		r.Post("/batch", batch.New(log, db))
+	})
 
 	srv := &http.Server{
 		Handler:      router,
@@ -105,6 +285,10 @@ func setupTestServer() (string, *sync.WaitGroup, func(), error) {
 		db.Close()
 	}
 
+	testDB = db
+	testJWT = cfg.JWT
+	testDatabase = cfg.Database
+
 	return testServerAddr, wg, teardown, nil
 }
 
@@ -112,6 +296,15 @@ func setupTestServer() (string, *sync.WaitGroup, func(), error) {
 var testServerAddr string
 var teardownServer func()
 
+// testDB — хранилище тестового сервера для проверок, которые не доступны через HTTP (очистка корзины).
+var testDB testStorage
+
+// testJWT — настройки токенов тестового сервера, чтобы выпускать токены в обход /token.
+var testJWT config.JWT
+
+// testDatabase — настройки базы данных тестового сервера для проверок с отдельным подключением.
+var testDatabase config.Database
+
 // TestMain запускается перед всеми тестами, чтобы настроить и остановить сервер.
 func TestMain(m *testing.M) {
 	var wg *sync.WaitGroup
@@ -150,17 +343,18 @@ func TestCreateUserAndEvent(t *testing.T) {
 	err = json.NewDecoder(resp.Body).Decode(&userResp)
 	assert.NoError(t, err)
 	assert.True(t, userResp.UserId > 0)
+	assert.NotEmpty(t, userResp.ApiKey)
 
-	// Шаг 2: Используем созданный ID пользователя для создания события.
+	// Шаг 2: Используем выданный ключ для создания события.
 	eventReqBody := createEvent.Request{
-		UserId: userResp.UserId,
-		Date:   "2025-12-25",
-		Text:   "Christmas party",
+		Date: "2025-12-25",
+		Text: "Christmas party",
 	}
 	eventBody, _ := json.Marshal(eventReqBody)
 	eventReq, err := http.NewRequest("POST", "http://"+testServerAddr+"/create_event", bytes.NewBuffer(eventBody))
 	assert.NoError(t, err)
 	eventReq.Header.Set("Content-Type", "application/json")
+	eventReq.Header.Set("Authorization", "Bearer "+userResp.ApiKey)
 
 	resp, err = http.DefaultClient.Do(eventReq)
 	assert.NoError(t, err)
@@ -186,3 +380,2414 @@ func TestCreateUser_InvalidJson(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
+
+// Тестируем обновление события и его выборку за неделю.
+func TestUpdateEventAndGetWeek(t *testing.T) {
+	userID := createTestUser(t)
+	eventID := createTestEvent(t, userID, "2025-11-03", "Standup")
+
+	updateBody, _ := json.Marshal(updateEvent.Request{
+		EventId: eventID,
+		Date:    "2025-11-05",
+		Text:    "Moved standup",
+	})
+	resp := doRequestAs(t, userID, http.MethodPost, "/update_event", updateBody)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	weekBody, _ := json.Marshal(getEvents.Request{
+		Date: "2025-11-03",
+	})
+	resp = doRequestAs(t, userID, http.MethodGet, "/events_for_week", weekBody)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var eventsResp getEvents.Response
+	err := json.NewDecoder(resp.Body).Decode(&eventsResp)
+	assert.NoError(t, err)
+	assert.Equal(t, []getEvents.EventResponse{{
+		EventId: eventID, Date: "2025-11-05", Text: "Moved standup", CalendarId: defaultCalendarOf(t, userID), Version: 2, ETag: `"2"`,
+	}}, eventsResp.Events)
+}
+
+// Тестируем аутентификацию: без ключа, с чужим форматом или неизвестным ключом запрос отклоняется с 401.
+func TestAuthentication(t *testing.T) {
+	body, _ := json.Marshal(createEvent.Request{Date: "2025-12-25", Text: "Nobody's party"})
+
+	send := func(authorization string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, "http://"+testServerAddr+"/create_event", bytes.NewReader(body))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return resp
+	}
+
+	unknownKey, _, err := apikey.Generate()
+	assert.NoError(t, err)
+
+	for _, authorization := range []string{"", "Basic dXNlcjpwYXNz", "Bearer not-a-key", "Bearer " + unknownKey} {
+		resp := send(authorization)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, authorization)
+		assert.NotEmpty(t, resp.Header.Get("WWW-Authenticate"), authorization)
+	}
+
+	// Поле user_id из старых клиентов игнорируется: событие создается от имени владельца ключа.
+	userID := createTestUser(t)
+	otherID := createTestUser(t)
+	body, _ = json.Marshal(map[string]interface{}{"user_id": otherID, "date": "2025-12-25", "text": "Mine"})
+	resp := doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	dayBody, _ := json.Marshal(getEvents.Request{Date: "2025-12-25"})
+	resp = doRequestAs(t, otherID, http.MethodGet, "/events_for_day", dayBody)
+	defer resp.Body.Close()
+
+	var eventsResp getEvents.Response
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&eventsResp))
+	assert.Empty(t, eventsResp.Events)
+}
+
+// Тестируем ключи API: выпуск дополнительного ключа, список без секретов и отзыв.
+func TestAPIKeys(t *testing.T) {
+	userID := createTestUser(t)
+	firstKey := apiKeyOf(userID)
+
+	resp := doRequestAs(t, userID, http.MethodPost, "/create_api_key", nil)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var created apiKey.Response
+	if !assert.NoError(t, json.NewDecoder(resp.Body).Decode(&created)) {
+		t.FailNow()
+	}
+	assert.NotEqual(t, firstKey, created.ApiKey)
+
+	listKeys := func() []apiKey.KeyResponse {
+		resp := doRequestAs(t, userID, http.MethodGet, "/api_keys", nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var listResp apiKey.ListResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&listResp))
+		return listResp.Keys
+	}
+
+	keys := listKeys()
+	if !assert.Len(t, keys, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, created.KeyId, keys[1].KeyId)
+	assert.True(t, strings.HasPrefix(firstKey, keys[0].Prefix))
+	assert.True(t, strings.HasPrefix(created.ApiKey, keys[1].Prefix))
+	assert.Empty(t, keys[0].RevokedAt)
+
+	revoke := func(asUserID, keyID int64) int {
+		body, _ := json.Marshal(apiKey.RevokeRequest{KeyId: keyID})
+		resp := doRequestAs(t, asUserID, http.MethodPost, "/revoke_api_key", body)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Отозвать чужой ключ нельзя: для другого пользователя его нет.
+	assert.Equal(t, http.StatusNotFound, revoke(createTestUser(t), keys[0].KeyId))
+	// Ключом можно отозвать и его самого.
+	assert.Equal(t, http.StatusOK, revoke(userID, keys[0].KeyId))
+
+	resp = doRequestAs(t, userID, http.MethodGet, "/get_user", nil)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// Новый ключ продолжает работать, повторный отзыв ничего не меняет.
+	rememberAPIKey(userID, created.ApiKey)
+	assert.Equal(t, http.StatusOK, revoke(userID, keys[0].KeyId))
+	keys = listKeys()
+	if assert.Len(t, keys, 2) {
+		assert.NotEmpty(t, keys[0].RevokedAt)
+		assert.Empty(t, keys[1].RevokedAt)
+	}
+}
+
+// Тестируем токены: обмен ключа на пару токенов, запросы с токеном доступа и ротацию токена обновления.
+func TestAccessTokens(t *testing.T) {
+	userID := createTestUser(t)
+	key := apiKeyOf(userID)
+
+	issue := func(req token.Request) (int, token.Response) {
+		body, _ := json.Marshal(req)
+		resp := doRequest(t, http.MethodPost, "/token", body)
+		defer resp.Body.Close()
+
+		var tokenResp token.Response
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&tokenResp))
+		return resp.StatusCode, tokenResp
+	}
+
+	code, pair := issue(token.Request{GrantType: token.GrantAPIKey, ApiKey: key})
+	if !assert.Equal(t, http.StatusOK, code) {
+		t.FailNow()
+	}
+	assert.Equal(t, "Bearer", pair.TokenType)
+	assert.Equal(t, int64(15*60), pair.ExpiresIn)
+
+	body, _ := json.Marshal(createEvent.Request{Date: "2026-03-01", Text: "Token party"})
+	resp := send(t, http.MethodPost, "/create_event", body, pair.AccessToken)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	dayBody, _ := json.Marshal(getEvents.Request{Date: "2026-03-01"})
+	resp = send(t, http.MethodGet, "/events_for_day", dayBody, pair.AccessToken)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var eventsResp getEvents.Response
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&eventsResp))
+	if assert.Len(t, eventsResp.Events, 1) {
+		assert.Equal(t, "Token party", eventsResp.Events[0].Text)
+	}
+
+	// Токен обновления одноразовый: после ротации старый отклоняется.
+	code, next := issue(token.Request{GrantType: token.GrantRefreshToken, RefreshToken: pair.RefreshToken})
+	assert.Equal(t, http.StatusOK, code)
+	assert.NotEqual(t, pair.RefreshToken, next.RefreshToken)
+
+	code, _ = issue(token.Request{GrantType: token.GrantRefreshToken, RefreshToken: pair.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, _ = issue(token.Request{GrantType: token.GrantAPIKey, ApiKey: "evs_unknown"})
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, _ = issue(token.Request{GrantType: "password"})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Просроченный токен, токен с чужой подписью и испорченный токен отклоняются.
+	tokens, err := jwtauth.New(testJWT)
+	assert.NoError(t, err)
+	expired, _, err := tokens.IssueAccess(userID, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+
+	foreignCfg := testJWT
+	foreignCfg.SigningKeys = []config.SigningKey{{ID: "test", Secret: "another-signing-secret-of-32-bytes!"}}
+	foreign, err := jwtauth.New(foreignCfg)
+	assert.NoError(t, err)
+	forged, _, err := foreign.IssueAccess(userID, time.Now())
+	assert.NoError(t, err)
+
+	for _, bad := range []string{expired, forged, next.AccessToken + "x"} {
+		resp := send(t, http.MethodGet, "/events_for_day", dayBody, bad)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	// Отзыв ключа закрывает и выданные по нему токены обновления.
+	keys := func() []apiKey.KeyResponse {
+		resp := doRequestAs(t, userID, http.MethodGet, "/api_keys", nil)
+		defer resp.Body.Close()
+
+		var listResp apiKey.ListResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&listResp))
+		return listResp.Keys
+	}()
+	if !assert.Len(t, keys, 1) {
+		t.FailNow()
+	}
+
+	revokeBody, _ := json.Marshal(apiKey.RevokeRequest{KeyId: keys[0].KeyId})
+	resp = send(t, http.MethodPost, "/revoke_api_key", revokeBody, next.AccessToken)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	code, _ = issue(token.Request{GrantType: token.GrantRefreshToken, RefreshToken: next.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+// Тестируем коды ошибок: несуществующее событие — 404, чужое — 403.
+func TestEventErrors(t *testing.T) {
+	userID := createTestUser(t)
+	otherID := createTestUser(t)
+	eventID := createTestEvent(t, userID, "2025-11-10", "Private")
+
+	update := func(userID, eventID int64) int {
+		body, _ := json.Marshal(updateEvent.Request{EventId: eventID, Date: "2025-11-11", Text: "Changed"})
+		resp := doRequestAs(t, userID, http.MethodPost, "/update_event", body)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	remove := func(userID, eventID int64) int {
+		body, _ := json.Marshal(deleteEvent.Request{EventId: eventID})
+		resp := doRequestAs(t, userID, http.MethodPost, "/delete_event", body)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusNotFound, update(userID, 1<<40))
+	assert.Equal(t, http.StatusNotFound, remove(userID, 1<<40))
+	assert.Equal(t, http.StatusForbidden, update(otherID, eventID))
+	assert.Equal(t, http.StatusForbidden, remove(otherID, eventID))
+
+	assert.Equal(t, http.StatusOK, remove(userID, eventID))
+	// Повторное удаление не притворяется успешным.
+	assert.Equal(t, http.StatusNotFound, remove(userID, eventID))
+}
+
+// Тестируем профиль пользователя: создание с профилем, чтение, изменение и удаление вместе с событиями.
+func TestUserProfile(t *testing.T) {
+	suffix := time.Now().UnixNano()
+	email := fmt.Sprintf("anna-%d@example.com", suffix)
+
+	body, _ := json.Marshal(user.Request{Name: "Anna", Email: email, TimeZone: "Europe/Moscow", Locale: "ru-RU"})
+	resp := doRequest(t, http.MethodPost, "/create_user", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var created user.Response
+	if !assert.NoError(t, json.NewDecoder(resp.Body).Decode(&created)) {
+		t.FailNow()
+	}
+	userID := created.UserId
+	rememberAPIKey(userID, created.ApiKey)
+
+	getUser := func(userID int64) (int, user.ProfileResponse) {
+		resp := doRequestAs(t, userID, http.MethodGet, "/get_user", nil)
+		defer resp.Body.Close()
+
+		var userResp user.UserResponse
+		_ = json.NewDecoder(resp.Body).Decode(&userResp)
+		return resp.StatusCode, userResp.User
+	}
+
+	status, profile := getUser(userID)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "Anna", profile.Name)
+	assert.Equal(t, email, profile.Email)
+	assert.Equal(t, "Europe/Moscow", profile.TimeZone)
+	assert.Equal(t, "ru-RU", profile.Locale)
+	assert.NotEmpty(t, profile.CreatedAt)
+
+	// Пользователь без профиля получает значения по умолчанию.
+	status, profile = getUser(createTestUser(t))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "UTC", profile.TimeZone)
+	assert.Equal(t, "en", profile.Locale)
+
+	body, _ = json.Marshal(user.UpdateRequest{Name: "Anna K."})
+	resp = doRequestAs(t, userID, http.MethodPost, "/update_user", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	status, profile = getUser(userID)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "Anna K.", profile.Name)
+	assert.Equal(t, email, profile.Email)
+
+	// Email одного пользователя не может занять другой.
+	otherID := createTestUser(t)
+	body, _ = json.Marshal(user.UpdateRequest{Email: strings.ToUpper(email)})
+	resp = doRequestAs(t, otherID, http.MethodPost, "/update_user", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	eventID := createTestEvent(t, userID, "2025-09-01", "Dentist")
+
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_user", nil)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Ключи удаляются вместе с пользователем, дальше он не проходит аутентификацию.
+	status, _ = getUser(userID)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	resp = doRequestAs(t, userID, http.MethodGet, fmt.Sprintf("/event_history?event_id=%d", eventID), nil)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_user", nil)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+// testAPIKeys — ключи, выданные тестовым пользователям при создании.
+var (
+	testAPIKeysMu sync.Mutex
+	testAPIKeys   = make(map[int64]string)
+)
+
+func rememberAPIKey(userID int64, key string) {
+	testAPIKeysMu.Lock()
+	defer testAPIKeysMu.Unlock()
+
+	testAPIKeys[userID] = key
+}
+
+func apiKeyOf(userID int64) string {
+	testAPIKeysMu.Lock()
+	defer testAPIKeysMu.Unlock()
+
+	return testAPIKeys[userID]
+}
+
+// doRequest отправляет запрос без ключа, doRequestAs — от имени пользователя userID.
+func doRequest(t *testing.T, method, path string, body []byte) *http.Response {
+	t.Helper()
+
+	return send(t, method, path, body, "")
+}
+
+func doRequestAs(t *testing.T, userID int64, method, path string, body []byte) *http.Response {
+	t.Helper()
+
+	return send(t, method, path, body, apiKeyOf(userID))
+}
+
+func send(t *testing.T, method, path string, body []byte, key string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, "http://"+testServerAddr+path, bytes.NewReader(body))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return resp
+}
+
+func createTestUser(t *testing.T) int64 {
+	t.Helper()
+
+	resp := doRequest(t, http.MethodPost, "/create_user", []byte(`{}`))
+	defer resp.Body.Close()
+
+	var userResp user.Response
+	err := json.NewDecoder(resp.Body).Decode(&userResp)
+	if !assert.NoError(t, err) || !assert.True(t, userResp.UserId > 0) {
+		t.FailNow()
+	}
+	rememberAPIKey(userResp.UserId, userResp.ApiKey)
+
+	return userResp.UserId
+}
+
+// defaultCalendarOf возвращает ID календаря пользователя по умолчанию.
+func defaultCalendarOf(t *testing.T, userID int64) int64 {
+	t.Helper()
+
+	resp := doRequestAs(t, userID, http.MethodGet, "/calendars", nil)
+	defer resp.Body.Close()
+
+	var listResp calendar.ListResponse
+	if !assert.NoError(t, json.NewDecoder(resp.Body).Decode(&listResp)) {
+		t.FailNow()
+	}
+	for _, c := range listResp.Calendars {
+		if c.Default {
+			return c.CalendarId
+		}
+	}
+
+	t.Fatalf("user %d has no default calendar", userID)
+	return 0
+}
+
+func createTestEvent(t *testing.T, userID int64, date, text string) int64 {
+	t.Helper()
+
+	body, _ := json.Marshal(createEvent.Request{
+		Date: date,
+		Text: text,
+	})
+	resp := doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+	defer resp.Body.Close()
+
+	var eventResp createEvent.Response
+	err := json.NewDecoder(resp.Body).Decode(&eventResp)
+	if !assert.NoError(t, err) || !assert.True(t, eventResp.EventId > 0) {
+		t.FailNow()
+	}
+
+	return eventResp.EventId
+}
+
+// Тестируем событие со временем, которое переходит через полночь и видно в обоих днях.
+func TestTimedEventSpansDays(t *testing.T) {
+	userID := createTestUser(t)
+
+	body, _ := json.Marshal(createEvent.Request{
+		Text:      "Night deploy",
+		StartTime: "2025-10-10T22:00",
+		EndTime:   "2025-10-11T02:00",
+		TimeZone:  "Europe/Moscow",
+	})
+	resp := doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var eventResp createEvent.Response
+	err := json.NewDecoder(resp.Body).Decode(&eventResp)
+	assert.NoError(t, err)
+
+	for _, day := range []string{"2025-10-10", "2025-10-11"} {
+		dayBody, _ := json.Marshal(getEvents.Request{Date: day})
+		resp := doRequestAs(t, userID, http.MethodGet, "/events_for_day", dayBody)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var eventsResp getEvents.Response
+		err := json.NewDecoder(resp.Body).Decode(&eventsResp)
+		assert.NoError(t, err)
+		assert.Equal(t, []getEvents.EventResponse{{
+			EventId:    eventResp.EventId,
+			Date:       "2025-10-10",
+			Text:       "Night deploy",
+			StartTime:  "2025-10-10T22:00:00+03:00",
+			EndTime:    "2025-10-11T02:00:00+03:00",
+			Version:    1,
+			ETag:       `"1"`,
+			TimeZone:   "Europe/Moscow",
+			CalendarId: defaultCalendarOf(t, userID),
+		}}, eventsResp.Events, day)
+	}
+}
+
+// Тестируем повторяющееся событие: в выборку за неделю попадает каждое повторение.
+func TestRecurringEventOccurrences(t *testing.T) {
+	userID := createTestUser(t)
+
+	body, _ := json.Marshal(createEvent.Request{
+		Text:       "Standup",
+		StartTime:  "2025-09-01T10:00",
+		EndTime:    "2025-09-01T10:15",
+		TimeZone:   "Europe/Moscow",
+		Recurrence: "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=6",
+	})
+	resp := doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	weekBody, _ := json.Marshal(getEvents.Request{Date: "2025-09-08"})
+	resp = doRequestAs(t, userID, http.MethodGet, "/events_for_week", weekBody)
+	defer resp.Body.Close()
+
+	var eventsResp getEvents.Response
+	err := json.NewDecoder(resp.Body).Decode(&eventsResp)
+	assert.NoError(t, err)
+
+	var starts []string
+	for _, e := range eventsResp.Events {
+		starts = append(starts, e.StartTime)
+	}
+	assert.Equal(t, []string{"2025-09-08T10:00:00+03:00", "2025-09-11T10:00:00+03:00"}, starts)
+
+	// COUNT=6 заканчивает серию 18 сентября.
+	monthBody, _ := json.Marshal(getEvents.Request{Date: "2025-09-01"})
+	resp = doRequestAs(t, userID, http.MethodGet, "/events_for_month", monthBody)
+	defer resp.Body.Close()
+
+	eventsResp = getEvents.Response{}
+	err = json.NewDecoder(resp.Body).Decode(&eventsResp)
+	assert.NoError(t, err)
+	if assert.Len(t, eventsResp.Events, 6) {
+		assert.Equal(t, "2025-09-18", eventsResp.Events[5].Date)
+	}
+}
+
+// Тестируем исключения серии: перенос и отмену одного повторения и изменение "это и следующие".
+func TestRecurringEventExceptions(t *testing.T) {
+	userID := createTestUser(t)
+
+	body, _ := json.Marshal(createEvent.Request{
+		Text:       "Standup",
+		StartTime:  "2025-09-01T10:00",
+		EndTime:    "2025-09-01T10:15",
+		TimeZone:   "Europe/Moscow",
+		Recurrence: "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=6",
+	})
+	resp := doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+	defer resp.Body.Close()
+
+	var eventResp createEvent.Response
+	err := json.NewDecoder(resp.Body).Decode(&eventResp)
+	if !assert.NoError(t, err) || !assert.True(t, eventResp.EventId > 0) {
+		t.FailNow()
+	}
+	seriesID := eventResp.EventId
+
+	monthEvents := func() []getEvents.EventResponse {
+		monthBody, _ := json.Marshal(getEvents.Request{Date: "2025-09-01"})
+		resp := doRequestAs(t, userID, http.MethodGet, "/events_for_month", monthBody)
+		defer resp.Body.Close()
+
+		var eventsResp getEvents.Response
+		err := json.NewDecoder(resp.Body).Decode(&eventsResp)
+		assert.NoError(t, err)
+		return eventsResp.Events
+	}
+
+	// Четверг 11 сентября переносим на пятницу, понедельник 8 сентября отменяем.
+	body, _ = json.Marshal(updateEvent.Request{
+		EventId:        seriesID,
+		Text:           "Standup (moved)",
+		StartTime:      "2025-09-12T10:00",
+		EndTime:        "2025-09-12T10:15",
+		TimeZone:       "Europe/Moscow",
+		Scope:          "this",
+		OccurrenceDate: "2025-09-11",
+	})
+	resp = doRequestAs(t, userID, http.MethodPost, "/update_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, _ = json.Marshal(deleteEvent.Request{
+		EventId:        seriesID,
+		Scope:          "this",
+		OccurrenceDate: "2025-09-08",
+	})
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	weekBody, _ := json.Marshal(getEvents.Request{Date: "2025-09-08"})
+	resp = doRequestAs(t, userID, http.MethodGet, "/events_for_week", weekBody)
+	defer resp.Body.Close()
+
+	var eventsResp getEvents.Response
+	err = json.NewDecoder(resp.Body).Decode(&eventsResp)
+	assert.NoError(t, err)
+	if assert.Len(t, eventsResp.Events, 1) {
+		assert.Equal(t, "2025-09-12", eventsResp.Events[0].Date)
+		assert.Equal(t, "2025-09-11", eventsResp.Events[0].OccurrenceDate)
+		assert.Equal(t, "Standup (moved)", eventsResp.Events[0].Text)
+	}
+
+	// 10 сентября повторения нет.
+	body, _ = json.Marshal(deleteEvent.Request{
+		EventId:        seriesID,
+		Scope:          "this",
+		OccurrenceDate: "2025-09-10",
+	})
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// С 15 сентября стендап переезжает на 11:00: оставшиеся два повторения становятся новой серией.
+	body, _ = json.Marshal(updateEvent.Request{
+		EventId:        seriesID,
+		Text:           "Standup",
+		StartTime:      "2025-09-15T11:00",
+		EndTime:        "2025-09-15T11:15",
+		TimeZone:       "Europe/Moscow",
+		Scope:          "following",
+		OccurrenceDate: "2025-09-15",
+	})
+	resp = doRequestAs(t, userID, http.MethodPost, "/update_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var updateResp updateEvent.Response
+	err = json.NewDecoder(resp.Body).Decode(&updateResp)
+	assert.NoError(t, err)
+	assert.NotEqual(t, seriesID, updateResp.EventId)
+
+	var starts []string
+	for _, e := range monthEvents() {
+		starts = append(starts, e.StartTime)
+	}
+	assert.Equal(t, []string{
+		"2025-09-01T10:00:00+03:00",
+		"2025-09-04T10:00:00+03:00",
+		"2025-09-12T10:00:00+03:00",
+		"2025-09-15T11:00:00+03:00",
+		"2025-09-18T11:00:00+03:00",
+	}, starts)
+
+	// Удаление "это и следующие" с первого повторения удаляет новую серию целиком.
+	body, _ = json.Marshal(deleteEvent.Request{
+		EventId:        updateResp.EventId,
+		Scope:          "following",
+		OccurrenceDate: "2025-09-15",
+	})
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Len(t, monthEvents(), 3)
+
+	// Переименование всей серии без rrule сохраняет правило и исключения.
+	body, _ = json.Marshal(updateEvent.Request{
+		EventId:   seriesID,
+		Text:      "Daily standup",
+		StartTime: "2025-09-01T10:00",
+		EndTime:   "2025-09-01T10:15",
+		TimeZone:  "Europe/Moscow",
+	})
+	resp = doRequestAs(t, userID, http.MethodPost, "/update_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var texts []string
+	for _, e := range monthEvents() {
+		texts = append(texts, e.Date+" "+e.Text)
+		assert.NotEmpty(t, e.Recurrence)
+	}
+	assert.Equal(t, []string{"2025-09-01 Daily standup", "2025-09-04 Daily standup", "2025-09-12 Standup (moved)"}, texts)
+
+	// Перенос всей серии удаляет исключения, исходных дат которых в ней больше нет.
+	body, _ = json.Marshal(createEvent.Request{Date: "2025-10-01", Text: "Sync", Recurrence: "FREQ=DAILY;COUNT=5"})
+	resp = doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+	defer resp.Body.Close()
+	eventResp = createEvent.Response{}
+	if !assert.NoError(t, json.NewDecoder(resp.Body).Decode(&eventResp)) {
+		t.FailNow()
+	}
+	syncID := eventResp.EventId
+
+	post := func(path string, req interface{}) {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, userID, http.MethodPost, path, body)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+	octoberEvents := func() []string {
+		monthBody, _ := json.Marshal(getEvents.Request{Date: "2025-10-01"})
+		resp := doRequestAs(t, userID, http.MethodGet, "/events_for_month", monthBody)
+		defer resp.Body.Close()
+
+		var eventsResp getEvents.Response
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&eventsResp))
+		var res []string
+		for _, e := range eventsResp.Events {
+			assert.Equal(t, "FREQ=DAILY;COUNT=5", e.Recurrence)
+			res = append(res, e.Date+" "+e.Text)
+		}
+		return res
+	}
+
+	post("/update_event", updateEvent.Request{EventId: syncID, Date: "2025-10-03", Text: "Sync (moved)", Scope: "this", OccurrenceDate: "2025-10-02"})
+	post("/delete_event", deleteEvent.Request{EventId: syncID, Scope: "this", OccurrenceDate: "2025-10-04"})
+
+	// Сдвиг на день оставляет исключения, даты которых остались в серии.
+	post("/update_event", updateEvent.Request{EventId: syncID, Date: "2025-10-02", Text: "Sync"})
+	assert.Equal(t, []string{"2025-10-03 Sync", "2025-10-03 Sync (moved)", "2025-10-05 Sync", "2025-10-06 Sync"}, octoberEvents())
+
+	post("/update_event", updateEvent.Request{EventId: syncID, Date: "2025-10-10", Text: "Sync"})
+	assert.Equal(t, []string{
+		"2025-10-10 Sync", "2025-10-11 Sync", "2025-10-12 Sync", "2025-10-13 Sync", "2025-10-14 Sync",
+	}, octoberEvents())
+
+	// Прежние даты больше не повторения серии: отменить их нельзя.
+	body, _ = json.Marshal(deleteEvent.Request{EventId: syncID, Scope: "this", OccurrenceDate: "2025-10-04"})
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+// Тестируем теги: фильтры "любой из" и "все сразу", замену тегов и список тегов с количеством.
+func TestEventTags(t *testing.T) {
+	userID := createTestUser(t)
+
+	create := func(req createEvent.Request) int64 {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+		defer resp.Body.Close()
+
+		var eventResp createEvent.Response
+		err := json.NewDecoder(resp.Body).Decode(&eventResp)
+		if !assert.NoError(t, err) || !assert.True(t, eventResp.EventId > 0) {
+			t.FailNow()
+		}
+		return eventResp.EventId
+	}
+
+	create(createEvent.Request{Date: "2025-06-02", Text: "Planning", Tags: []string{"Work"}})
+	reviewID := create(createEvent.Request{Date: "2025-06-03", Text: "Incident review", Tags: []string{"work", "oncall"}})
+	create(createEvent.Request{Date: "2025-06-04", Text: "Dentist", Tags: []string{"personal"}})
+	create(createEvent.Request{Date: "2025-06-05", Text: "Oncall shift", Tags: []string{"oncall"}, Recurrence: "FREQ=WEEKLY;COUNT=2"})
+
+	monthTexts := func(tags []string, match string) []string {
+		body, _ := json.Marshal(getEvents.Request{Date: "2025-06-01", Tags: tags, TagMatch: match})
+		resp := doRequestAs(t, userID, http.MethodGet, "/events_for_month", body)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var eventsResp getEvents.Response
+		err := json.NewDecoder(resp.Body).Decode(&eventsResp)
+		assert.NoError(t, err)
+
+		var texts []string
+		for _, e := range eventsResp.Events {
+			texts = append(texts, e.Text)
+		}
+		return texts
+	}
+
+	assert.Equal(t, []string{"Planning", "Incident review"}, monthTexts([]string{"work"}, ""))
+	assert.Equal(t, []string{"Incident review", "Oncall shift", "Oncall shift"}, monthTexts([]string{"oncall"}, "any"))
+	assert.Equal(t, []string{"Incident review"}, monthTexts([]string{"work", "oncall"}, "all"))
+	assert.Len(t, monthTexts(nil, ""), 5)
+
+	// Пустой массив тегов снимает все теги с события.
+	body, _ := json.Marshal(map[string]interface{}{
+		"event_id": reviewID,
+		"date":     "2025-06-03",
+		"text":     "Incident review",
+		"tags":     []string{},
+	})
+	resp := doRequestAs(t, userID, http.MethodPost, "/update_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, []string{"Planning"}, monthTexts([]string{"work"}, ""))
+
+	resp = doRequestAs(t, userID, http.MethodGet, "/tags", nil)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var tagsResp listTags.Response
+	err := json.NewDecoder(resp.Body).Decode(&tagsResp)
+	assert.NoError(t, err)
+	assert.Equal(t, []listTags.TagResponse{
+		{Name: "oncall", Count: 1},
+		{Name: "personal", Count: 1},
+		{Name: "work", Count: 1},
+	}, tagsResp.Tags)
+}
+
+// Тестируем календари: календарь по умолчанию, часовой пояс календаря, фильтр по календарям и удаление.
+func TestCalendars(t *testing.T) {
+	body, _ := json.Marshal(user.Request{TimeZone: "Europe/Moscow"})
+	resp := doRequest(t, http.MethodPost, "/create_user", body)
+	defer resp.Body.Close()
+
+	var created user.Response
+	if !assert.NoError(t, json.NewDecoder(resp.Body).Decode(&created)) {
+		t.FailNow()
+	}
+	userID := created.UserId
+	rememberAPIKey(userID, created.ApiKey)
+
+	listCalendars := func() []calendar.CalendarResponse {
+		resp := doRequestAs(t, userID, http.MethodGet, "/calendars", nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var listResp calendar.ListResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&listResp))
+		return listResp.Calendars
+	}
+
+	// Календарь по умолчанию создается вместе с пользователем в его часовом поясе.
+	calendars := listCalendars()
+	if !assert.Len(t, calendars, 1) {
+		t.FailNow()
+	}
+	defaultID := calendars[0].CalendarId
+	assert.Equal(t, models.DefaultCalendarName, calendars[0].Name)
+	assert.Equal(t, "Europe/Moscow", calendars[0].TimeZone)
+	assert.True(t, calendars[0].Default)
+
+	createCalendar := func(req calendar.Request) (int, int64) {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, userID, http.MethodPost, "/create_calendar", body)
+		defer resp.Body.Close()
+
+		var calendarResp calendar.Response
+		_ = json.NewDecoder(resp.Body).Decode(&calendarResp)
+		return resp.StatusCode, calendarResp.CalendarId
+	}
+
+	status, workID := createCalendar(calendar.Request{Name: "Work", Color: "#FF8800", TimeZone: "Asia/Tokyo"})
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = createCalendar(calendar.Request{Name: "Work"})
+	assert.Equal(t, http.StatusConflict, status)
+
+	calendars = listCalendars()
+	if !assert.Len(t, calendars, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, calendar.CalendarResponse{
+		CalendarId: workID,
+		Name:       "Work",
+		Color:      "#ff8800",
+		TimeZone:   "Asia/Tokyo",
+		CreatedAt:  calendars[1].CreatedAt,
+	}, calendars[1])
+
+	create := func(req createEvent.Request) (int, int64) {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+		defer resp.Body.Close()
+
+		var eventResp createEvent.Response
+		_ = json.NewDecoder(resp.Body).Decode(&eventResp)
+		return resp.StatusCode, eventResp.EventId
+	}
+
+	// Событие со временем без часового пояса получает часовой пояс календаря.
+	status, standupID := create(createEvent.Request{StartTime: "2025-07-01T10:00", EndTime: "2025-07-01T10:15", Text: "Standup", CalendarId: workID})
+	assert.Equal(t, http.StatusOK, status)
+	status, seriesID := create(createEvent.Request{Date: "2025-07-07", Text: "Sprint review", Recurrence: "FREQ=WEEKLY;COUNT=2", CalendarId: workID})
+	assert.Equal(t, http.StatusOK, status)
+	status, dentistID := create(createEvent.Request{Date: "2025-07-02", Text: "Dentist"})
+	assert.Equal(t, http.StatusOK, status)
+
+	// Календарь другого пользователя недоступен.
+	otherID := createTestUser(t)
+	body, _ = json.Marshal(createEvent.Request{Date: "2025-07-03", Text: "Intrusion", CalendarId: workID})
+	resp = doRequestAs(t, otherID, http.MethodPost, "/create_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Измененное повторение остается в календаре серии.
+	body, _ = json.Marshal(updateEvent.Request{
+		EventId: seriesID, Date: "2025-07-15", Text: "Sprint review (moved)", Scope: "this", OccurrenceDate: "2025-07-14",
+	})
+	resp = doRequestAs(t, userID, http.MethodPost, "/update_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Одно повторение нельзя перенести в другой календарь.
+	body, _ = json.Marshal(updateEvent.Request{
+		EventId: seriesID, Date: "2025-07-07", Text: "Sprint review", Scope: "this", OccurrenceDate: "2025-07-07", CalendarId: defaultID,
+	})
+	resp = doRequestAs(t, userID, http.MethodPost, "/update_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	monthEvents := func(calendars []int64) []getEvents.EventResponse {
+		body, _ := json.Marshal(getEvents.Request{Date: "2025-07-01", Calendars: calendars})
+		resp := doRequestAs(t, userID, http.MethodGet, "/events_for_month", body)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var eventsResp getEvents.Response
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&eventsResp))
+		return eventsResp.Events
+	}
+	texts := func(events []getEvents.EventResponse) []string {
+		var texts []string
+		for _, e := range events {
+			texts = append(texts, e.Text)
+		}
+		return texts
+	}
+
+	work := monthEvents([]int64{workID})
+	assert.Equal(t, []string{"Standup", "Sprint review", "Sprint review (moved)"}, texts(work))
+	if assert.NotEmpty(t, work) {
+		assert.Equal(t, "Asia/Tokyo", work[0].TimeZone)
+		assert.Equal(t, "2025-07-01T10:00:00+09:00", work[0].StartTime)
+		assert.Equal(t, workID, work[0].CalendarId)
+	}
+	assert.Equal(t, []string{"Dentist"}, texts(monthEvents([]int64{defaultID})))
+	assert.Len(t, monthEvents(nil), 4)
+
+	listTexts := func(query string) []string {
+		resp := doRequestAs(t, userID, http.MethodGet, "/events?from=2025-07-01&to=2025-07-31"+query, nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var listResp getEvents.ListResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&listResp))
+		return texts(listResp.Events)
+	}
+
+	assert.Equal(t, []string{"Standup", "Sprint review", "Sprint review (moved)"}, listTexts(fmt.Sprintf("&calendars=%d", workID)))
+	assert.Equal(t, []string{"Dentist"}, listTexts(fmt.Sprintf("&calendars=%d", defaultID)))
+
+	// Событие переносится в другой календарь целиком.
+	body, _ = json.Marshal(updateEvent.Request{EventId: dentistID, Date: "2025-07-02", Text: "Dentist", CalendarId: workID})
+	resp = doRequestAs(t, userID, http.MethodPost, "/update_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, listTexts(fmt.Sprintf("&calendars=%d", defaultID)))
+
+	body, _ = json.Marshal(calendar.UpdateRequest{CalendarId: workID, Name: "Job", Color: "#00AA00"})
+	resp = doRequestAs(t, userID, http.MethodPost, "/update_calendar", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var updated calendar.UpdateResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&updated))
+	assert.Equal(t, "Job", updated.Calendar.Name)
+	assert.Equal(t, "#00aa00", updated.Calendar.Color)
+	assert.Equal(t, "Asia/Tokyo", updated.Calendar.TimeZone)
+
+	deleteCalendar := func(asUserID, calendarID int64) int {
+		body, _ := json.Marshal(calendar.DeleteRequest{CalendarId: calendarID})
+		resp := doRequestAs(t, asUserID, http.MethodPost, "/delete_calendar", body)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusConflict, deleteCalendar(userID, defaultID))
+	assert.Equal(t, http.StatusNotFound, deleteCalendar(otherID, workID))
+
+	// События удаленного календаря уходят в корзину с записью в истории.
+	assert.Equal(t, http.StatusOK, deleteCalendar(userID, workID))
+	assert.Empty(t, monthEvents(nil))
+	assert.Len(t, listCalendars(), 1)
+
+	resp = doRequestAs(t, userID, http.MethodGet, fmt.Sprintf("/event_history?event_id=%d", standupID), nil)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var historyResp eventHistory.Response
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&historyResp))
+	if assert.NotEmpty(t, historyResp.Revisions) {
+		assert.Equal(t, "delete", historyResp.Revisions[len(historyResp.Revisions)-1].Action)
+	}
+
+	resp = doRequestAs(t, userID, http.MethodGet, "/trash", nil)
+	defer resp.Body.Close()
+	var trashResp listTrash.Response
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&trashResp))
+	var trashed []int64
+	for _, e := range trashResp.Events {
+		trashed = append(trashed, e.EventId)
+	}
+	assert.ElementsMatch(t, []int64{standupID, seriesID, dentistID}, trashed)
+
+	// Восстановленное событие попадает в календарь по умолчанию.
+	body, _ = json.Marshal(restoreEvent.Request{EventId: standupID})
+	resp = doRequestAs(t, userID, http.MethodPost, "/restore_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"Standup"}, listTexts(fmt.Sprintf("&calendars=%d", defaultID)))
+}
+
+// Тестируем, что удаление календаря сообщает об удалении каждого его события вебхукам и через outbox.
+func TestDeleteCalendarNotifies(t *testing.T) {
+	ctx := context.Background()
+	userID := createTestUser(t)
+
+	var mu sync.Mutex
+	var deleted []float64
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if eventID, ok := payload["event_id"].(float64); ok {
+			deleted = append(deleted, eventID)
+		}
+	}))
+	defer receiver.Close()
+
+	post := func(path string, req interface{}) {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, userID, http.MethodPost, path, body)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+
+	body, _ := json.Marshal(calendar.Request{Name: "Work"})
+	resp := doRequestAs(t, userID, http.MethodPost, "/create_calendar", body)
+	defer resp.Body.Close()
+	var calendarResp calendar.Response
+	if !assert.NoError(t, json.NewDecoder(resp.Body).Decode(&calendarResp)) {
+		t.FailNow()
+	}
+	workID := calendarResp.CalendarId
+
+	var eventIDs []int64
+	for _, text := range []string{"Standup", "Retro"} {
+		body, _ := json.Marshal(createEvent.Request{Date: "2025-12-08", Text: text, CalendarId: workID})
+		resp := doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+		defer resp.Body.Close()
+		var eventResp createEvent.Response
+		if !assert.NoError(t, json.NewDecoder(resp.Body).Decode(&eventResp)) {
+			t.FailNow()
+		}
+		eventIDs = append(eventIDs, eventResp.EventId)
+	}
+	// Событие уже в корзине: о его удалении сообщено раньше.
+	trashedID := createTestEvent(t, userID, "2025-12-09", "Old")
+	post("/update_event", updateEvent.Request{EventId: trashedID, Date: "2025-12-09", Text: "Old", CalendarId: workID})
+	post("/delete_event", deleteEvent.Request{EventId: trashedID})
+
+	post("/create_webhook", webhook.Request{URL: receiver.URL, Secret: "calendar-deletions-secret", EventTypes: []string{models.WebhookEventDeleted}})
+
+	// Отправляем записи, оставленные до удаления календаря.
+	for {
+		sent, err := testDB.RelayOutbox(ctx, 100, func(models.OutboxRecord) error { return nil })
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		if sent == 0 {
+			break
+		}
+	}
+
+	post("/delete_calendar", calendar.DeleteRequest{CalendarId: workID})
+
+	var records []models.OutboxRecord
+	_, err := testDB.RelayOutbox(ctx, 100, func(record models.OutboxRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	assert.NoError(t, err)
+	var outboxDeleted []int64
+	for _, record := range records {
+		if record.Type == models.WebhookEventDeleted {
+			outboxDeleted = append(outboxDeleted, record.AggregateID)
+		}
+	}
+	assert.Equal(t, eventIDs, outboxDeleted)
+
+	senderCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		webhooksender.Run(senderCtx, slog.New(slog.NewTextHandler(io.Discard, nil)), testDB, config.Webhooks{
+			PollInterval:         10 * time.Millisecond,
+			BatchSize:            100,
+			Timeout:              2 * time.Second,
+			MaxAttempts:          2,
+			RetryInterval:        10 * time.Millisecond,
+			MaxRetryInterval:     10 * time.Millisecond,
+			DisableAfter:         2,
+			AllowPrivateNetworks: true,
+		})
+	}()
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(deleted) >= len(eventIDs)
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.ElementsMatch(t, []float64{float64(eventIDs[0]), float64(eventIDs[1])}, deleted)
+}
+
+// Тестируем общие календари: только занятость, чтение, запись и отзыв доступа.
+func TestCalendarSharing(t *testing.T) {
+	ownerID := createTestUser(t)
+	viewerID := createTestUser(t)
+	readerID := createTestUser(t)
+	editorID := createTestUser(t)
+	strangerID := createTestUser(t)
+	defaultID := defaultCalendarOf(t, ownerID)
+
+	body, _ := json.Marshal(calendar.Request{Name: "Work"})
+	resp := doRequestAs(t, ownerID, http.MethodPost, "/create_calendar", body)
+	defer resp.Body.Close()
+	var calendarResp calendar.Response
+	if !assert.NoError(t, json.NewDecoder(resp.Body).Decode(&calendarResp)) {
+		t.FailNow()
+	}
+	workID := calendarResp.CalendarId
+
+	create := func(asUserID int64, req createEvent.Request) (int, int64) {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, asUserID, http.MethodPost, "/create_event", body)
+		defer resp.Body.Close()
+
+		var eventResp createEvent.Response
+		_ = json.NewDecoder(resp.Body).Decode(&eventResp)
+		return resp.StatusCode, eventResp.EventId
+	}
+
+	status, standupID := create(ownerID, createEvent.Request{Date: "2025-09-01", Text: "Standup", Tags: []string{"work"}, CalendarId: workID})
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = create(ownerID, createEvent.Request{Date: "2025-09-02", Text: "Dentist", Tags: []string{"health"}})
+	assert.Equal(t, http.StatusOK, status)
+
+	shareCalendar := func(req share.Request) int {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, ownerID, http.MethodPost, "/share_calendar", body)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, shareCalendar(share.Request{CalendarId: defaultID, UserId: viewerID, Access: "freebusy"}))
+	assert.Equal(t, http.StatusOK, shareCalendar(share.Request{CalendarId: workID, UserId: readerID, Access: "read"}))
+	assert.Equal(t, http.StatusOK, shareCalendar(share.Request{CalendarId: workID, UserId: editorID, Access: "write"}))
+	assert.Equal(t, http.StatusBadRequest, shareCalendar(share.Request{CalendarId: workID, UserId: ownerID, Access: "read"}))
+	assert.Equal(t, http.StatusBadRequest, shareCalendar(share.Request{CalendarId: workID, UserId: readerID, Access: "admin"}))
+	assert.Equal(t, http.StatusNotFound, shareCalendar(share.Request{CalendarId: defaultCalendarOf(t, strangerID), UserId: readerID, Access: "read"}))
+
+	listShares := func() []share.ShareResponse {
+		resp := doRequestAs(t, ownerID, http.MethodGet, "/shares", nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var listResp share.ListResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&listResp))
+		return listResp.Shares
+	}
+
+	shares := listShares()
+	if assert.Len(t, shares, 3) {
+		assert.Equal(t, share.ShareResponse{
+			CalendarId: defaultID, UserId: viewerID, Access: "freebusy", CreatedAt: shares[0].CreatedAt,
+		}, shares[0])
+	}
+
+	monthEvents := func(asUserID int64, calendars []int64) (int, []getEvents.EventResponse) {
+		body, _ := json.Marshal(getEvents.Request{Date: "2025-09-01", Calendars: calendars, OwnerId: ownerID})
+		resp := doRequestAs(t, asUserID, http.MethodGet, "/events_for_month", body)
+		defer resp.Body.Close()
+
+		var eventsResp getEvents.Response
+		_ = json.NewDecoder(resp.Body).Decode(&eventsResp)
+		return resp.StatusCode, eventsResp.Events
+	}
+
+	// Доступ к занятости показывает только время событий.
+	status, events := monthEvents(viewerID, nil)
+	assert.Equal(t, http.StatusOK, status)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "2025-09-02", events[0].Date)
+		assert.Empty(t, events[0].Text)
+		assert.Empty(t, events[0].Tags)
+	}
+
+	// Фильтр по тегам не выдает скрытые теги событий.
+	body, _ = json.Marshal(getEvents.Request{Date: "2025-09-01", Tags: []string{"health"}, OwnerId: ownerID})
+	resp = doRequestAs(t, viewerID, http.MethodGet, "/events_for_month", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var taggedResp getEvents.Response
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&taggedResp))
+	assert.Empty(t, taggedResp.Events)
+
+	status, events = monthEvents(readerID, nil)
+	assert.Equal(t, http.StatusOK, status)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, "Standup", events[0].Text)
+		assert.Equal(t, []string{"work"}, events[0].Tags)
+	}
+
+	status, _ = monthEvents(readerID, []int64{defaultID})
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = monthEvents(strangerID, nil)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	resp = doRequestAs(t, readerID, http.MethodGet, fmt.Sprintf("/events?from=2025-09-01&to=2025-09-30&owner_id=%d", ownerID), nil)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var listResp getEvents.ListResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&listResp))
+	if assert.Len(t, listResp.Events, 1) {
+		assert.Equal(t, standupID, listResp.Events[0].EventId)
+	}
+
+	updateStandup := func(asUserID int64, text string) int {
+		body, _ := json.Marshal(updateEvent.Request{EventId: standupID, Date: "2025-09-01", Text: text})
+		resp := doRequestAs(t, asUserID, http.MethodPost, "/update_event", body)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Менять события может только пользователь с доступом на запись.
+	assert.Equal(t, http.StatusForbidden, updateStandup(readerID, "Reader standup"))
+	assert.Equal(t, http.StatusForbidden, updateStandup(viewerID, "Viewer standup"))
+	assert.Equal(t, http.StatusOK, updateStandup(editorID, "Daily standup"))
+
+	status, planningID := create(editorID, createEvent.Request{Date: "2025-09-03", Text: "Planning", CalendarId: workID})
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = create(readerID, createEvent.Request{Date: "2025-09-04", Text: "Intrusion", CalendarId: workID})
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, events = monthEvents(ownerID, []int64{workID})
+	assert.Equal(t, http.StatusOK, status)
+	var texts []string
+	for _, e := range events {
+		texts = append(texts, e.Text)
+	}
+	assert.Equal(t, []string{"Daily standup", "Planning"}, texts)
+
+	body, _ = json.Marshal(deleteEvent.Request{EventId: planningID})
+	resp = doRequestAs(t, editorID, http.MethodPost, "/delete_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Удаленное событие попадает в корзину владельца.
+	resp = doRequestAs(t, ownerID, http.MethodGet, "/trash", nil)
+	defer resp.Body.Close()
+	var trashResp listTrash.Response
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&trashResp))
+	if assert.Len(t, trashResp.Events, 1) {
+		assert.Equal(t, planningID, trashResp.Events[0].EventId)
+	}
+
+	revokeShare := func(userID int64) int {
+		body, _ := json.Marshal(share.RevokeRequest{CalendarId: workID, UserId: userID})
+		resp := doRequestAs(t, ownerID, http.MethodPost, "/revoke_share", body)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, revokeShare(editorID))
+	assert.Equal(t, http.StatusNotFound, revokeShare(editorID))
+	assert.Equal(t, http.StatusForbidden, updateStandup(editorID, "Late standup"))
+	assert.Len(t, listShares(), 2)
+}
+
+// Тестируем участников событий: приглашения видны в выборках приглашенных, ответы на них сохраняются.
+func TestAttendees(t *testing.T) {
+	ownerID := createTestUser(t)
+	aliceID := createTestUser(t)
+	bobID := createTestUser(t)
+
+	create := func(req createEvent.Request) (int, int64) {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, ownerID, http.MethodPost, "/create_event", body)
+		defer resp.Body.Close()
+
+		var eventResp createEvent.Response
+		_ = json.NewDecoder(resp.Body).Decode(&eventResp)
+		return resp.StatusCode, eventResp.EventId
+	}
+
+	status, syncID := create(createEvent.Request{
+		Date: "2025-10-06", Text: "Sync", Recurrence: "FREQ=WEEKLY;COUNT=2", Attendees: []int64{bobID, aliceID},
+	})
+	assert.Equal(t, http.StatusOK, status)
+	status, privateID := create(createEvent.Request{Date: "2025-10-07", Text: "Private"})
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = create(createEvent.Request{Date: "2025-10-08", Text: "Self", Attendees: []int64{ownerID}})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = create(createEvent.Request{Date: "2025-10-08", Text: "Ghost", Attendees: []int64{math.MaxInt32}})
+	assert.Equal(t, http.StatusNotFound, status)
+
+	monthEvents := func(userID int64, calendars []int64) []getEvents.EventResponse {
+		body, _ := json.Marshal(getEvents.Request{Date: "2025-10-01", Calendars: calendars})
+		resp := doRequestAs(t, userID, http.MethodGet, "/events_for_month", body)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var eventsResp getEvents.Response
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&eventsResp))
+		return eventsResp.Events
+	}
+
+	// Приглашенный видит все повторения серии, но не другие события владельца.
+	events := monthEvents(aliceID, nil)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "Sync", events[0].Text)
+		assert.Equal(t, "needs-action", events[0].RsvpStatus)
+		assert.Equal(t, []getEvents.AttendeeResponse{
+			{UserId: aliceID, Status: "needs-action"},
+			{UserId: bobID, Status: "needs-action"},
+		}, events[1].Attendees)
+	}
+	assert.Empty(t, monthEvents(aliceID, []int64{defaultCalendarOf(t, aliceID)}))
+
+	rsvp := func(userID, eventID int64, status string) int {
+		body, _ := json.Marshal(respondEvent.Request{EventId: eventID, Status: status})
+		resp := doRequestAs(t, userID, http.MethodPost, "/rsvp", body)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, rsvp(aliceID, syncID, "accepted"))
+	assert.Equal(t, http.StatusNotFound, rsvp(aliceID, privateID, "accepted"))
+	assert.Equal(t, http.StatusBadRequest, rsvp(bobID, syncID, "maybe"))
+
+	events = monthEvents(aliceID, nil)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "accepted", events[0].RsvpStatus)
+		assert.NotEmpty(t, events[0].Attendees[0].RespondedAt)
+	}
+
+	// Владелец видит ответы участников, но сам участником не считается.
+	events = monthEvents(ownerID, nil)
+	if assert.Len(t, events, 3) {
+		assert.Empty(t, events[0].RsvpStatus)
+		assert.Equal(t, "accepted", events[0].Attendees[0].Status)
+		assert.Equal(t, "needs-action", events[0].Attendees[1].Status)
+	}
+
+	// Приглашенный не может менять событие.
+	body, _ := json.Marshal(updateEvent.Request{EventId: syncID, Date: "2025-10-06", Text: "Hijacked"})
+	resp := doRequestAs(t, aliceID, http.MethodPost, "/update_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// Оставшиеся участники сохраняют ответы, исключенные перестают видеть событие.
+	body, _ = json.Marshal(updateEvent.Request{EventId: syncID, Date: "2025-10-06", Text: "Sync", Recurrence: "FREQ=WEEKLY;COUNT=2", Attendees: []int64{aliceID}})
+	resp = doRequestAs(t, ownerID, http.MethodPost, "/update_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Empty(t, monthEvents(bobID, nil))
+	events = monthEvents(aliceID, nil)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "accepted", events[0].RsvpStatus)
+	}
+
+	body, _ = json.Marshal(deleteEvent.Request{EventId: syncID})
+	resp = doRequestAs(t, ownerID, http.MethodPost, "/delete_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Empty(t, monthEvents(aliceID, nil))
+	assert.Equal(t, http.StatusNotFound, rsvp(aliceID, syncID, "declined"))
+}
+
+// Тестируем напоминания: каждое срабатывает один раз перед каждым повторением серии,
+// отмененные повторения и события в корзине напоминаний не получают.
+func TestReminders(t *testing.T) {
+	userID := createTestUser(t)
+
+	create := func(req createEvent.Request) int64 {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var eventResp createEvent.Response
+		_ = json.NewDecoder(resp.Body).Decode(&eventResp)
+		return eventResp.EventId
+	}
+
+	standupID := create(createEvent.Request{
+		Text: "Standup", StartTime: "2099-01-05T09:00:00Z", EndTime: "2099-01-05T09:15:00Z", TimeZone: "UTC",
+		Recurrence: "FREQ=WEEKLY;COUNT=3", Reminders: []int{1440, 10},
+	})
+	// Событие на весь день начинается в полночь часового пояса своего календаря.
+	body, _ := json.Marshal(calendar.Request{Name: "Travel", TimeZone: "Asia/Tokyo"})
+	resp := doRequestAs(t, userID, http.MethodPost, "/create_calendar", body)
+	defer resp.Body.Close()
+	var calendarResp calendar.Response
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&calendarResp))
+	tripID := create(createEvent.Request{Date: "2099-01-07", Text: "Trip", CalendarId: calendarResp.CalendarId, Reminders: []int{60}})
+
+	body, _ = json.Marshal(deleteEvent.Request{EventId: standupID, Scope: "this", OccurrenceDate: "2099-01-12"})
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	monthBody, _ := json.Marshal(getEvents.Request{Date: "2099-01-01"})
+	resp = doRequestAs(t, userID, http.MethodGet, "/events_for_month", monthBody)
+	defer resp.Body.Close()
+	var eventsResp getEvents.Response
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&eventsResp))
+	if assert.Len(t, eventsResp.Events, 3) {
+		assert.Equal(t, []int{10, 1440}, eventsResp.Events[0].Reminders)
+		assert.Equal(t, []int{60}, eventsResp.Events[1].Reminders)
+	}
+
+	// fire забирает сработавшие к now напоминания событий этого теста.
+	fire := func(now string, eventIDs ...int64) []models.Notification {
+		at, _ := time.Parse(time.RFC3339, now)
+		notifications, err := testDB.FireReminders(context.Background(), at, 1000)
+		assert.NoError(t, err)
+
+		var res []models.Notification
+		for _, n := range notifications {
+			if slices.Contains(eventIDs, n.EventID) {
+				res = append(res, n)
+			}
+		}
+		return res
+	}
+	ids := []int64{standupID, tripID}
+
+	assert.Empty(t, fire("2099-01-04T08:59:59Z", ids...))
+
+	fired := fire("2099-01-04T09:00:00Z", ids...)
+	if assert.Len(t, fired, 1) {
+		assert.Equal(t, standupID, fired[0].EventID)
+		assert.Equal(t, userID, fired[0].UserID)
+		assert.Equal(t, "2099-01-05", fired[0].OccurrenceDate)
+		assert.Equal(t, 24*time.Hour, fired[0].Before)
+	}
+	// Сработавшее напоминание больше не возвращается.
+	assert.Empty(t, fire("2099-01-04T09:00:00Z", ids...))
+
+	fired = fire("2099-01-06T20:00:00Z", ids...)
+	if assert.Len(t, fired, 2) {
+		assert.Equal(t, 10*time.Minute, fired[0].Before)
+		assert.True(t, fired[0].FireAt.Equal(time.Date(2099, 1, 5, 8, 50, 0, 0, time.UTC)))
+		assert.Equal(t, "Trip", fired[1].Text)
+		assert.True(t, fired[1].StartsAt.Equal(time.Date(2099, 1, 6, 15, 0, 0, 0, time.UTC)))
+	}
+
+	// Отмененное повторение 12 января пропускается.
+	fired = fire("2099-01-31T00:00:00Z", ids...)
+	if assert.Len(t, fired, 2) {
+		assert.Equal(t, "2099-01-19", fired[0].OccurrenceDate)
+		assert.Equal(t, 24*time.Hour, fired[0].Before)
+		assert.Equal(t, "2099-01-19", fired[1].OccurrenceDate)
+		assert.Equal(t, 10*time.Minute, fired[1].Before)
+	}
+	assert.Empty(t, fire("2100-01-01T00:00:00Z", ids...))
+
+	// Напоминание события в корзине ждет его восстановления.
+	trashedID := create(createEvent.Request{
+		Text: "Review", StartTime: "2099-03-01T10:00:00Z", EndTime: "2099-03-01T11:00:00Z", Reminders: []int{0},
+	})
+	body, _ = json.Marshal(deleteEvent.Request{EventId: trashedID})
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, fire("2099-03-02T00:00:00Z", trashedID))
+
+	body, _ = json.Marshal(restoreEvent.Request{EventId: trashedID})
+	resp = doRequestAs(t, userID, http.MethodPost, "/restore_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, fire("2099-03-02T00:00:00Z", trashedID), 1)
+
+	// Параллельные планировщики получают каждое напоминание ровно один раз.
+	sharedID := create(createEvent.Request{
+		Text: "Demo", StartTime: "2099-04-01T10:00:00Z", EndTime: "2099-04-01T11:00:00Z", Reminders: []int{0, 5, 30},
+	})
+	var mu sync.Mutex
+	var total int
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fired := fire("2099-04-01T10:00:00Z", sharedID)
+			mu.Lock()
+			total += len(fired)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 3, total)
+}
+
+// Тестируем вебхуки: изменения событий доставляются подписанными, в порядке изменений и только
+// на подписанные вебхуки; откаченный пакет ничего не доставляет, а вебхук, который не принимает
+// доставки, выключается и после включения получает отложенные доставки.
+func TestWebhooks(t *testing.T) {
+	userID := createTestUser(t)
+
+	type received struct {
+		path    string
+		header  http.Header
+		body    []byte
+		payload map[string]interface{}
+	}
+	var mu sync.Mutex
+	var requests []received
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]interface{}
+		_ = json.Unmarshal(body, &payload)
+
+		mu.Lock()
+		requests = append(requests, received{path: r.URL.Path, header: r.Header, body: body, payload: payload})
+		mu.Unlock()
+
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer receiver.Close()
+
+	// receivedAt возвращает запросы, пришедшие на path.
+	receivedAt := func(path string) []received {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var res []received
+		for _, r := range requests {
+			if r.path == path {
+				res = append(res, r)
+			}
+		}
+		return res
+	}
+
+	createWebhook := func(path, secret string, eventTypes ...string) int64 {
+		body, _ := json.Marshal(webhook.Request{URL: receiver.URL + path, Secret: secret, EventTypes: eventTypes})
+		resp := doRequestAs(t, userID, http.MethodPost, "/create_webhook", body)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var webhookResp webhook.Response
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&webhookResp))
+		return webhookResp.WebhookId
+	}
+
+	const secret = "all-changes-secret"
+	createWebhook("/all", secret)
+	downID := createWebhook("/down", "deletions-secret", models.WebhookEventDeleted)
+	createWebhook("/restored", "restorations-secret", models.WebhookEventRestored)
+
+	body, _ := json.Marshal(webhook.Request{URL: "not a url", Secret: secret})
+	resp := doRequestAs(t, userID, http.MethodPost, "/create_webhook", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	post := func(path string, req interface{}) {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, userID, http.MethodPost, path, body)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+
+	eventID := createTestEvent(t, userID, "2025-11-03", "Retro")
+	post("/update_event", updateEvent.Request{EventId: eventID, Date: "2025-11-04", Text: "Retro"})
+	post("/delete_event", deleteEvent.Request{EventId: eventID})
+	post("/restore_event", restoreEvent.Request{EventId: eventID})
+
+	// Откаченный пакет не оставляет доставок.
+	body, _ = json.Marshal(batch.Request{Operations: []batch.Operation{
+		{Op: "create", Date: "2025-11-05", Text: "Rolled back"},
+		{Op: "delete", EventId: eventID, ExpectedVersion: 100},
+	}})
+	resp = doRequestAs(t, userID, http.MethodPost, "/batch", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		webhooksender.Run(ctx, slog.New(slog.NewTextHandler(io.Discard, nil)), testDB, config.Webhooks{
+			PollInterval:         10 * time.Millisecond,
+			BatchSize:            100,
+			Timeout:              2 * time.Second,
+			MaxAttempts:          2,
+			RetryInterval:        10 * time.Millisecond,
+			MaxRetryInterval:     10 * time.Millisecond,
+			DisableAfter:         2,
+			AllowPrivateNetworks: true,
+		})
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	assert.Eventually(t, func() bool {
+		return len(receivedAt("/all")) >= 4 && len(receivedAt("/down")) >= 2 && len(receivedAt("/restored")) >= 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	all := receivedAt("/all")
+	if assert.Len(t, all, 4) {
+		for i, eventType := range []string{"event.created", "event.updated", "event.deleted", "event.restored"} {
+			r := all[i]
+			assert.Equal(t, eventType, r.header.Get(webhooksender.HeaderEvent))
+			assert.Equal(t, eventType, r.payload["type"])
+			assert.Equal(t, float64(eventID), r.payload["event_id"])
+			assert.Equal(t, float64(userID), r.payload["user_id"])
+			assert.NotEmpty(t, r.payload["changed_at"])
+			assert.True(t, webhooksender.Verify(secret, r.header.Get(webhooksender.HeaderTimestamp), r.body,
+				r.header.Get(webhooksender.HeaderSignature)), "signature of %s", eventType)
+			assert.False(t, webhooksender.Verify("wrong-secret", r.header.Get(webhooksender.HeaderTimestamp), r.body,
+				r.header.Get(webhooksender.HeaderSignature)))
+		}
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"field": "date", "old": "2025-11-03", "new": "2025-11-04"},
+		}, all[1].payload["changes"])
+	}
+	assert.Len(t, receivedAt("/restored"), 1)
+
+	// Доставка на /down провалилась после двух попыток и выключила вебхук.
+	listWebhooks := func() map[int64]webhook.WebhookResponse {
+		resp := doRequestAs(t, userID, http.MethodGet, "/webhooks", nil)
+		defer resp.Body.Close()
+
+		var listResp webhook.ListResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&listResp))
+		res := make(map[int64]webhook.WebhookResponse)
+		for _, w := range listResp.Webhooks {
+			res[w.WebhookId] = w
+		}
+		return res
+	}
+	assert.Eventually(t, func() bool {
+		return !listWebhooks()[downID].Active
+	}, 5*time.Second, 10*time.Millisecond)
+	down := listWebhooks()[downID]
+	assert.Equal(t, 2, down.Failures)
+	assert.NotEmpty(t, down.DisabledAt)
+	assert.Equal(t, []string{models.WebhookEventDeleted}, down.EventTypes)
+	assert.Len(t, receivedAt("/down"), 2)
+
+	deliveries := func(webhookID int64) webhook.DeliveriesResponse {
+		resp := doRequestAs(t, userID, http.MethodGet, fmt.Sprintf("/webhook_deliveries?webhook_id=%d", webhookID), nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var deliveriesResp webhook.DeliveriesResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&deliveriesResp))
+		return deliveriesResp
+	}
+	if downDeliveries := deliveries(downID).Deliveries; assert.Len(t, downDeliveries, 1) {
+		d := downDeliveries[0]
+		assert.Equal(t, "failed", d.Status)
+		assert.Equal(t, "event.deleted", d.EventType)
+		assert.Empty(t, d.NextAttemptAt)
+		if assert.Len(t, d.Attempts, 2) {
+			assert.Equal(t, http.StatusInternalServerError, d.Attempts[0].StatusCode)
+			assert.NotEmpty(t, d.Attempts[1].Error)
+		}
+	}
+
+	// Выключенный вебхук не получает изменений, пока его не включат.
+	post("/delete_event", deleteEvent.Request{EventId: createTestEvent(t, userID, "2025-11-06", "Cancelled")})
+	assert.Len(t, deliveries(downID).Deliveries, 1)
+
+	active := true
+	post("/update_webhook", webhook.UpdateRequest{WebhookId: downID, URL: receiver.URL + "/up", Active: &active})
+	up := listWebhooks()[downID]
+	assert.True(t, up.Active)
+	assert.Zero(t, up.Failures)
+	assert.Empty(t, up.DisabledAt)
+
+	post("/delete_event", deleteEvent.Request{EventId: createTestEvent(t, userID, "2025-11-07", "Postponed")})
+	assert.Eventually(t, func() bool {
+		return len(receivedAt("/up")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return deliveries(downID).Deliveries[0].Status == "delivered"
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Len(t, receivedAt("/down"), 2)
+
+	// Чужие вебхуки недоступны.
+	otherID := createTestUser(t)
+	resp = doRequestAs(t, otherID, http.MethodGet, fmt.Sprintf("/webhook_deliveries?webhook_id=%d", downID), nil)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	body, _ = json.Marshal(webhook.DeleteRequest{WebhookId: downID})
+	resp = doRequestAs(t, otherID, http.MethodPost, "/delete_webhook", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	post("/delete_webhook", webhook.DeleteRequest{WebhookId: downID})
+	_, ok := listWebhooks()[downID]
+	assert.False(t, ok)
+}
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+
+	// Отправляем записи, оставленные другими тестами.
+	for {
+		sent, err := testDB.RelayOutbox(ctx, 100, func(models.OutboxRecord) error { return nil })
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		if sent == 0 {
+			break
+		}
+	}
+
+	post := func(userID int64, path string, req interface{}) *http.Response {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, userID, http.MethodPost, path, body)
+		resp.Body.Close()
+		return resp
+	}
+
+	userID := createTestUser(t)
+	eventID := createTestEvent(t, userID, "2025-12-01", "Planning")
+	resp := post(userID, "/update_event", updateEvent.Request{EventId: eventID, Date: "2025-12-02", Text: "Planning"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Откаченный пакет не оставляет записей.
+	resp = post(userID, "/batch", batch.Request{Operations: []batch.Operation{
+		{Op: "create", Date: "2025-12-03", Text: "Rolled back"},
+		{Op: "delete", EventId: eventID, ExpectedVersion: 100},
+	}})
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	resp = post(userID, "/delete_event", deleteEvent.Request{EventId: eventID})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Запись, которую не принял получатель, и следующие за ней остаются в outbox.
+	var records []models.OutboxRecord
+	sent, err := testDB.RelayOutbox(ctx, 100, func(record models.OutboxRecord) error {
+		if len(records) == 2 {
+			return errors.New("broker is down")
+		}
+		records = append(records, record)
+		return nil
+	})
+	assert.Equal(t, 2, sent)
+	assert.EqualError(t, err, "broker is down")
+
+	publisher := &outbox.MemoryPublisher{}
+	relayCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		outbox.Run(relayCtx, slog.New(slog.NewTextHandler(io.Discard, nil)), testDB, publisher, config.Outbox{
+			PollInterval: 10 * time.Millisecond,
+			BatchSize:    100,
+		})
+	}()
+	assert.Eventually(t, func() bool {
+		return len(publisher.Records()) >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	records = append(records, publisher.Records()...)
+	if !assert.Len(t, records, 4) {
+		t.FailNow()
+	}
+	for i, want := range []struct {
+		aggregateType string
+		aggregateID   int64
+		eventType     string
+	}{
+		{models.AggregateUser, userID, models.OutboxUserCreated},
+		{models.AggregateEvent, eventID, models.WebhookEventCreated},
+		{models.AggregateEvent, eventID, models.WebhookEventUpdated},
+		{models.AggregateEvent, eventID, models.WebhookEventDeleted},
+	} {
+		assert.Equal(t, want.aggregateType, records[i].AggregateType)
+		assert.Equal(t, want.aggregateID, records[i].AggregateID)
+		assert.Equal(t, want.eventType, records[i].Type)
+		assert.False(t, records[i].CreatedAt.IsZero())
+		if i > 0 {
+			assert.Greater(t, records[i].ID, records[i-1].ID)
+		}
+	}
+
+	var userPayload map[string]interface{}
+	assert.NoError(t, json.Unmarshal(records[0].Payload, &userPayload))
+	assert.Equal(t, models.OutboxUserCreated, userPayload["type"])
+	assert.Equal(t, float64(userID), userPayload["user_id"])
+	assert.NotEmpty(t, userPayload["created_at"])
+
+	var updatePayload map[string]interface{}
+	assert.NoError(t, json.Unmarshal(records[2].Payload, &updatePayload))
+	assert.Equal(t, float64(eventID), updatePayload["event_id"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"field": "date", "old": "2025-12-01", "new": "2025-12-02"},
+	}, updatePayload["changes"])
+
+	// Опубликованные записи не публикуются снова.
+	sent, err = testDB.RelayOutbox(ctx, 100, func(record models.OutboxRecord) error {
+		t.Errorf("record %d published twice", record.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Zero(t, sent)
+}
+
+// Тестируем, что удаление пользователя сообщает через outbox об удалении его событий и его самого.
+func TestDeleteUserOutbox(t *testing.T) {
+	ctx := context.Background()
+	userID := createTestUser(t)
+	firstID := createTestEvent(t, userID, "2025-12-10", "Standup")
+	secondID := createTestEvent(t, userID, "2025-12-11", "Retro")
+	trashedID := createTestEvent(t, userID, "2025-12-12", "Old")
+
+	body, _ := json.Marshal(deleteEvent.Request{EventId: trashedID})
+	resp := doRequestAs(t, userID, http.MethodPost, "/delete_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Отправляем записи, оставленные до удаления пользователя.
+	for {
+		sent, err := testDB.RelayOutbox(ctx, 100, func(models.OutboxRecord) error { return nil })
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		if sent == 0 {
+			break
+		}
+	}
+
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_user", nil)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var records []models.OutboxRecord
+	_, err := testDB.RelayOutbox(ctx, 100, func(record models.OutboxRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	assert.NoError(t, err)
+	if !assert.Len(t, records, 3) {
+		t.FailNow()
+	}
+	for i, want := range []struct {
+		aggregateType string
+		aggregateID   int64
+		eventType     string
+	}{
+		{models.AggregateEvent, firstID, models.WebhookEventDeleted},
+		{models.AggregateEvent, secondID, models.WebhookEventDeleted},
+		{models.AggregateUser, userID, models.OutboxUserDeleted},
+	} {
+		assert.Equal(t, want.aggregateType, records[i].AggregateType)
+		assert.Equal(t, want.aggregateID, records[i].AggregateID)
+		assert.Equal(t, want.eventType, records[i].Type)
+		assert.False(t, records[i].CreatedAt.IsZero())
+	}
+
+	var eventPayload map[string]interface{}
+	assert.NoError(t, json.Unmarshal(records[0].Payload, &eventPayload))
+	assert.Equal(t, float64(userID), eventPayload["user_id"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"field": "date", "old": "2025-12-10"},
+		map[string]interface{}{"field": "text", "old": "Standup"},
+	}, eventPayload["changes"])
+
+	var userPayload map[string]interface{}
+	assert.NoError(t, json.Unmarshal(records[2].Payload, &userPayload))
+	assert.Equal(t, models.OutboxUserDeleted, userPayload["type"])
+	assert.Equal(t, float64(userID), userPayload["user_id"])
+	assert.NotEmpty(t, userPayload["deleted_at"])
+
+	// Повторное удаление не оставляет записей.
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_user", nil)
+	defer resp.Body.Close()
+	sent, err := testDB.RelayOutbox(ctx, 100, func(record models.OutboxRecord) error {
+		t.Errorf("unexpected outbox record %s", record.Type)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Zero(t, sent)
+}
+
+// Тестируем, что незавершенная транзакция, уже получившая id записи outbox, не блокирует другие
+// записи и релей, а записи с большими id ждут ее фиксации и публикуются после ее записи.
+func TestOutboxOpenTransaction(t *testing.T) {
+	if testStorageType() != config.StorageDatabase || testDatabaseDriver() != config.DriverPostgres {
+		t.Skip("only postgres allocates outbox ids before commit")
+	}
+	ctx := context.Background()
+
+	drain := func() []models.OutboxRecord {
+		var records []models.OutboxRecord
+		for {
+			sent, err := testDB.RelayOutbox(ctx, 100, func(record models.OutboxRecord) error {
+				records = append(records, record)
+				return nil
+			})
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+			if sent == 0 {
+				return records
+			}
+		}
+	}
+	drain()
+
+	db, err := sql.Open("postgres", fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		testDatabase.Host, testDatabase.Port, testDatabase.User, testDatabase.Password, testDatabase.DBName, testDatabase.SSLMode,
+	))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer tx.Rollback()
+
+	// Та же последовательность, что у записи в outbox из хранилища.
+	var txid, openID int64
+	err = tx.QueryRowContext(ctx, "SELECT txid_current(), nextval('outbox_id_seq')").Scan(&txid, &openID)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO outbox (id, aggregate_type, aggregate_id, type, payload, created_at, tx_horizon)
+         VALUES ($1, 'user', 0, 'test.open', '{}', now(), txid_snapshot_xmax(txid_current_snapshot()))`,
+		openID,
+	)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	userID := createTestUser(t)
+	eventID := createTestEvent(t, userID, "2025-12-01", "Written while another transaction is open")
+
+	// Релей не ждет открытую транзакцию, но и не публикует записи, перед которыми она может появиться.
+	relayCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	sent, err := testDB.RelayOutbox(relayCtx, 100, func(record models.OutboxRecord) error {
+		t.Errorf("record %d published before an earlier transaction committed", record.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Zero(t, sent)
+
+	if !assert.NoError(t, tx.Commit()) {
+		t.FailNow()
+	}
+
+	records := drain()
+	if !assert.Len(t, records, 3) {
+		t.FailNow()
+	}
+	assert.Equal(t, openID, records[0].ID)
+	assert.Equal(t, "test.open", records[0].Type)
+	assert.Equal(t, userID, records[1].AggregateID)
+	assert.Equal(t, models.OutboxUserCreated, records[1].Type)
+	assert.Equal(t, eventID, records[2].AggregateID)
+	assert.Equal(t, models.WebhookEventCreated, records[2].Type)
+}
+
+// Тестируем полнотекстовый поиск: все слова запроса, границы дат и выделение в сниппете.
+func TestSearchEvents(t *testing.T) {
+	userID := createTestUser(t)
+
+	createTestEvent(t, userID, "2025-02-10", "Dentist appointment")
+	createTestEvent(t, userID, "2025-03-14", "Call the dentist about the appointment")
+	createTestEvent(t, userID, "2025-03-20", "Team lunch")
+
+	search := func(req searchEvents.Request) []searchEvents.ResultResponse {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, userID, http.MethodGet, "/search_events", body)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var searchResp searchEvents.Response
+		err := json.NewDecoder(resp.Body).Decode(&searchResp)
+		assert.NoError(t, err)
+		return searchResp.Results
+	}
+
+	results := search(searchEvents.Request{Query: "DENTIST appointment"})
+	if assert.Len(t, results, 2) {
+		// Короткий текст, в котором совпадает большая часть слов, релевантнее.
+		assert.Equal(t, "2025-02-10", results[0].Date)
+		assert.Contains(t, results[0].Snippet, "<b>Dentist</b>")
+		assert.True(t, results[0].Rank >= results[1].Rank)
+	}
+
+	results = search(searchEvents.Request{Query: "dentist", From: "2025-03-01", To: "2025-03-31"})
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "Call the dentist about the appointment", results[0].Text)
+	}
+
+	assert.Empty(t, search(searchEvents.Request{Query: "dentist lunch"}))
+}
+
+// Тестируем корзину: удаленное событие скрыто из выборок, его можно восстановить, а очистка удаляет его навсегда.
+func TestTrash(t *testing.T) {
+	userID := createTestUser(t)
+
+	body, _ := json.Marshal(createEvent.Request{Date: "2025-07-01", Text: "Quarterly review", Tags: []string{"work"}})
+	resp := doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+	defer resp.Body.Close()
+
+	var eventResp createEvent.Response
+	err := json.NewDecoder(resp.Body).Decode(&eventResp)
+	if !assert.NoError(t, err) || !assert.True(t, eventResp.EventId > 0) {
+		t.FailNow()
+	}
+	eventID := eventResp.EventId
+	keptID := createTestEvent(t, userID, "2025-07-02", "Team lunch")
+
+	dayEvents := func(date string) []getEvents.EventResponse {
+		body, _ := json.Marshal(getEvents.Request{Date: date})
+		resp := doRequestAs(t, userID, http.MethodGet, "/events_for_day", body)
+		defer resp.Body.Close()
+
+		var eventsResp getEvents.Response
+		err := json.NewDecoder(resp.Body).Decode(&eventsResp)
+		assert.NoError(t, err)
+		return eventsResp.Events
+	}
+	trashEvents := func() []listTrash.EventResponse {
+		resp := doRequestAs(t, userID, http.MethodGet, "/trash", nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var trashResp listTrash.Response
+		err := json.NewDecoder(resp.Body).Decode(&trashResp)
+		assert.NoError(t, err)
+		return trashResp.Events
+	}
+	restore := func(id int64) int {
+		body, _ := json.Marshal(restoreEvent.Request{EventId: id})
+		resp := doRequestAs(t, userID, http.MethodPost, "/restore_event", body)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	deleteBody, _ := json.Marshal(deleteEvent.Request{EventId: eventID})
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_event", deleteBody)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Empty(t, dayEvents("2025-07-01"))
+
+	trashed := trashEvents()
+	if assert.Len(t, trashed, 1) {
+		assert.Equal(t, eventID, trashed[0].EventId)
+		assert.Equal(t, "Quarterly review", trashed[0].Text)
+		assert.Equal(t, []string{"work"}, trashed[0].Tags)
+		assert.NotEmpty(t, trashed[0].DeletedAt)
+	}
+
+	// Событие не в корзине восстановить нельзя.
+	assert.Equal(t, http.StatusConflict, restore(keptID))
+	assert.Equal(t, http.StatusOK, restore(eventID))
+	assert.Equal(t, http.StatusConflict, restore(eventID))
+
+	restored := dayEvents("2025-07-01")
+	if assert.Len(t, restored, 1) {
+		assert.Equal(t, []string{"work"}, restored[0].Tags)
+	}
+	assert.Empty(t, trashEvents())
+
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_event", deleteBody)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Событие, удаленное только что, не старше срока хранения и переживает очистку.
+	_, err = testDB.PurgeTrash(context.Background(), time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, trashEvents(), 1)
+
+	purged, err := testDB.PurgeTrash(context.Background(), time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, purged >= 1)
+	assert.Empty(t, trashEvents())
+	assert.Equal(t, http.StatusNotFound, restore(eventID))
+	assert.Len(t, dayEvents("2025-07-02"), 1)
+}
+
+// Тестируем историю изменений: создание, перенос повторения, удаление и восстановление.
+func TestEventHistory(t *testing.T) {
+	userID := createTestUser(t)
+
+	body, _ := json.Marshal(createEvent.Request{Date: "2025-08-04", Text: "Planning", Recurrence: "FREQ=WEEKLY;COUNT=4"})
+	resp := doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+	defer resp.Body.Close()
+
+	var eventResp createEvent.Response
+	err := json.NewDecoder(resp.Body).Decode(&eventResp)
+	if !assert.NoError(t, err) || !assert.True(t, eventResp.EventId > 0) {
+		t.FailNow()
+	}
+	eventID := eventResp.EventId
+
+	post := func(path string, req interface{}) {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, userID, http.MethodPost, path, body)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+
+	post("/update_event", updateEvent.Request{EventId: eventID, Scope: "this", OccurrenceDate: "2025-08-11",
+		Date: "2025-08-12", Text: "Planning"})
+	post("/update_event", updateEvent.Request{EventId: eventID, Date: "2025-08-04", Text: "Sprint planning",
+		Recurrence: "FREQ=WEEKLY;COUNT=4"})
+	post("/delete_event", deleteEvent.Request{EventId: eventID})
+	post("/restore_event", restoreEvent.Request{EventId: eventID})
+
+	resp = doRequestAs(t, userID, http.MethodGet, fmt.Sprintf("/event_history?event_id=%d", eventID), nil)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var historyResp eventHistory.Response
+	err = json.NewDecoder(resp.Body).Decode(&historyResp)
+	assert.NoError(t, err)
+
+	revisions := historyResp.Revisions
+	if !assert.Len(t, revisions, 5) {
+		t.FailNow()
+	}
+
+	for i, action := range []string{"create", "update", "update", "delete", "restore"} {
+		assert.Equal(t, action, revisions[i].Action)
+		assert.Equal(t, userID, revisions[i].UserId)
+		assert.NotEmpty(t, revisions[i].ChangedAt)
+	}
+	assert.Equal(t, "2025-08-11", revisions[1].OccurrenceDate)
+	assert.Equal(t, []eventHistory.ChangeResponse{{Field: "date", Old: "2025-08-11", New: "2025-08-12"}}, revisions[1].Changes)
+	assert.Equal(t, []eventHistory.ChangeResponse{{Field: "text", Old: "Planning", New: "Sprint planning"}}, revisions[2].Changes)
+	assert.Equal(t, []eventHistory.ChangeResponse{
+		{Field: "date", Old: "2025-08-04"},
+		{Field: "text", Old: "Sprint planning"},
+	}, revisions[3].Changes)
+
+	// Чужое событие не отдается.
+	otherID := createTestUser(t)
+	resp = doRequestAs(t, otherID, http.MethodGet, fmt.Sprintf("/event_history?event_id=%d", eventID), nil)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+// Тестируем оптимистичные блокировки: устаревшая версия в If-Match или expected_version дает 412.
+func TestEventVersionConflict(t *testing.T) {
+	userID := createTestUser(t)
+
+	body, _ := json.Marshal(createEvent.Request{Date: "2025-09-01", Text: "Retro"})
+	resp := doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+	defer resp.Body.Close()
+
+	var eventResp createEvent.Response
+	err := json.NewDecoder(resp.Body).Decode(&eventResp)
+	if !assert.NoError(t, err) || !assert.True(t, eventResp.EventId > 0) {
+		t.FailNow()
+	}
+	eventID := eventResp.EventId
+	assert.Equal(t, `"1"`, resp.Header.Get("ETag"))
+	assert.Equal(t, int64(1), eventResp.Version)
+
+	update := func(text, ifMatch string, expectedVersion int64) *http.Response {
+		body, _ := json.Marshal(updateEvent.Request{EventId: eventID, Date: "2025-09-01", Text: text,
+			ExpectedVersion: expectedVersion})
+		req, err := http.NewRequest(http.MethodPost, "http://"+testServerAddr+"/update_event", bytes.NewReader(body))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+apiKeyOf(userID))
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return resp
+	}
+
+	// Два клиента прочитали версию 1, первый успевает сохранить изменения.
+	resp = update("Retro with notes", `"1"`, 0)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `"2"`, resp.Header.Get("ETag"))
+
+	resp = update("Retro moved", `"1"`, 0)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	resp = update("Retro moved", "", 1)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	dayBody, _ := json.Marshal(getEvents.Request{Date: "2025-09-01"})
+	resp = doRequestAs(t, userID, http.MethodGet, "/events_for_day", dayBody)
+	defer resp.Body.Close()
+
+	var eventsResp getEvents.Response
+	err = json.NewDecoder(resp.Body).Decode(&eventsResp)
+	assert.NoError(t, err)
+	if assert.Len(t, eventsResp.Events, 1) {
+		assert.Equal(t, "Retro with notes", eventsResp.Events[0].Text)
+		assert.Equal(t, int64(2), eventsResp.Events[0].Version)
+		assert.Equal(t, `"2"`, eventsResp.Events[0].ETag)
+	}
+
+	deleteBody, _ := json.Marshal(deleteEvent.Request{EventId: eventID, ExpectedVersion: 1})
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_event", deleteBody)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	deleteBody, _ = json.Marshal(deleteEvent.Request{EventId: eventID, ExpectedVersion: 2})
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_event", deleteBody)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// Тестируем пакетные операции: атомарный пакет откатывается целиком, а с continue_on_error
+// откатываются только ошибочные операции.
+func TestBatch(t *testing.T) {
+	userID := createTestUser(t)
+
+	apply := func(req batch.Request) (int, batch.Response) {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, userID, http.MethodPost, "/batch", body)
+		defer resp.Body.Close()
+
+		var batchResp batch.Response
+		err := json.NewDecoder(resp.Body).Decode(&batchResp)
+		assert.NoError(t, err)
+		return resp.StatusCode, batchResp
+	}
+
+	dayTexts := func(date string) []string {
+		body, _ := json.Marshal(getEvents.Request{Date: date})
+		resp := doRequestAs(t, userID, http.MethodGet, "/events_for_day", body)
+		defer resp.Body.Close()
+
+		var eventsResp getEvents.Response
+		err := json.NewDecoder(resp.Body).Decode(&eventsResp)
+		assert.NoError(t, err)
+
+		var texts []string
+		for _, e := range eventsResp.Events {
+			texts = append(texts, e.Text)
+		}
+		sort.Strings(texts)
+		return texts
+	}
+
+	status, batchResp := apply(batch.Request{Operations: []batch.Operation{
+		{Op: "create", Date: "2025-10-01", Text: "Import A"},
+		{Op: "create", Date: "2025-10-01", Text: "Import B", Tags: []string{"import"}},
+	}})
+	assert.Equal(t, http.StatusOK, status)
+	if !assert.Len(t, batchResp.Results, 2) {
+		t.FailNow()
+	}
+	firstID, secondID := batchResp.Results[0].EventId, batchResp.Results[1].EventId
+	assert.True(t, firstID > 0 && secondID > 0 && firstID != secondID)
+	assert.Equal(t, int64(1), batchResp.Results[0].Version)
+	assert.Equal(t, []string{"Import A", "Import B"}, dayTexts("2025-10-01"))
+
+	// Устаревшая версия у второй операции откатывает и первую.
+	status, batchResp = apply(batch.Request{Operations: []batch.Operation{
+		{Op: "update", EventId: firstID, Date: "2025-10-01", Text: "Import A edited"},
+		{Op: "delete", EventId: secondID, ExpectedVersion: 5},
+		{Op: "create", Date: "2025-10-01", Text: "Import C"},
+	}})
+	assert.Equal(t, http.StatusPreconditionFailed, status)
+	assert.Equal(t, "Error", batchResp.Status)
+	var codes []int
+	for _, res := range batchResp.Results {
+		codes = append(codes, res.Code)
+	}
+	assert.Equal(t, []int{http.StatusFailedDependency, http.StatusPreconditionFailed, http.StatusFailedDependency}, codes)
+	assert.Equal(t, []string{"Import A", "Import B"}, dayTexts("2025-10-01"))
+
+	status, batchResp = apply(batch.Request{ContinueOnError: true, Operations: []batch.Operation{
+		{Op: "update", EventId: firstID, Date: "2025-10-01", Text: "Import A edited"},
+		{Op: "delete", EventId: secondID, ExpectedVersion: 5},
+		{Op: "delete", EventId: secondID, ExpectedVersion: 1},
+	}})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "OK", batchResp.Status)
+	codes = nil
+	for _, res := range batchResp.Results {
+		codes = append(codes, res.Code)
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusPreconditionFailed, http.StatusOK}, codes)
+	assert.Equal(t, int64(2), batchResp.Results[0].Version)
+	assert.Equal(t, []string{"Import A edited"}, dayTexts("2025-10-01"))
+
+	// В истории нет следов откаченных операций.
+	resp := doRequestAs(t, userID, http.MethodGet, fmt.Sprintf("/event_history?event_id=%d", firstID), nil)
+	defer resp.Body.Close()
+
+	var historyResp eventHistory.Response
+	err := json.NewDecoder(resp.Body).Decode(&historyResp)
+	assert.NoError(t, err)
+	assert.Len(t, historyResp.Revisions, 2)
+}
+
+// Тестируем постраничную выборку /events: страницы не теряют и не повторяют события, в том числе
+// повторения серии, перенесенные на день другого ее повторения.
+func TestListEvents(t *testing.T) {
+	userID := createTestUser(t)
+
+	create := func(req createEvent.Request) int64 {
+		body, _ := json.Marshal(req)
+		resp := doRequestAs(t, userID, http.MethodPost, "/create_event", body)
+		defer resp.Body.Close()
+
+		var eventResp createEvent.Response
+		err := json.NewDecoder(resp.Body).Decode(&eventResp)
+		if !assert.NoError(t, err) || !assert.True(t, eventResp.EventId > 0) {
+			t.FailNow()
+		}
+		return eventResp.EventId
+	}
+
+	create(createEvent.Request{Date: "2025-03-01", Text: "A"})
+	seriesID := create(createEvent.Request{Date: "2025-03-03", Text: "Weekly", Recurrence: "FREQ=WEEKLY;COUNT=3"})
+	create(createEvent.Request{Date: "2025-03-17", Text: "B"})
+	create(createEvent.Request{Date: "2025-03-31", Text: "C"})
+	create(createEvent.Request{Date: "2025-04-01", Text: "Outside"})
+	trashedID := create(createEvent.Request{Date: "2025-03-05", Text: "Trashed"})
+
+	body, _ := json.Marshal(updateEvent.Request{EventId: seriesID, Scope: "this", OccurrenceDate: "2025-03-10",
+		Date: "2025-03-17", Text: "Moved"})
+	resp := doRequestAs(t, userID, http.MethodPost, "/update_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, _ = json.Marshal(deleteEvent.Request{EventId: trashedID})
+	resp = doRequestAs(t, userID, http.MethodPost, "/delete_event", body)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var texts []string
+	next := ""
+	for pages := 0; ; pages++ {
+		if !assert.Less(t, pages, 10, "pagination does not stop") {
+			t.FailNow()
+		}
+
+		url := "/events?from=2025-03-01&to=2025-03-31&limit=2&cursor=" + next
+		resp := doRequestAs(t, userID, http.MethodGet, url, nil)
+		defer resp.Body.Close()
+		if !assert.Equal(t, http.StatusOK, resp.StatusCode) {
+			t.FailNow()
+		}
+
+		var listResp getEvents.ListResponse
+		err := json.NewDecoder(resp.Body).Decode(&listResp)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(listResp.Events), 2)
+
+		for _, e := range listResp.Events {
+			texts = append(texts, e.Date+" "+e.Text)
+		}
+		if listResp.NextCursor == "" {
+			break
+		}
+		next = listResp.NextCursor
+	}
+
+	assert.Equal(t, []string{
+		"2025-03-01 A",
+		"2025-03-03 Weekly",
+		"2025-03-17 Moved",
+		"2025-03-17 Weekly",
+		"2025-03-17 B",
+		"2025-03-31 C",
+	}, texts)
+
+	resp = doRequestAs(t, userID, http.MethodGet, "/events?from=2025-03-01&to=2025-03-31&cursor=bogus", nil)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+// Тестируем, что хранилище не выполняет операции с отмененным контекстом или истекшим сроком.
+func TestStorageContextDone(t *testing.T) {
+	userID := createTestUser(t)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithTimeout(context.Background(), 0)
+	defer cancelExpired()
+
+	for ctx, want := range map[context.Context]error{canceled: storage.ErrCanceled, expired: storage.ErrTimeout} {
+		_, err := testDB.SaveEvent(ctx, models.Event{UserID: userID, Date: "2025-11-01", Text: "Never saved"})
+		assert.ErrorIs(t, storage.ContextError(ctx, err), want)
+
+		_, err = testDB.GetEventsByDay(ctx, userID, "2025-11-01", models.EventFilter{})
+		assert.ErrorIs(t, storage.ContextError(ctx, err), want)
+	}
+
+	events, err := testDB.GetEventsByDay(context.Background(), userID, "2025-11-01", models.EventFilter{})
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}